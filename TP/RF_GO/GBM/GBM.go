@@ -0,0 +1,293 @@
+// Package GBM implementa gradient boosting con árboles de regresión poco profundos.
+// Cada ronda ajusta un árbol (uno por clase en multiclase) a los gradientes de la pérdida
+// y lo suma al modelo. Usa los mismos datos (`RF.LoadCSV`, `RF.SplitDataset`), esquemas,
+// métricas y formato JSON que el paquete `RF`.
+package GBM
+
+import (
+	"errors"    // Para los errores de configuración
+	"fmt"       // Para mostrar el progreso del entrenamiento
+	"math"      // Para la función logística y el logaritmo de la pérdida
+	"math/rand" // Para el submuestreo de filas
+	"runtime"   // Para elegir la cantidad de goroutines
+	"sort"      // Para ordenar las clases
+	"time"      // Para la semilla por defecto y el progreso
+
+	"tp-test/RF" // Esquema, métricas y serialización compartidos
+)
+
+// Pérdidas disponibles.
+const LOG_LOSS = "logloss"      // Clasificación binaria o multiclase (entropía cruzada)
+const SQUARED_ERROR = "squared" // Regresión (error cuadrático)
+
+// Estructura `Config` con los parámetros de entrenamiento.
+type Config struct {
+	Rounds         int     // Cantidad máxima de rondas
+	LearningRate   float64 // Factor que multiplica la salida de cada árbol
+	MaxDepth       int     // Profundidad máxima de cada árbol
+	MinSamplesLeaf int     // Mínimo de filas en cada hoja
+	Lambda         float64 // Regularización L2 de las salidas de las hojas
+	Subsample      float64 // Fracción de filas (sin reemplazo) usada en cada ronda
+	Workers        int     // Goroutines para buscar divisiones (una por CPU si es 0)
+	Seed           int64   // Semilla aleatoria; si es 0 se usa la hora actual
+	Quiet          bool    // Si es true no se imprime el progreso
+	Schema         *RF.Schema
+
+	// Conjunto de validación para la parada temprana: se detiene si la pérdida de validación
+	// no mejora en `Patience` rondas y se conservan los árboles hasta la mejor ronda.
+	ValidationInputs  [][]interface{}
+	ValidationLabels  []string  // Para `Train`
+	ValidationTargets []float64 // Para `TrainRegression`
+	Patience          int
+}
+
+// `DefaultConfig` devuelve una configuración razonable para empezar.
+func DefaultConfig() Config {
+	return Config{
+		Rounds:         100,
+		LearningRate:   0.1,
+		MaxDepth:       3,
+		MinSamplesLeaf: 10,
+		Lambda:         1,
+		Subsample:      1,
+		Patience:       10,
+	}
+}
+
+// Estructura `Model` con un modelo entrenado. La puntuación de cada salida es `Base` más la
+// suma de los árboles; en clasificación binaria hay una salida (logit de `Classes[1]`), en
+// multiclase una por clase (softmax) y en regresión una (el valor predicho).
+type Model struct {
+	Loss           string
+	Classes        []string   `json:",omitempty"`
+	Base           []float64  // Puntuación inicial de cada salida
+	Trees          [][]*Node  // `Trees[ronda][salida]`
+	Schema         *RF.Schema `json:",omitempty"`
+	TrainLoss      []float64  `json:",omitempty"` // Pérdida de entrenamiento por ronda
+	ValidationLoss []float64  `json:",omitempty"` // Pérdida de validación por ronda
+}
+
+// `Train` entrena un clasificador con pérdida logarítmica sobre `inputs` y `labels`.
+func Train(inputs [][]interface{}, labels []string, config Config) (*Model, error) {
+	if len(inputs) == 0 || len(inputs) != len(labels) {
+		return nil, errors.New("gbm: inputs and labels must be non-empty and of the same length")
+	}
+	classes := make(map[string]bool)
+	for _, label := range labels {
+		classes[label] = true
+	}
+	model := &Model{Loss: LOG_LOSS}
+	for label := range classes {
+		model.Classes = append(model.Classes, label)
+	}
+	sort.Strings(model.Classes)
+	if len(model.Classes) < 2 {
+		return nil, errors.New("gbm: training labels have a single class")
+	}
+
+	// Cada etiqueta se codifica como su índice en `Classes`.
+	index := make(map[string]int, len(model.Classes))
+	for k, label := range model.Classes {
+		index[label] = k
+	}
+	encode := func(labels []string) ([]int, error) {
+		encoded := make([]int, len(labels))
+		for i, label := range labels {
+			k, ok := index[label]
+			if !ok {
+				return nil, fmt.Errorf("gbm: validation label %q was not seen in training", label)
+			}
+			encoded[i] = k
+		}
+		return encoded, nil
+	}
+	targets, _ := encode(labels)
+	validation, err := encode(config.ValidationLabels)
+	if err != nil {
+		return nil, err
+	}
+	if len(validation) != len(config.ValidationInputs) {
+		return nil, errors.New("gbm: validation inputs and labels have different lengths")
+	}
+
+	loss := newLogLoss(len(model.Classes), targets, validation)
+	return model, model.fit(inputs, config, loss)
+}
+
+// `TrainRegression` entrena un modelo de regresión con error cuadrático.
+func TrainRegression(inputs [][]interface{}, targets []float64, config Config) (*Model, error) {
+	if len(inputs) == 0 || len(inputs) != len(targets) {
+		return nil, errors.New("gbm: inputs and targets must be non-empty and of the same length")
+	}
+	if len(config.ValidationTargets) != len(config.ValidationInputs) {
+		return nil, errors.New("gbm: validation inputs and targets have different lengths")
+	}
+	model := &Model{Loss: SQUARED_ERROR}
+	return model, model.fit(inputs, config, &squaredError{targets: targets, validation: config.ValidationTargets})
+}
+
+// Bucle de boosting común a ambas pérdidas.
+func (self *Model) fit(inputs [][]interface{}, config Config, loss lossFunction) error {
+	if config.Rounds <= 0 || config.LearningRate <= 0 || config.MaxDepth <= 0 {
+		return errors.New("gbm: Rounds, LearningRate and MaxDepth must be positive")
+	}
+	if config.MinSamplesLeaf <= 0 {
+		config.MinSamplesLeaf = 1
+	}
+	if config.Subsample <= 0 {
+		config.Subsample = 1
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	outputs := loss.outputs()
+	self.Base = loss.base()
+	scores := newScores(len(inputs), self.Base)
+	validation_scores := newScores(len(config.ValidationInputs), self.Base)
+
+	builder := newTreeBuilder(inputs, &config)
+	grad := newScores(len(inputs), make([]float64, outputs))
+	hess := newScores(len(inputs), make([]float64, outputs))
+
+	best_loss := math.Inf(1)
+	best_round := 0
+	for round := 0; round < config.Rounds; round++ {
+		rows := subsample(len(inputs), config.Subsample, rng)
+		trees := make([]*Node, outputs)
+		loss.gradients(scores, grad, hess)
+		for k := 0; k < outputs; k++ {
+			builder.grad, builder.hess = grad[k], hess[k]
+			trees[k] = builder.build(rows, 0)
+		}
+		// Se actualizan las puntuaciones después de ajustar todas las salidas de la ronda.
+		for k, tree := range trees {
+			addTree(scores, inputs, tree, k)
+			addTree(validation_scores, config.ValidationInputs, tree, k)
+		}
+		self.Trees = append(self.Trees, trees)
+		self.TrainLoss = append(self.TrainLoss, loss.loss(scores, false))
+
+		if len(config.ValidationInputs) == 0 {
+			if !config.Quiet {
+				fmt.Printf("%v round %d: train loss %.5f\n", time.Now(), round+1, self.TrainLoss[round])
+			}
+			continue
+		}
+		validation_loss := loss.loss(validation_scores, true)
+		self.ValidationLoss = append(self.ValidationLoss, validation_loss)
+		if !config.Quiet {
+			fmt.Printf("%v round %d: train loss %.5f, validation loss %.5f\n", time.Now(), round+1, self.TrainLoss[round], validation_loss)
+		}
+		if validation_loss < best_loss {
+			best_loss = validation_loss
+			best_round = round + 1
+		} else if config.Patience > 0 && round+1-best_round >= config.Patience {
+			if !config.Quiet {
+				fmt.Printf("early stopping: best round %d (validation loss %.5f)\n", best_round, best_loss)
+			}
+			break
+		}
+	}
+	if best_round > 0 {
+		self.Trees = self.Trees[:best_round]
+	}
+
+	if config.Schema != nil {
+		self.Schema = config.Schema.Observe(inputs)
+	}
+	return nil
+}
+
+// Crea las puntuaciones de `n` filas inicializadas en `base`.
+func newScores(n int, base []float64) [][]float64 {
+	scores := make([][]float64, len(base))
+	for k := range scores {
+		scores[k] = make([]float64, n)
+		for i := range scores[k] {
+			scores[k][i] = base[k]
+		}
+	}
+	return scores
+}
+
+// Suma la salida de `tree` a la puntuación `k` de cada fila.
+func addTree(scores [][]float64, inputs [][]interface{}, tree *Node, k int) {
+	for i, input := range inputs {
+		scores[k][i] += tree.Predict(input)
+	}
+}
+
+// `Scores` devuelve la puntuación de cada salida para `input`.
+func (self *Model) Scores(input []interface{}) []float64 {
+	scores := append([]float64(nil), self.Base...)
+	for _, trees := range self.Trees {
+		for k, tree := range trees {
+			scores[k] += tree.Predict(input)
+		}
+	}
+	return scores
+}
+
+// `Probabilities` devuelve la probabilidad de cada clase para `input` (solo clasificación).
+func (self *Model) Probabilities(input []interface{}) map[string]float64 {
+	scores := self.Scores(input)
+	probs := make(map[string]float64, len(self.Classes))
+	if len(self.Classes) == 2 {
+		p := sigmoid(scores[0])
+		probs[self.Classes[0]] = 1 - p
+		probs[self.Classes[1]] = p
+		return probs
+	}
+	for k, p := range softmax(scores) {
+		probs[self.Classes[k]] = p
+	}
+	return probs
+}
+
+// `PredicateProba` devuelve la clase más probable para `input` y su probabilidad.
+func (self *Model) PredicateProba(input []interface{}) (string, float64) {
+	best := ""
+	best_p := -1.0
+	probs := self.Probabilities(input)
+	for _, label := range self.Classes {
+		if probs[label] > best_p {
+			best = label
+			best_p = probs[label]
+		}
+	}
+	return best, best_p
+}
+
+// `Predicate` devuelve la clase más probable para `input`; junto con `RF.Evaluate` permite
+// medir un modelo de GBM igual que un bosque.
+func (self *Model) Predicate(input []interface{}) string {
+	label, _ := self.PredicateProba(input)
+	return label
+}
+
+// `PredictValue` devuelve el valor predicho para `input` (solo regresión).
+func (self *Model) PredictValue(input []interface{}) float64 {
+	return self.Scores(input)[0]
+}
+
+// `DumpModel` guarda el modelo en un archivo JSON.
+func DumpModel(model *Model, fileName string) error {
+	return RF.WriteJSON(fileName, model)
+}
+
+// `ReadModel` carga un modelo guardado con `DumpModel`.
+func ReadModel(fileName string) (*Model, error) {
+	model := &Model{}
+	if err := RF.ReadJSON(fileName, model); err != nil {
+		return nil, err
+	}
+	if model.Loss != LOG_LOSS && model.Loss != SQUARED_ERROR {
+		return nil, fmt.Errorf("%s: unknown loss %q", fileName, model.Loss)
+	}
+	return model, nil
+}
@@ -0,0 +1,207 @@
+package GBM
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"tp-test/RF"
+)
+
+// Carga los primeros `n` registros de diabetes.csv con `RF.DIABETES_SCHEMA`.
+func diabetes(t *testing.T, n int) ([][]interface{}, []string, *RF.Schema) {
+	schema, err := RF.ParseSchema(RF.DIABETES_SCHEMA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs, labels, schema, err := RF.LoadCSV("../diabetes.csv", "diabetes", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inputs[:n], labels[:n], schema
+}
+
+// Comprueba que la pérdida de entrenamiento no aumente de una ronda a la siguiente.
+func checkDecreasing(t *testing.T, losses []float64) {
+	for round := 1; round < len(losses); round++ {
+		if losses[round] > losses[round-1]+1e-12 {
+			t.Fatalf("train loss increased at round %d: %v -> %v", round+1, losses[round-1], losses[round])
+		}
+	}
+	if losses[len(losses)-1] >= losses[0] {
+		t.Fatalf("train loss did not decrease: %v -> %v", losses[0], losses[len(losses)-1])
+	}
+}
+
+func TestSquaredErrorLossDecreases(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	inputs := make([][]interface{}, 1000)
+	targets := make([]float64, len(inputs))
+	for i := range inputs {
+		x := rng.Float64() * 10
+		group := []string{"a", "b"}[rng.Intn(2)]
+		targets[i] = 3*x + rng.NormFloat64()
+		if group == "b" {
+			targets[i] += 5
+		}
+		inputs[i] = []interface{}{x, group}
+	}
+	config := DefaultConfig()
+	config.Rounds = 50
+	config.Seed = 1
+	config.Quiet = true
+	model, err := TrainRegression(inputs, targets, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDecreasing(t, model.TrainLoss)
+	if mse := model.TrainLoss[len(model.TrainLoss)-1]; mse > 2 {
+		t.Fatalf("train mse %v, expected close to the noise variance", mse)
+	}
+	if value := model.PredictValue([]interface{}{5.0, "b"}); math.Abs(value-20) > 1.5 {
+		t.Fatalf("predicted %v for x=5 in group b, expected about 20", value)
+	}
+}
+
+func TestLogLossDecreases(t *testing.T) {
+	inputs, labels, schema := diabetes(t, 5000)
+	config := DefaultConfig()
+	config.Rounds = 30
+	config.Seed = 2
+	config.Quiet = true
+	config.Schema = schema
+	model, err := Train(inputs, labels, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkDecreasing(t, model.TrainLoss)
+	if accuracy := RF.Evaluate(model, inputs, labels, 0).Accuracy(); accuracy < 0.95 {
+		t.Fatalf("train accuracy %.4f", accuracy)
+	}
+}
+
+// En multiclase las probabilidades de softmax suman 1 y la pérdida baja en cada ronda.
+func TestMulticlassProbabilities(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	inputs := make([][]interface{}, 1500)
+	labels := make([]string, len(inputs))
+	for i := range inputs {
+		x, y := rng.Float64(), rng.Float64()
+		inputs[i] = []interface{}{x, y}
+		switch {
+		case x < 0.4:
+			labels[i] = "low"
+		case y < 0.5:
+			labels[i] = "mid"
+		default:
+			labels[i] = "high"
+		}
+	}
+	config := DefaultConfig()
+	config.Rounds = 20
+	config.Seed = 3
+	config.Quiet = true
+	model, err := Train(inputs, labels, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(model.Base) != 3 || len(model.Trees[0]) != 3 {
+		t.Fatalf("expected one output per class, got %d", len(model.Base))
+	}
+	checkDecreasing(t, model.TrainLoss)
+	for _, input := range inputs[:200] {
+		total := 0.0
+		for _, p := range model.Probabilities(input) {
+			if p < 0 || p > 1 {
+				t.Fatalf("probability %v out of range", p)
+			}
+			total += p
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Fatalf("probabilities of %v add up to %v", input, total)
+		}
+	}
+	if accuracy := RF.Evaluate(model, inputs, labels, 0).Accuracy(); accuracy < 0.95 {
+		t.Fatalf("train accuracy %.4f", accuracy)
+	}
+}
+
+// Con etiquetas al azar la pérdida de validación empeora enseguida: el entrenamiento se
+// detiene `Patience` rondas después de la mejor y conserva los árboles hasta ella.
+func TestEarlyStopping(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	inputs := make([][]interface{}, 1200)
+	labels := make([]string, len(inputs))
+	for i := range inputs {
+		inputs[i] = []interface{}{rng.Float64(), rng.Float64()}
+		labels[i] = []string{"0", "1"}[rng.Intn(2)]
+	}
+	config := DefaultConfig()
+	config.Rounds = 200
+	config.LearningRate = 0.5
+	config.MaxDepth = 6
+	config.MinSamplesLeaf = 1
+	config.Patience = 5
+	config.Seed = 4
+	config.Quiet = true
+	config.ValidationInputs, config.ValidationLabels = inputs[1000:], labels[1000:]
+	model, err := Train(inputs[:1000], labels[:1000], config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	best := 0
+	for round, loss := range model.ValidationLoss {
+		if loss < model.ValidationLoss[best] {
+			best = round
+		}
+	}
+	if len(model.ValidationLoss) == config.Rounds {
+		t.Fatal("training did not stop early")
+	}
+	if len(model.ValidationLoss) != best+1+config.Patience {
+		t.Fatalf("stopped after %d rounds, expected %d (best round %d)", len(model.ValidationLoss), best+1+config.Patience, best+1)
+	}
+	if len(model.Trees) != best+1 {
+		t.Fatalf("kept %d rounds, expected the best round %d", len(model.Trees), best+1)
+	}
+}
+
+// Un modelo guardado y vuelto a cargar predice exactamente lo mismo.
+func TestModelJSONRoundTrip(t *testing.T) {
+	inputs, labels, schema := diabetes(t, 3000)
+	config := DefaultConfig()
+	config.Rounds = 10
+	config.Seed = 5
+	config.Quiet = true
+	config.Schema = schema
+	model, err := Train(inputs, labels, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(t.TempDir(), "gbm.json")
+	if err := DumpModel(model, fileName); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadModel(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Classes, model.Classes) || !reflect.DeepEqual(loaded.Schema, model.Schema) {
+		t.Fatal("classes or schema changed after loading")
+	}
+	for i, input := range inputs {
+		if !reflect.DeepEqual(loaded.Probabilities(input), model.Probabilities(input)) {
+			t.Fatalf("record %d: loaded %v, saved %v", i, loaded.Probabilities(input), model.Probabilities(input))
+		}
+	}
+
+	if err := RF.WriteJSON(fileName, map[string]string{"Loss": "hinge"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadModel(fileName); err == nil {
+		t.Fatal("expected an error for an unknown loss")
+	}
+}
@@ -0,0 +1,178 @@
+package GBM
+
+import (
+	"math" // Para la función logística, exponenciales y logaritmos
+)
+
+// Hessiana mínima, para que las hojas con probabilidades saturadas no dividan por cero.
+const minHessian = 1e-6
+
+// Interfaz `lossFunction` de una pérdida: sus salidas, la puntuación inicial, los gradientes
+// respecto de todas las salidas (`grad[k][i]`, `hess[k][i]`) y el valor medio de la pérdida.
+type lossFunction interface {
+	outputs() int
+	base() []float64
+	gradients(scores [][]float64, grad, hess [][]float64)
+	loss(scores [][]float64, validation bool) float64
+}
+
+// Pérdida logarítmica. Con dos clases usa una salida (logit de la clase 1) y con más,
+// una salida por clase combinadas con softmax.
+type logLoss struct {
+	classes    int
+	targets    []int // Índice de la clase de cada fila de entrenamiento
+	validation []int // Índice de la clase de cada fila de validación
+}
+
+func newLogLoss(classes int, targets, validation []int) *logLoss {
+	return &logLoss{classes: classes, targets: targets, validation: validation}
+}
+
+func (self *logLoss) outputs() int {
+	if self.classes == 2 {
+		return 1
+	}
+	return self.classes
+}
+
+// Puntuación inicial: el logaritmo de la proporción de cada clase.
+func (self *logLoss) base() []float64 {
+	counts := make([]float64, self.classes)
+	for _, y := range self.targets {
+		counts[y] += 1
+	}
+	n := float64(len(self.targets))
+	if self.classes == 2 {
+		p := math.Min(math.Max(counts[1]/n, 1e-6), 1-1e-6)
+		return []float64{math.Log(p / (1 - p))}
+	}
+	base := make([]float64, self.classes)
+	for k := range base {
+		base[k] = math.Log(math.Max(counts[k]/n, 1e-6))
+	}
+	return base
+}
+
+// Gradiente p - y y hessiana p (1 - p) de cada fila respecto de cada salida. En multiclase
+// el softmax de cada fila se calcula una sola vez para todas las salidas.
+func (self *logLoss) gradients(scores [][]float64, grad, hess [][]float64) {
+	row := make([]float64, len(scores))
+	for i, y := range self.targets {
+		if self.classes == 2 {
+			p := sigmoid(scores[0][i])
+			target := 0.0
+			if y == 1 {
+				target = 1
+			}
+			grad[0][i] = p - target
+			hess[0][i] = math.Max(p*(1-p), minHessian)
+			continue
+		}
+		for j := range scores {
+			row[j] = scores[j][i]
+		}
+		for k, p := range softmax(row) {
+			target := 0.0
+			if y == k {
+				target = 1
+			}
+			grad[k][i] = p - target
+			hess[k][i] = math.Max(p*(1-p), minHessian)
+		}
+	}
+}
+
+// Entropía cruzada media de las filas de entrenamiento o de validación.
+func (self *logLoss) loss(scores [][]float64, validation bool) float64 {
+	targets := self.targets
+	if validation {
+		targets = self.validation
+	}
+	if len(targets) == 0 {
+		return 0
+	}
+	total := 0.0
+	row := make([]float64, len(scores))
+	for i, y := range targets {
+		var p float64
+		if self.classes == 2 {
+			p = sigmoid(scores[0][i])
+			if y == 0 {
+				p = 1 - p
+			}
+		} else {
+			for j := range scores {
+				row[j] = scores[j][i]
+			}
+			p = softmax(row)[y]
+		}
+		total -= math.Log(math.Max(p, 1e-15))
+	}
+	return total / float64(len(targets))
+}
+
+// Error cuadrático: una salida, gradiente igual al residuo y hessiana 1.
+type squaredError struct {
+	targets    []float64
+	validation []float64
+}
+
+func (self *squaredError) outputs() int {
+	return 1
+}
+
+// Puntuación inicial: la media de los valores de entrenamiento.
+func (self *squaredError) base() []float64 {
+	mean := 0.0
+	for _, y := range self.targets {
+		mean += y
+	}
+	return []float64{mean / float64(len(self.targets))}
+}
+
+func (self *squaredError) gradients(scores [][]float64, grad, hess [][]float64) {
+	for i, y := range self.targets {
+		grad[0][i] = scores[0][i] - y
+		hess[0][i] = 1
+	}
+}
+
+// Error cuadrático medio de las filas de entrenamiento o de validación.
+func (self *squaredError) loss(scores [][]float64, validation bool) float64 {
+	targets := self.targets
+	if validation {
+		targets = self.validation
+	}
+	if len(targets) == 0 {
+		return 0
+	}
+	total := 0.0
+	for i, y := range targets {
+		diff := scores[0][i] - y
+		total += diff * diff
+	}
+	return total / float64(len(targets))
+}
+
+// Función logística.
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// Softmax numéricamente estable.
+func softmax(scores []float64) []float64 {
+	max := math.Inf(-1)
+	for _, s := range scores {
+		max = math.Max(max, s)
+	}
+	probs := make([]float64, len(scores))
+	total := 0.0
+	for k, s := range scores {
+		probs[k] = math.Exp(s - max)
+		total += probs[k]
+	}
+	for k := range probs {
+		probs[k] /= total
+	}
+	return probs
+}
@@ -0,0 +1,335 @@
+package GBM
+
+import (
+	"math/rand" // Para el submuestreo de filas
+	"sort"      // Para ordenar valores y categorías al buscar divisiones
+	"sync"      // Para evaluar las columnas en paralelo
+
+	"tp-test/RF" // Condición de división compartida con los árboles del bosque
+)
+
+// Estructura `Node` de un árbol de regresión. Usa el mismo formato de división que
+// `RF.TreeNode` (umbral numérico en `Value` o subconjunto de categorías en `Categories`);
+// las hojas guardan en `Output` el valor que suman a la puntuación, ya multiplicado
+// por la tasa de aprendizaje.
+type Node struct {
+	ColumnNo   int
+	Value      interface{} `json:",omitempty"`
+	Categories []string    `json:",omitempty"`
+	Left       *Node       `json:",omitempty"`
+	Right      *Node       `json:",omitempty"`
+	Output     float64
+}
+
+// `Predict` devuelve la salida de la hoja que alcanza `input`. Los valores de tipo distinto
+// al de la división y las categorías no vistas siguen la rama derecha.
+func (self *Node) Predict(input []interface{}) float64 {
+	node := self
+	for node.Left != nil {
+		if RF.SplitGoesLeft(input[node.ColumnNo], node.Value, node.Categories) {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+	}
+	return node.Output
+}
+
+// Mejor división encontrada para una columna.
+type split struct {
+	gain       float64
+	column     int
+	value      interface{}
+	categories []string
+}
+
+// Estado compartido mientras se construye un árbol sobre los gradientes de una ronda.
+type treeBuilder struct {
+	inputs  [][]interface{}
+	numeric []bool      // Si cada columna es numérica
+	values  [][]float64 // Valores de las columnas numéricas, por columna
+	order   [][]int     // Filas ordenadas por valor, por columna numérica (se ordena una sola vez)
+	codes   [][]int32   // Código de la categoría de cada fila, por columna categórica
+	names   [][]string  // Categoría de cada código, por columna categórica
+	member  []bool      // Marca temporal: filas de la ronda al construir la raíz, o de la rama izquierda al dividir
+	grad    []float64   // Gradiente de la pérdida para cada fila, de la salida del árbol actual
+	hess    []float64   // Hessiana (segunda derivada) para cada fila, de la salida del árbol actual
+	config  *Config
+}
+
+// Crea el constructor de árboles para `inputs`: detecta el tipo de cada columna a partir
+// de la primera fila y ordena una única vez las filas de cada columna numérica.
+func newTreeBuilder(inputs [][]interface{}, config *Config) *treeBuilder {
+	columns := len(inputs[0])
+	self := &treeBuilder{
+		inputs:  inputs,
+		numeric: make([]bool, columns),
+		values:  make([][]float64, columns),
+		order:   make([][]int, columns),
+		codes:   make([][]int32, columns),
+		names:   make([][]string, columns),
+		member:  make([]bool, len(inputs)),
+		config:  config,
+	}
+	for c := 0; c < columns; c++ {
+		if _, self.numeric[c] = inputs[0][c].(float64); !self.numeric[c] {
+			codes := make([]int32, len(inputs))
+			index := make(map[string]int32)
+			for i, input := range inputs {
+				category, _ := input[c].(string)
+				code, ok := index[category]
+				if !ok {
+					code = int32(len(self.names[c]))
+					index[category] = code
+					self.names[c] = append(self.names[c], category)
+				}
+				codes[i] = code
+			}
+			self.codes[c] = codes
+			continue
+		}
+		values := make([]float64, len(inputs))
+		order := make([]int, len(inputs))
+		for i, input := range inputs {
+			values[i], _ = input[c].(float64)
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+		self.values[c] = values
+		self.order[c] = order
+	}
+	return self
+}
+
+// Construye un árbol de regresión de profundidad `config.MaxDepth` sobre las filas `rows`.
+// Filtra una única vez el orden precalculado de cada columna numérica; luego cada nodo
+// reparte esas listas entre sus hijos, así que cada nivel del árbol cuesta O(N) por columna.
+func (self *treeBuilder) build(rows []int, depth int) *Node {
+	for _, i := range rows {
+		self.member[i] = true
+	}
+	sorted := make([][]int, len(self.numeric))
+	for c, order := range self.order {
+		if !self.numeric[c] {
+			continue
+		}
+		sorted[c] = make([]int, 0, len(rows))
+		for _, i := range order {
+			if self.member[i] {
+				sorted[c] = append(sorted[c], i)
+			}
+		}
+	}
+	for _, i := range rows {
+		self.member[i] = false
+	}
+	return self.grow(rows, sorted, depth)
+}
+
+// Construye el subárbol de las filas `rows`; `sorted` tiene esas mismas filas ordenadas
+// por el valor de cada columna numérica.
+func (self *treeBuilder) grow(rows []int, sorted [][]int, depth int) *Node {
+	g, h := 0.0, 0.0
+	for _, i := range rows {
+		g += self.grad[i]
+		h += self.hess[i]
+	}
+	// Paso de Newton con regularización L2, escalado por la tasa de aprendizaje.
+	leaf := &Node{Output: -g / (h + self.config.Lambda) * self.config.LearningRate}
+	if depth >= self.config.MaxDepth || len(rows) < 2*self.config.MinSamplesLeaf {
+		return leaf
+	}
+
+	best := self.bestSplit(rows, sorted, g, h)
+	if best.gain <= 0 {
+		return leaf
+	}
+	left_rows := make([]int, 0, len(rows))
+	right_rows := make([]int, 0, len(rows))
+	for _, i := range rows {
+		if RF.SplitGoesLeft(self.inputs[i][best.column], best.value, best.categories) {
+			left_rows = append(left_rows, i)
+			self.member[i] = true
+		} else {
+			right_rows = append(right_rows, i)
+		}
+	}
+	// Reparte cada lista ordenada entre los hijos conservando el orden.
+	left_sorted := make([][]int, len(sorted))
+	right_sorted := make([][]int, len(sorted))
+	for c, order := range sorted {
+		if order == nil {
+			continue
+		}
+		left_sorted[c] = make([]int, 0, len(left_rows))
+		right_sorted[c] = make([]int, 0, len(right_rows))
+		for _, i := range order {
+			if self.member[i] {
+				left_sorted[c] = append(left_sorted[c], i)
+			} else {
+				right_sorted[c] = append(right_sorted[c], i)
+			}
+		}
+	}
+	for _, i := range left_rows {
+		self.member[i] = false
+	}
+	return &Node{
+		ColumnNo:   best.column,
+		Value:      best.value,
+		Categories: best.categories,
+		Left:       self.grow(left_rows, left_sorted, depth+1),
+		Right:      self.grow(right_rows, right_sorted, depth+1),
+	}
+}
+
+// Busca la mejor división de `rows` evaluando las columnas en paralelo con
+// `config.Workers` goroutines. En caso de empate gana la columna de menor índice,
+// por lo que el resultado no depende del orden en que terminan las goroutines.
+func (self *treeBuilder) bestSplit(rows []int, sorted [][]int, g, h float64) split {
+	columns := len(self.numeric)
+	results := make([]split, columns)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	workers := self.config.Workers
+	if workers > columns {
+		workers = columns
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				if self.numeric[c] {
+					results[c] = self.numericSplit(sorted[c], c, g, h)
+				} else {
+					results[c] = self.categoricalSplit(rows, c, g, h)
+				}
+			}
+		}()
+	}
+	for c := 0; c < columns; c++ {
+		jobs <- c
+	}
+	close(jobs)
+	wg.Wait()
+
+	best := split{}
+	for _, result := range results {
+		if result.gain > best.gain {
+			best = result
+		}
+	}
+	return best
+}
+
+// Ganancia de una división según la aproximación de segundo orden de la pérdida.
+func (self *treeBuilder) gain(g_l, h_l, g, h float64) float64 {
+	lambda := self.config.Lambda
+	g_r, h_r := g-g_l, h-h_l
+	return g_l*g_l/(h_l+lambda) + g_r*g_r/(h_r+lambda) - g*g/(h+lambda)
+}
+
+// Mejor umbral de una columna numérica: recorre las filas del nodo, `sorted`, en el orden
+// de la columna, acumulando gradientes y hessianas. Solo se corta entre valores distintos.
+func (self *treeBuilder) numericSplit(sorted []int, c int, g, h float64) split {
+	best := split{column: c}
+	values := self.values[c]
+	min_leaf := self.config.MinSamplesLeaf
+	g_l, h_l := 0.0, 0.0
+	count := 0
+	previous := -1
+	for _, i := range sorted {
+		// Corte entre la fila anterior y la actual.
+		if previous >= 0 && values[previous] < values[i] && count >= min_leaf && len(sorted)-count >= min_leaf {
+			if gain := self.gain(g_l, h_l, g, h); gain > best.gain {
+				best.gain = gain
+				best.value = values[previous]
+			}
+		}
+		g_l += self.grad[i]
+		h_l += self.hess[i]
+		count += 1
+		previous = i
+	}
+	return best
+}
+
+// Mejor subconjunto de una columna categórica: ordena las categorías por su paso de Newton
+// (gradiente / hessiana) y evalúa los prefijos de ese orden, igual que `RF` con etiquetas
+// binarias. El subconjunto guardado es el de menos filas, para que las categorías no vistas
+// sigan la rama mayoritaria.
+func (self *treeBuilder) categoricalSplit(rows []int, c int, g, h float64) split {
+	names := self.names[c]
+	grads := make([]float64, len(names))
+	hess := make([]float64, len(names))
+	counts := make([]int, len(names))
+	for _, i := range rows {
+		code := self.codes[c][i]
+		grads[code] += self.grad[i]
+		hess[code] += self.hess[i]
+		counts[code] += 1
+	}
+	// Categorías presentes en el nodo, ordenadas por nombre y luego por paso de Newton.
+	categories := make([]int32, 0, len(names))
+	for code := range names {
+		if counts[code] > 0 {
+			categories = append(categories, int32(code))
+		}
+	}
+	sort.Slice(categories, func(a, b int) bool { return names[categories[a]] < names[categories[b]] })
+	lambda := self.config.Lambda
+	sort.SliceStable(categories, func(a, b int) bool {
+		return grads[categories[a]]/(hess[categories[a]]+lambda) < grads[categories[b]]/(hess[categories[b]]+lambda)
+	})
+
+	best := split{column: c}
+	best_k := 0
+	best_count := 0
+	min_leaf := self.config.MinSamplesLeaf
+	g_l, h_l, count := 0.0, 0.0, 0
+	for k := 0; k < len(categories)-1; k++ {
+		g_l += grads[categories[k]]
+		h_l += hess[categories[k]]
+		count += counts[categories[k]]
+		if count < min_leaf || len(rows)-count < min_leaf {
+			continue
+		}
+		if gain := self.gain(g_l, h_l, g, h); gain > best.gain {
+			best.gain = gain
+			best_k = k + 1
+			best_count = count
+		}
+	}
+	if best_k == 0 {
+		return best
+	}
+
+	subset := categories[:best_k]
+	if best_count > len(rows)-best_count {
+		subset = categories[best_k:]
+	}
+	for _, code := range subset {
+		best.categories = append(best.categories, names[code])
+	}
+	sort.Strings(best.categories)
+	return best
+}
+
+// Elige `fraction` de las filas sin reemplazo (todas si `fraction` >= 1), en orden creciente.
+func subsample(n int, fraction float64, rng *rand.Rand) []int {
+	if fraction >= 1 {
+		rows := make([]int, n)
+		for i := range rows {
+			rows[i] = i
+		}
+		return rows
+	}
+	size := int(fraction * float64(n))
+	if size < 1 {
+		size = 1
+	}
+	rows := rng.Perm(n)[:size]
+	sort.Ints(rows)
+	return rows
+}
@@ -0,0 +1,153 @@
+package RF
+
+import (
+	"fmt"       // Para escribir el código generado
+	"go/format" // Para dar formato estándar al código generado
+	"io"        // Para escribir en cualquier destino
+	"sort"      // Para ordenar las clases
+	"strconv"   // Para escribir números y cadenas como literales de Go
+	"strings"   // Para construir el código y la indentación
+)
+
+// `WriteGo` genera un archivo Go independiente (sin dependencias) con el bosque convertido
+// en funciones if/else anidadas: una función por árbol y una función de votación.
+// El paquete generado expone siempre la misma API:
+//
+//	var Classes []string                                  // clases, en el orden de los vectores de probabilidad
+//	var Columns []string                                  // columnas esperadas (si el bosque tiene esquema)
+//	func Predict(input []interface{}) (string, float64)   // igual que Forest.PredicateProba
+//	func Probabilities(input []interface{}, probs []float64)
+//
+// `input` usa los mismos tipos que `Forest.Predicate`: `float64` para columnas numéricas y
+// `string` para categóricas.
+func (self *Forest) WriteGo(w io.Writer, packageName string) error {
+	classes := make(map[string]bool)
+	for _, tree := range self.Trees {
+		if tree != nil && tree.Root != nil {
+			collectFlat(tree.Root, classes, make(map[int]map[string]bool), make(map[int]uint8))
+		}
+	}
+	class_list := make([]string, 0, len(classes))
+	for label := range classes {
+		class_list = append(class_list, label)
+	}
+	sort.Strings(class_list)
+	class_index := make(map[string]int, len(class_list))
+	for i, label := range class_list {
+		class_index[label] = i
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by rf codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %s contiene un bosque aleatorio de %d árboles compilado a código Go.\n", packageName, len(self.Trees))
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+
+	b.WriteString("// Classes son las clases que predice el bosque, en el orden de los vectores de probabilidad.\n")
+	fmt.Fprintf(&b, "var Classes = %s\n\n", goStringSlice(class_list))
+	b.WriteString("// Columns son las columnas que espera `Predict`, en orden (vacío si el bosque no tenía esquema).\n")
+	fmt.Fprintf(&b, "var Columns = %s\n\n", goStringSlice(self.ColumnNames()))
+
+	b.WriteString(`// Predict devuelve la clase más votada para input y su probabilidad.
+func Predict(input []interface{}) (string, float64) {
+	var probs [` + strconv.Itoa(len(class_list)) + `]float64
+	Probabilities(input, probs[:])
+	best := -1
+	for k, p := range probs {
+		if p > 0 && (best < 0 || p > probs[best]) {
+			best = k
+		}
+	}
+	if best < 0 {
+		return "", 0
+	}
+	return Classes[best], probs[best]
+}
+
+// Probabilities escribe en probs (de largo len(Classes)) la probabilidad de cada clase
+// para input: el promedio de las distribuciones de las hojas alcanzadas en cada árbol.
+func Probabilities(input []interface{}, probs []float64) {
+	for k := range probs {
+		probs[k] = 0
+	}
+`)
+	for i := range self.Trees {
+		fmt.Fprintf(&b, "\ttree%d(input, probs)\n", i)
+	}
+	if len(self.Trees) > 0 {
+		b.WriteString("\tfor k := range probs {\n")
+		fmt.Fprintf(&b, "\t\tprobs[k] /= %d\n", len(self.Trees))
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+
+	for i, tree := range self.Trees {
+		fmt.Fprintf(&b, "\nfunc tree%d(input []interface{}, votes []float64) {\n", i)
+		if tree != nil && tree.Root != nil {
+			writeGoNode(&b, tree.Root, class_index, 1)
+		}
+		b.WriteString("}\n")
+	}
+
+	source, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return fmt.Errorf("generated code does not compile: %v", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// Escribe el código del subárbol de `node`. Las condiciones replican a `predicate`:
+// si el valor no es del tipo esperado o no cumple la condición se sigue por la derecha.
+func writeGoNode(b *strings.Builder, node *TreeNode, class_index map[string]int, depth int) {
+	indent := strings.Repeat("\t", depth)
+	if node.Labels != nil {
+		total := 0.0
+		for _, v := range node.Labels {
+			total += float64(v)
+		}
+		for _, label := range sortedLabels(node.Labels) {
+			p := float64(node.Labels[label]) / total
+			fmt.Fprintf(b, "%svotes[%d] += %s\n", indent, class_index[label], strconv.FormatFloat(p, 'g', -1, 64))
+		}
+		return
+	}
+
+	var condition string
+	if node.Categories != nil {
+		options := make([]string, len(node.Categories))
+		for i, category := range node.Categories {
+			options[i] = "v == " + strconv.Quote(category)
+		}
+		condition = fmt.Sprintf("v, ok := input[%d].(string); ok && (%s)", node.ColumnNo, strings.Join(options, " || "))
+	} else {
+		switch value := node.Value.(type) {
+		case float64:
+			condition = fmt.Sprintf("v, ok := input[%d].(float64); ok && v <= %s", node.ColumnNo, strconv.FormatFloat(value, 'g', -1, 64))
+		case string:
+			condition = fmt.Sprintf("v, ok := input[%d].(string); ok && v == %s", node.ColumnNo, strconv.Quote(value))
+		default:
+			condition = "false"
+		}
+	}
+
+	if node.Left != nil {
+		fmt.Fprintf(b, "%sif %s {\n", indent, condition)
+		writeGoNode(b, node.Left, class_index, depth+1)
+		if node.Right != nil {
+			fmt.Fprintf(b, "%s} else {\n", indent)
+			writeGoNode(b, node.Right, class_index, depth+1)
+		}
+		fmt.Fprintf(b, "%s}\n", indent)
+	} else if node.Right != nil {
+		writeGoNode(b, node.Right, class_index, depth)
+	}
+}
+
+// Escribe un slice de cadenas como literal de Go.
+func goStringSlice(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
@@ -0,0 +1,120 @@
+package RF
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// Programa que usa el paquete generado: lee registros JSON de stdin y escribe
+// la clase y las probabilidades de cada uno.
+const codegenHarness = `package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"codegentest/model"
+)
+
+type result struct {
+	Label string
+	Proba float64
+	Probs []float64
+}
+
+func main() {
+	var inputs [][]interface{}
+	if err := json.NewDecoder(os.Stdin).Decode(&inputs); err != nil {
+		panic(err)
+	}
+	results := make([]result, len(inputs))
+	for i, input := range inputs {
+		results[i].Label, results[i].Proba = model.Predict(input)
+		results[i].Probs = make([]float64, len(model.Classes))
+		model.Probabilities(input, results[i].Probs)
+	}
+	json.NewEncoder(os.Stdout).Encode(results)
+}
+`
+
+func TestGeneratedCodeMatchesPredicate(t *testing.T) {
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	forest, inputs := compileFixture(t)
+
+	// Módulo temporal con el paquete generado y el programa de prueba.
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "model"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var source bytes.Buffer
+	if err := forest.WriteGo(&source, "model"); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"go.mod":         []byte("module codegentest\n\ngo 1.23\n"),
+		"main.go":        []byte(codegenHarness),
+		"model/model.go": source.Bytes(),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	input_json, err := json.Marshal(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(gobin, "run", ".")
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(input_json)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running generated code: %v", err)
+	}
+
+	var results []struct {
+		Label string
+		Proba float64
+		Probs []float64
+	}
+	if err := json.Unmarshal(output, &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(inputs) {
+		t.Fatalf("got %d results for %d records", len(results), len(inputs))
+	}
+
+	classes := forest.Compile().Classes
+	for i, input := range inputs {
+		expect := forest.Probabilities(input)
+		for k, label := range classes {
+			if math.Abs(results[i].Probs[k]-expect[label]) > 1e-12 {
+				t.Fatalf("record %d, class %s: generated probability %v, expected %v", i, label, results[i].Probs[k], expect[label])
+			}
+		}
+		// Con empate, `Predicate` puede elegir cualquiera de las clases empatadas.
+		label, proba := forest.PredicateProba(input)
+		if math.Abs(results[i].Proba-proba) > 1e-12 {
+			t.Fatalf("record %d: generated %s (%v), forest %s (%v)", i, results[i].Label, results[i].Proba, label, proba)
+		}
+		ties := 0
+		for _, p := range expect {
+			if p == proba {
+				ties += 1
+			}
+		}
+		if label != results[i].Label && ties == 1 {
+			t.Fatalf("record %d: generated predicts %s, forest predicts %s", i, results[i].Label, label)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package RF
+
+import (
+	"math" // Para marcar con NaN los valores desconocidos
+	"sort" // Para ordenar las clases y las categorías
+	"sync" // Para reutilizar los buffers de predicción
+)
+
+// Tipos de nodo de un bosque compilado.
+const (
+	flatLeaf    = iota // Hoja: `left` es el desplazamiento de sus probabilidades
+	flatNumeric        // División numérica: va a la izquierda si valor <= `value`
+	flatCat            // División categórica: va a la izquierda si código == `value`
+	flatSubset         // División por subconjunto: va a la izquierda si el bit del código está en `masks[value:]`
+)
+
+// Nodo de un árbol compilado. Los hijos son índices dentro de `CompiledForest.nodes`
+// (-1 si no existen).
+type flatNode struct {
+	kind   uint8
+	column int32
+	left   int32
+	right  int32
+	value  float64
+}
+
+// Estructura `CompiledForest` con una representación plana de un `Forest` para inferencia
+// rápida: todos los nodos en un único arreglo, las hojas con su distribución de clases ya
+// normalizada y las categorías codificadas como enteros. Predecir no reserva memoria.
+type CompiledForest struct {
+	Classes    []string           // Clases, en el orden de los vectores de probabilidad
+	roots      []int32            // Índice del nodo raíz de cada árbol
+	nodes      []flatNode         // Nodos de todos los árboles
+	probs      []float64          // Probabilidades de las hojas, `len(Classes)` por hoja
+	columns    int                // Cantidad de columnas usadas (máximo índice + 1)
+	kinds      []uint8            // Tipo de cada columna (`flatNumeric`, `flatCat` o `flatLeaf` si no se usa)
+	categories []map[string]int32 // Código de cada categoría, por columna
+	masks      []uint64           // Bits de los subconjuntos de categorías de los nodos `flatSubset`
+	pool       sync.Pool          // Buffers reutilizables para `Predict`
+}
+
+// Buffers de trabajo de una predicción.
+type compiledScratch struct {
+	row   []float64
+	votes []float64
+}
+
+// `Compile` convierte el bosque a su representación plana. El bosque compilado predice
+// lo mismo que `Predicate` para entradas del mismo tipo que las de entrenamiento.
+func (self *Forest) Compile() *CompiledForest {
+	compiled := &CompiledForest{}
+
+	// Primera pasada: clases, columnas y categorías usadas.
+	classes := make(map[string]bool)
+	categories := make(map[int]map[string]bool)
+	kinds := make(map[int]uint8)
+	for _, tree := range self.Trees {
+		if tree != nil && tree.Root != nil {
+			collectFlat(tree.Root, classes, categories, kinds)
+		}
+	}
+	for label := range classes {
+		compiled.Classes = append(compiled.Classes, label)
+	}
+	sort.Strings(compiled.Classes)
+	class_index := make(map[string]int, len(compiled.Classes))
+	for i, label := range compiled.Classes {
+		class_index[label] = i
+	}
+
+	for c := range kinds {
+		if c+1 > compiled.columns {
+			compiled.columns = c + 1
+		}
+	}
+	compiled.kinds = make([]uint8, compiled.columns)
+	compiled.categories = make([]map[string]int32, compiled.columns)
+	for c, kind := range kinds {
+		compiled.kinds[c] = kind
+		if kind != flatCat {
+			continue
+		}
+		values := make([]string, 0, len(categories[c]))
+		for value := range categories[c] {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		compiled.categories[c] = make(map[string]int32, len(values))
+		for code, value := range values {
+			compiled.categories[c][value] = int32(code)
+		}
+	}
+
+	// Segunda pasada: aplana cada árbol.
+	for _, tree := range self.Trees {
+		root := int32(-1)
+		if tree != nil && tree.Root != nil {
+			root = compiled.flatten(tree.Root, class_index)
+		}
+		compiled.roots = append(compiled.roots, root)
+	}
+
+	classes_count := len(compiled.Classes)
+	compiled.pool.New = func() any {
+		return &compiledScratch{row: make([]float64, compiled.columns), votes: make([]float64, classes_count)}
+	}
+	return compiled
+}
+
+// Recorre el árbol registrando las clases de las hojas, el tipo de cada columna y las categorías.
+func collectFlat(node *TreeNode, classes map[string]bool, categories map[int]map[string]bool, kinds map[int]uint8) {
+	if node.Labels != nil {
+		for label := range node.Labels {
+			classes[label] = true
+		}
+		return
+	}
+	values := node.Categories
+	switch value := node.Value.(type) {
+	case float64:
+		kinds[node.ColumnNo] = flatNumeric
+	case string:
+		values = append(values, value)
+	}
+	if len(values) > 0 {
+		kinds[node.ColumnNo] = flatCat
+		if categories[node.ColumnNo] == nil {
+			categories[node.ColumnNo] = make(map[string]bool)
+		}
+		for _, value := range values {
+			categories[node.ColumnNo][value] = true
+		}
+	}
+	if node.Left != nil {
+		collectFlat(node.Left, classes, categories, kinds)
+	}
+	if node.Right != nil {
+		collectFlat(node.Right, classes, categories, kinds)
+	}
+}
+
+// Agrega `node` y sus descendientes al arreglo de nodos y devuelve su índice.
+func (self *CompiledForest) flatten(node *TreeNode, class_index map[string]int) int32 {
+	index := int32(len(self.nodes))
+	self.nodes = append(self.nodes, flatNode{left: -1, right: -1})
+
+	if node.Labels != nil {
+		// Normaliza la distribución de la hoja una sola vez.
+		offset := len(self.probs)
+		self.probs = append(self.probs, make([]float64, len(self.Classes))...)
+		total := 0.0
+		for _, v := range node.Labels {
+			total += float64(v)
+		}
+		for label, v := range node.Labels {
+			self.probs[offset+class_index[label]] = float64(v) / total
+		}
+		self.nodes[index] = flatNode{kind: flatLeaf, left: int32(offset), right: -1}
+		return index
+	}
+
+	flat := flatNode{column: int32(node.ColumnNo), left: -1, right: -1}
+	switch value := node.Value.(type) {
+	case nil:
+		// Subconjunto de categorías: un bit por código de la columna.
+		codes := self.categories[node.ColumnNo]
+		offset := len(self.masks)
+		self.masks = append(self.masks, make([]uint64, (len(codes)+63)/64)...)
+		for _, category := range node.Categories {
+			code := codes[category]
+			self.masks[offset+int(code)/64] |= 1 << (uint(code) % 64)
+		}
+		flat.kind = flatSubset
+		flat.value = float64(offset)
+	case float64:
+		flat.kind = flatNumeric
+		flat.value = value
+	case string:
+		flat.kind = flatCat
+		flat.value = float64(self.categories[node.ColumnNo][value])
+	default:
+		// Valor desconocido: ninguna entrada cumple la condición.
+		flat.kind = flatNumeric
+		flat.value = math.NaN()
+	}
+	if node.Left != nil {
+		flat.left = self.flatten(node.Left, class_index)
+	}
+	if node.Right != nil {
+		flat.right = self.flatten(node.Right, class_index)
+	}
+	self.nodes[index] = flat
+	return index
+}
+
+// `Encode` codifica `input` en `row` (de largo al menos `Columns()`): los números se copian
+// y las categorías se reemplazan por su código. Los valores de tipo distinto al esperado
+// y las categorías desconocidas se codifican como NaN, que nunca cumple una condición.
+func (self *CompiledForest) Encode(input []interface{}, row []float64) {
+	for c := 0; c < self.columns; c++ {
+		row[c] = math.NaN()
+		if c >= len(input) {
+			continue
+		}
+		switch value := input[c].(type) {
+		case float64:
+			if self.kinds[c] == flatNumeric {
+				row[c] = value
+			}
+		case string:
+			if self.kinds[c] == flatCat {
+				if code, ok := self.categories[c][value]; ok {
+					row[c] = float64(code)
+				}
+			}
+		}
+	}
+}
+
+// `Columns` devuelve el largo mínimo de una fila codificada.
+func (self *CompiledForest) Columns() int {
+	return self.columns
+}
+
+// `PredictEncoded` acumula en `votes` (de largo `len(Classes)`) la probabilidad promedio
+// de cada clase para la fila codificada `row` y devuelve el índice de la clase ganadora.
+func (self *CompiledForest) PredictEncoded(row []float64, votes []float64) int {
+	for k := range votes {
+		votes[k] = 0
+	}
+	classes_count := len(votes)
+	for _, root := range self.roots {
+		n := root
+		for n >= 0 {
+			node := &self.nodes[n]
+			if node.kind == flatLeaf {
+				leaf := self.probs[node.left : int(node.left)+classes_count]
+				for k, p := range leaf {
+					votes[k] += p
+				}
+				break
+			}
+			value := row[node.column]
+			left := false
+			switch node.kind {
+			case flatNumeric:
+				left = value <= node.value
+			case flatCat:
+				left = value == node.value
+			case flatSubset:
+				if value == value { // NaN: categoría desconocida
+					code := int(value)
+					left = self.masks[int(node.value)+code/64]&(1<<(uint(code)%64)) != 0
+				}
+			}
+			if left {
+				if node.left >= 0 {
+					n = node.left
+					continue
+				}
+			}
+			n = node.right
+		}
+	}
+
+	best := -1
+	trees := float64(len(self.roots))
+	for k := range votes {
+		votes[k] /= trees
+		if votes[k] > 0 && (best < 0 || votes[k] > votes[best]) {
+			best = k
+		}
+	}
+	return best
+}
+
+// `Predict` predice la clase de `input` y su probabilidad, igual que `Forest.PredicateProba`.
+// Es seguro usarlo desde varias goroutines y no reserva memoria por predicción.
+func (self *CompiledForest) Predict(input []interface{}) (string, float64) {
+	scratch := self.pool.Get().(*compiledScratch)
+	defer self.pool.Put(scratch)
+
+	self.Encode(input, scratch.row)
+	best := self.PredictEncoded(scratch.row, scratch.votes)
+	if best < 0 {
+		return "", 0
+	}
+	return self.Classes[best], scratch.votes[best]
+}
+
+// `Probabilities` escribe en `probs` (de largo `len(Classes)`) la probabilidad de cada clase.
+func (self *CompiledForest) Probabilities(input []interface{}, probs []float64) {
+	scratch := self.pool.Get().(*compiledScratch)
+	defer self.pool.Put(scratch)
+
+	self.Encode(input, scratch.row)
+	self.PredictEncoded(scratch.row, probs)
+}
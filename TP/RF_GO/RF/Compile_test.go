@@ -0,0 +1,115 @@
+package RF
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+// Bosque y registros de prueba compartidos por las pruebas y los benchmarks.
+var (
+	compileOnce   sync.Once
+	compileForest *Forest
+	compileInputs [][]interface{}
+)
+
+// Entrena una única vez un bosque con columnas numéricas y categóricas sobre diabetes.csv.
+func compileFixture(tb testing.TB) (*Forest, [][]interface{}) {
+	compileOnce.Do(func() {
+		inputs, labels, schema := diabetesFixture(tb)
+		compileInputs = inputs[:5000]
+		compileForest = BuildForestWithConfig(inputs, labels, ForestConfig{
+			TreesAmount:           20,
+			SamplesAmount:         2000,
+			SelectedFeatureAmount: 3,
+			Seed:                  7,
+			Quiet:                 true,
+			Schema:                schema,
+		})
+	})
+	if compileForest == nil {
+		tb.Fatal("fixture forest was not built")
+	}
+	return compileForest, compileInputs
+}
+
+func TestCompiledForestMatchesPredicate(t *testing.T) {
+	forest, inputs := compileFixture(t)
+	compiled := forest.Compile()
+	probs := make([]float64, len(compiled.Classes))
+
+	for i, input := range inputs {
+		expect := forest.Probabilities(input)
+		compiled.Probabilities(input, probs)
+		for k, label := range compiled.Classes {
+			if math.Abs(expect[label]-probs[k]) > 1e-9 {
+				t.Fatalf("record %d, class %s: compiled probability %v, expected %v", i, label, probs[k], expect[label])
+			}
+		}
+		label, proba := compiled.Predict(input)
+		if math.Abs(proba-expect[label]) > 1e-9 {
+			t.Fatalf("record %d: compiled predicted %s with %v, forest gives %v", i, label, proba, expect[label])
+		}
+	}
+}
+
+func TestCompiledForestUnknownCategory(t *testing.T) {
+	forest, inputs := compileFixture(t)
+	compiled := forest.Compile()
+
+	input := append([]interface{}{}, inputs[0]...)
+	input[4] = "unknown smoking history"
+	label, proba := compiled.Predict(input)
+	expect_label, expect_proba := forest.PredicateProba(input)
+	if label != expect_label || math.Abs(proba-expect_proba) > 1e-9 {
+		t.Fatalf("unknown category: compiled %s (%v), forest %s (%v)", label, proba, expect_label, expect_proba)
+	}
+}
+
+func TestCompiledForestPredictDoesNotAllocate(t *testing.T) {
+	forest, inputs := compileFixture(t)
+	compiled := forest.Compile()
+	compiled.Predict(inputs[0])
+
+	allocs := testing.AllocsPerRun(100, func() {
+		compiled.Predict(inputs[1])
+	})
+	if allocs != 0 {
+		t.Fatalf("Predict allocated %v times per call", allocs)
+	}
+}
+
+func BenchmarkForestPredicate(b *testing.B) {
+	forest, inputs := compileFixture(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		forest.Predicate(inputs[i%len(inputs)])
+	}
+}
+
+func BenchmarkCompiledPredict(b *testing.B) {
+	forest, inputs := compileFixture(b)
+	compiled := forest.Compile()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compiled.Predict(inputs[i%len(inputs)])
+	}
+}
+
+func BenchmarkCompiledPredictEncoded(b *testing.B) {
+	forest, inputs := compileFixture(b)
+	compiled := forest.Compile()
+	rows := make([][]float64, len(inputs))
+	for i, input := range inputs {
+		rows[i] = make([]float64, compiled.Columns())
+		compiled.Encode(input, rows[i])
+	}
+	votes := make([]float64, len(compiled.Classes))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compiled.PredictEncoded(rows[i%len(rows)], votes)
+	}
+}
@@ -0,0 +1,100 @@
+package RF
+
+import (
+	"encoding/csv" // Para leer el conjunto de datos en CSV
+	"fmt"          // Para formatear mensajes de error
+	"io"           // Para detectar el final del archivo
+	"math/rand"    // Para mezclar las filas al dividir el conjunto de datos
+	"os"           // Para abrir el archivo de datos
+)
+
+// Especificación de esquema de diabetes.csv (ver `ParseSchema`) con las columnas numéricas declaradas.
+const DIABETES_SCHEMA = "gender,age:numeric,hypertension,heart_disease,smoking_history,bmi:numeric,HbA1c_level:numeric,blood_glucose_level:numeric"
+
+// `LoadCSV` carga un conjunto de datos CSV con cabecera. La columna `target` se usa como
+// etiqueta (si está vacía se usa la última columna). Las demás columnas se ordenan y
+// convierten según `schema`; si `schema` es nil todas se leen como categóricas.
+// Devuelve las entradas, las etiquetas y el esquema usado.
+func LoadCSV(fileName string, target string, schema *Schema) ([][]interface{}, []string, *Schema, error) {
+	in_f, err := os.Open(fileName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer in_f.Close()
+
+	reader := csv.NewReader(in_f)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read header of %s: %v", fileName, err)
+	}
+
+	// Posición de cada columna dentro de la cabecera.
+	position := make(map[string]int, len(header))
+	for i, name := range header {
+		position[name] = i
+	}
+	if target == "" {
+		target = header[len(header)-1]
+	}
+	target_index, ok := position[target]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("target column %q not found in %s", target, fileName)
+	}
+
+	// Sin esquema, todas las columnas excepto la etiqueta son categóricas.
+	if schema == nil {
+		names := make([]string, 0, len(header)-1)
+		for i, name := range header {
+			if i != target_index {
+				names = append(names, name)
+			}
+		}
+		schema = CatSchema(names)
+	}
+	indexes := make([]int, len(schema.Columns))
+	for i, column := range schema.Columns {
+		index, ok := position[column.Name]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("column %q not found in %s", column.Name, fileName)
+		}
+		indexes[i] = index
+	}
+
+	inputs := make([][]interface{}, 0)
+	labels := make([]string, 0)
+	for line := 1; ; line++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		input := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			value, err := convertValue(fields[index], schema.Columns[i].Type)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("%s line %d, column %q: %v", fileName, line, schema.Columns[i].Name, err)
+			}
+			input[i] = value
+		}
+		inputs = append(inputs, input)
+		labels = append(labels, fields[target_index])
+	}
+	if len(inputs) == 0 {
+		return nil, nil, nil, fmt.Errorf("%s has no records", fileName)
+	}
+	return inputs, labels, schema, nil
+}
+
+// `SplitDataset` mezcla las filas con la semilla `seed` y separa una fracción `test_ratio`
+// para prueba. Devuelve entradas y etiquetas de entrenamiento y de prueba.
+func SplitDataset(inputs [][]interface{}, labels []string, test_ratio float64, seed int64) ([][]interface{}, []string, [][]interface{}, []string) {
+	rng := rand.New(rand.NewSource(seed))
+	index := rng.Perm(len(inputs))
+	test_count := int(float64(len(inputs)) * test_ratio)
+
+	test_index := index[:test_count]
+	train_index := index[test_count:]
+	return getSamples(inputs, train_index), getLabels(labels, train_index), getSamples(inputs, test_index), getLabels(labels, test_index)
+}
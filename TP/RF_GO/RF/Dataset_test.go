@@ -0,0 +1,104 @@
+package RF
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Registros de diabetes.csv compartidos por las pruebas, leídos una única vez.
+var (
+	diabetesOnce   sync.Once
+	diabetesInputs [][]interface{}
+	diabetesLabels []string
+	diabetesSchema *Schema
+)
+
+// Carga diabetes.csv con `DIABETES_SCHEMA`.
+func diabetesFixture(tb testing.TB) ([][]interface{}, []string, *Schema) {
+	diabetesOnce.Do(func() {
+		schema, err := ParseSchema(DIABETES_SCHEMA)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		diabetesInputs, diabetesLabels, diabetesSchema, err = LoadCSV("../diabetes.csv", "diabetes", schema)
+		if err != nil {
+			tb.Fatal(err)
+		}
+	})
+	if diabetesInputs == nil {
+		tb.Fatal("diabetes.csv was not loaded")
+	}
+	return diabetesInputs, diabetesLabels, diabetesSchema
+}
+
+// Entrena un bosque pequeño sobre los primeros registros de diabetes.csv.
+func smallForest(tb testing.TB, trees int, seed int64) *Forest {
+	inputs, labels, schema := diabetesFixture(tb)
+	return BuildForestWithConfig(inputs[:5000], labels[:5000], ForestConfig{
+		TreesAmount:           trees,
+		SamplesAmount:         500,
+		SelectedFeatureAmount: 3,
+		Seed:                  seed,
+		Quiet:                 true,
+		Schema:                schema,
+	})
+}
+
+// `SplitDataset` reparte todas las filas sin repetirlas y con la misma semilla da la misma partición.
+func TestSplitDataset(t *testing.T) {
+	inputs := make([][]interface{}, 100)
+	labels := make([]string, 100)
+	for i := range inputs {
+		inputs[i] = []interface{}{float64(i)}
+		labels[i] = fmt.Sprint(i)
+	}
+	train_inputs, train_labels, test_inputs, test_labels := SplitDataset(inputs, labels, 0.25, 7)
+	if len(train_inputs) != 75 || len(test_inputs) != 25 || len(train_labels) != 75 || len(test_labels) != 25 {
+		t.Fatalf("split sizes %d/%d", len(train_inputs), len(test_inputs))
+	}
+	seen := make(map[string]bool)
+	for i, input := range append(append([][]interface{}{}, train_inputs...), test_inputs...) {
+		label := append(append([]string{}, train_labels...), test_labels...)[i]
+		if fmt.Sprint(input[0]) != label {
+			t.Fatalf("row %v lost its label %s", input, label)
+		}
+		if seen[label] {
+			t.Fatalf("row %s appears twice", label)
+		}
+		seen[label] = true
+	}
+
+	_, again, _, _ := SplitDataset(inputs, labels, 0.25, 7)
+	_, other, _, _ := SplitDataset(inputs, labels, 0.25, 8)
+	if fmt.Sprint(again) != fmt.Sprint(train_labels) {
+		t.Fatal("the same seed gave a different split")
+	}
+	if fmt.Sprint(other) == fmt.Sprint(train_labels) {
+		t.Fatal("a different seed gave the same split")
+	}
+}
+
+// `LoadCSV` usa la última columna como etiqueta por defecto y convierte las columnas numéricas.
+func TestLoadCSV(t *testing.T) {
+	inputs, labels, schema := diabetesFixture(t)
+	if len(inputs) != 100000 || len(labels) != len(inputs) {
+		t.Fatalf("loaded %d records and %d labels", len(inputs), len(labels))
+	}
+	if len(schema.Columns) != 8 || schema.Columns[1].Name != "age" {
+		t.Fatalf("unexpected schema %+v", schema.Columns)
+	}
+	if _, ok := inputs[0][1].(float64); !ok {
+		t.Fatalf("age was not converted: %T", inputs[0][1])
+	}
+	_, default_labels, _, err := LoadCSV("../diabetes.csv", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if default_labels[0] != labels[0] || default_labels[99999] != labels[99999] {
+		t.Fatal("the default target is not the last column")
+	}
+	if _, _, _, err := LoadCSV("../diabetes.csv", "missing", nil); err == nil {
+		t.Fatal("expected an error for an unknown target")
+	}
+}
@@ -0,0 +1,254 @@
+package RF
+
+import (
+	"fmt"     // Para dar formato a la explicación en texto
+	"math"    // Para ordenar las contribuciones por valor absoluto
+	"sort"    // Para ordenar clases y contribuciones
+	"strings" // Para construir la explicación en texto
+)
+
+// Estructura `PathStep` que describe una decisión tomada en un nodo del árbol.
+type PathStep struct {
+	Column    int         `json:"column"`    // Columna evaluada
+	Name      string      `json:"name"`      // Nombre de la columna (o `x[i]`)
+	Operator  string      `json:"operator"`  // Condición que cumplió la entrada: "<=", ">", "==", "!=", "in" o "not in"
+	Value     interface{} `json:"value"`     // Umbral, categoría o subconjunto de categorías del nodo
+	Input     interface{} `json:"input"`     // Valor de la entrada en esa columna
+	Direction string      `json:"direction"` // Rama tomada: "left" o "right"
+}
+
+// Estructura `TreePath` con el camino recorrido en un árbol y la hoja alcanzada.
+type TreePath struct {
+	Tree  int            `json:"tree"`
+	Steps []PathStep     `json:"steps"`
+	Leaf  map[string]int `json:"leaf"` // Distribución de clases de la hoja
+}
+
+// Estructura `Contribution` con el aporte de una columna a la probabilidad de cada clase.
+type Contribution struct {
+	Column int                `json:"column"`
+	Name   string             `json:"name"`
+	Values map[string]float64 `json:"values"` // Clase -> cambio de probabilidad atribuido a la columna
+}
+
+// Estructura `Explanation` con la explicación de una predicción. Se cumple que, para cada
+// clase, `Bias` más la suma de las contribuciones es igual a `Probabilities`.
+type Explanation struct {
+	Prediction    string             `json:"prediction"`
+	Probability   float64            `json:"probability"`
+	Probabilities map[string]float64 `json:"probabilities"`
+	Bias          map[string]float64 `json:"bias"`          // Distribución promedio en la raíz de los árboles
+	Contributions []Contribution     `json:"contributions"` // Ordenadas por aporte a la clase predicha
+	Paths         []TreePath         `json:"paths"`
+}
+
+// `Explain` explica la predicción de `input`: devuelve el camino recorrido en cada árbol
+// y la contribución de cada columna (método de Saabas). En cada nodo del camino, la
+// columna evaluada recibe el cambio en la distribución de clases entre el nodo y el hijo
+// elegido; los aportes se promedian entre los árboles.
+func (self *Forest) Explain(input []interface{}) *Explanation {
+	names := self.ColumnNames()
+	explanation := &Explanation{
+		Probabilities: make(map[string]float64),
+		Bias:          make(map[string]float64),
+	}
+	contributions := make(map[int]map[string]float64)
+	trees := float64(len(self.Trees))
+
+	for t, tree := range self.Trees {
+		if tree == nil || tree.Root == nil {
+			continue
+		}
+		nodes, steps := explainPath(tree.Root, input, names)
+		leaf := nodes[len(nodes)-1]
+		if leaf.Labels == nil {
+			// La entrada no llegó a ninguna hoja: el árbol no vota, igual que en `Predicate`.
+			continue
+		}
+
+		// Distribución de cada nodo del camino, calculada desde la hoja hacia la raíz.
+		distributions := make([]map[string]float64, len(nodes))
+		counts := copyCounts(leaf.Labels)
+		distributions[len(nodes)-1] = normalizeCounts(counts)
+		for i := len(nodes) - 2; i >= 0; i-- {
+			sibling := nodes[i].Right
+			if nodes[i+1] == nodes[i].Right {
+				sibling = nodes[i].Left
+			}
+			if sibling != nil {
+				for k, v := range subtreeCounts(sibling) {
+					counts[k] += v
+				}
+			}
+			distributions[i] = normalizeCounts(counts)
+		}
+
+		for k, v := range distributions[0] {
+			explanation.Bias[k] += v / trees
+		}
+		for i, step := range steps {
+			if contributions[step.Column] == nil {
+				contributions[step.Column] = make(map[string]float64)
+			}
+			for k := range unionKeys(distributions[i], distributions[i+1]) {
+				contributions[step.Column][k] += (distributions[i+1][k] - distributions[i][k]) / trees
+			}
+		}
+		for k, v := range distributions[len(nodes)-1] {
+			explanation.Probabilities[k] += v / trees
+		}
+		explanation.Paths = append(explanation.Paths, TreePath{Tree: t, Steps: steps, Leaf: leaf.Labels})
+	}
+
+	// Clase predicha: la de mayor probabilidad (en caso de empate, la menor alfabéticamente).
+	for _, label := range sortedKeys(explanation.Probabilities) {
+		if explanation.Prediction == "" || explanation.Probabilities[label] > explanation.Probability {
+			explanation.Prediction = label
+			explanation.Probability = explanation.Probabilities[label]
+		}
+	}
+
+	for column, values := range contributions {
+		explanation.Contributions = append(explanation.Contributions, Contribution{Column: column, Name: columnName(names, column), Values: values})
+	}
+	predicted := explanation.Prediction
+	sort.Slice(explanation.Contributions, func(i, j int) bool {
+		a := math.Abs(explanation.Contributions[i].Values[predicted])
+		b := math.Abs(explanation.Contributions[j].Values[predicted])
+		if a != b {
+			return a > b
+		}
+		return explanation.Contributions[i].Column < explanation.Contributions[j].Column
+	})
+	return explanation
+}
+
+// Recorre el árbol igual que `predicate` y devuelve los nodos visitados y las decisiones tomadas.
+func explainPath(node *TreeNode, input []interface{}, names []string) ([]*TreeNode, []PathStep) {
+	nodes := []*TreeNode{node}
+	steps := make([]PathStep, 0)
+	for node.Labels == nil {
+		c := node.ColumnNo
+		step := PathStep{Column: c, Name: columnName(names, c), Value: node.Value, Input: input[c]}
+		operators := [2]string{"<=", ">"}
+		if node.Categories != nil {
+			step.Value = node.Categories
+			operators = [2]string{"in", "not in"}
+		} else if _, ok := node.Value.(string); ok {
+			operators = [2]string{"==", "!="}
+		}
+		step.Operator = operators[1]
+		next := node.Right
+		if node.GoesLeft(input[c]) && node.Left != nil {
+			step.Operator = operators[0]
+			next = node.Left
+		}
+		if next == nil {
+			break
+		}
+		step.Direction = "right"
+		if next == node.Left {
+			step.Direction = "left"
+		}
+		steps = append(steps, step)
+		nodes = append(nodes, next)
+		node = next
+	}
+	return nodes, steps
+}
+
+// `String` da formato de texto a la explicación: predicción, contribuciones y caminos.
+func (self *Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "prediction: %s (probability %.4f)\n", self.Prediction, self.Probability)
+	fmt.Fprintf(&b, "bias:       %s\n\n", formatProbabilities(self.Bias))
+
+	fmt.Fprintf(&b, "contributions to class %s:\n", self.Prediction)
+	for _, contribution := range self.Contributions {
+		fmt.Fprintf(&b, "  %-22s %+.4f\n", contribution.Name, contribution.Values[self.Prediction])
+	}
+
+	b.WriteString("\npaths:\n")
+	for _, path := range self.Paths {
+		fmt.Fprintf(&b, "  tree %d:", path.Tree)
+		for _, step := range path.Steps {
+			fmt.Fprintf(&b, " %s %s %v (%v) ->", step.Name, step.Operator, step.Value, step.Input)
+		}
+		fmt.Fprintf(&b, " leaf %s\n", formatDistribution(path.Leaf))
+	}
+	return b.String()
+}
+
+// Suma las distribuciones de todas las hojas del subárbol de `node`.
+func subtreeCounts(node *TreeNode) map[string]int {
+	if node.Labels != nil {
+		return node.Labels
+	}
+	counts := make(map[string]int)
+	for _, child := range []*TreeNode{node.Left, node.Right} {
+		if child != nil {
+			for k, v := range subtreeCounts(child) {
+				counts[k] += v
+			}
+		}
+	}
+	return counts
+}
+
+// Copia una distribución de clases para poder modificarla.
+func copyCounts(labels map[string]int) map[string]int {
+	counts := make(map[string]int, len(labels))
+	for k, v := range labels {
+		counts[k] = v
+	}
+	return counts
+}
+
+// Convierte una distribución de clases en probabilidades.
+func normalizeCounts(counts map[string]int) map[string]float64 {
+	total := float64(countSamples(counts))
+	probs := make(map[string]float64, len(counts))
+	for k, v := range counts {
+		probs[k] = float64(v) / total
+	}
+	return probs
+}
+
+// Devuelve el conjunto de clases presentes en cualquiera de las dos distribuciones.
+func unionKeys(a, b map[string]float64) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+// Devuelve las claves de un mapa de probabilidades ordenadas alfabéticamente.
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Da formato a un mapa de probabilidades, por ejemplo `{0: 0.9150, 1: 0.0850}`.
+func formatProbabilities(values map[string]float64) string {
+	parts := make([]string, 0, len(values))
+	for _, k := range sortedKeys(values) {
+		parts = append(parts, fmt.Sprintf("%s: %.4f", k, values[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// Devuelve el nombre de la columna `c`, o `x[c]` si no hay nombres.
+func columnName(names []string, c int) string {
+	if c < len(names) {
+		return names[c]
+	}
+	return fmt.Sprintf("x[%d]", c)
+}
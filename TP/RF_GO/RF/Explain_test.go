@@ -0,0 +1,97 @@
+package RF
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// En registros de diabetes.csv, `Bias` más la suma de las contribuciones es igual a la
+// probabilidad de cada clase, y cada camino termina en la hoja a la que llega `PredicateTree`.
+func TestExplainContributions(t *testing.T) {
+	forest := smallForest(t, 10, 4)
+	inputs, _, _ := diabetesFixture(t)
+	for r, input := range inputs[:300] {
+		explanation := forest.Explain(input)
+		probabilities := forest.Probabilities(input)
+		if len(explanation.Paths) != len(forest.Trees) {
+			t.Fatalf("record %d: %d paths for %d trees", r, len(explanation.Paths), len(forest.Trees))
+		}
+		labels := unionKeys(explanation.Bias, explanation.Probabilities)
+		for label := range probabilities {
+			labels[label] = true
+		}
+		for label := range labels {
+			total := explanation.Bias[label]
+			for _, contribution := range explanation.Contributions {
+				total += contribution.Values[label]
+			}
+			if math.Abs(total-explanation.Probabilities[label]) > 1e-9 {
+				t.Fatalf("record %d, class %s: bias + contributions = %v, probability %v", r, label, total, explanation.Probabilities[label])
+			}
+			if math.Abs(probabilities[label]-explanation.Probabilities[label]) > 1e-9 {
+				t.Fatalf("record %d, class %s: explained probability %v, forest probability %v", r, label, explanation.Probabilities[label], probabilities[label])
+			}
+		}
+		if explanation.Probability != explanation.Probabilities[explanation.Prediction] {
+			t.Fatalf("record %d: probability %v does not match class %s", r, explanation.Probability, explanation.Prediction)
+		}
+
+		for _, path := range explanation.Paths {
+			node := forest.Trees[path.Tree].Root
+			for _, step := range path.Steps {
+				if step.Column != node.ColumnNo || step.Input != input[step.Column] {
+					t.Fatalf("record %d, tree %d: step %+v does not match the node on column %d", r, path.Tree, step, node.ColumnNo)
+				}
+				if node.GoesLeft(input[step.Column]) != (step.Direction == "left") {
+					t.Fatalf("record %d, tree %d: step %+v took the wrong branch", r, path.Tree, step)
+				}
+				if step.Direction == "left" {
+					node = node.Left
+				} else {
+					node = node.Right
+				}
+			}
+			if node.Labels == nil || fmt.Sprint(node.Labels) != fmt.Sprint(path.Leaf) {
+				t.Fatalf("record %d, tree %d: path ends in %v, expected leaf %v", r, path.Tree, path.Leaf, node.Labels)
+			}
+			if fmt.Sprint(PredicateTree(forest.Trees[path.Tree], input)) != fmt.Sprint(path.Leaf) {
+				t.Fatalf("record %d, tree %d: path leaf %v differs from PredicateTree", r, path.Tree, path.Leaf)
+			}
+		}
+	}
+}
+
+// Camino y contribuciones sobre el árbol de `exportFixture`, calculados a mano.
+func TestExplainPath(t *testing.T) {
+	forest := &Forest{Trees: []*Tree{exportFixture()}, Schema: CatSchema([]string{"smoking_history", "age", "gender"})}
+	explanation := forest.Explain([]interface{}{"never", 40.0, "Female"})
+
+	steps := explanation.Paths[0].Steps
+	if len(steps) != 2 {
+		t.Fatalf("steps %+v", steps)
+	}
+	if steps[0].Name != "age" || steps[0].Operator != "<=" || steps[0].Value != 52.5 || steps[0].Direction != "left" {
+		t.Fatalf("first step %+v", steps[0])
+	}
+	if steps[1].Name != "smoking_history" || steps[1].Operator != "not in" || steps[1].Input != "never" || steps[1].Direction != "right" {
+		t.Fatalf("second step %+v", steps[1])
+	}
+	if explanation.Prediction != "0" || explanation.Probability != 1 {
+		t.Fatalf("prediction %s with probability %v", explanation.Prediction, explanation.Probability)
+	}
+
+	// Raíz {0: 49, 1: 24} -> izquierda {0: 43, 1: 7} -> hoja {0: 40}.
+	expected := map[string]float64{
+		"age":             43.0/50 - 49.0/73,
+		"smoking_history": 1 - 43.0/50,
+	}
+	for _, contribution := range explanation.Contributions {
+		if math.Abs(contribution.Values["0"]-expected[contribution.Name]) > 1e-12 {
+			t.Fatalf("contribution of %s to class 0 is %v, expected %v", contribution.Name, contribution.Values["0"], expected[contribution.Name])
+		}
+	}
+	if explanation.Contributions[0].Name != "age" {
+		t.Fatalf("contributions are not sorted by size: %+v", explanation.Contributions)
+	}
+}
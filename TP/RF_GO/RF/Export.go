@@ -0,0 +1,168 @@
+package RF
+
+import (
+	"fmt"     // Para dar formato a los nodos
+	"io"      // Para escribir en cualquier destino (archivo o stdout)
+	"sort"    // Para ordenar las clases de cada distribución
+	"strconv" // Para formatear los umbrales numéricos
+	"strings" // Para construir las etiquetas y la indentación
+)
+
+// `ColumnNames` devuelve los nombres de las columnas del esquema del bosque,
+// o nil si el bosque no tiene esquema.
+func (self *Forest) ColumnNames() []string {
+	if self.Schema == nil {
+		return nil
+	}
+	return self.Schema.Names()
+}
+
+// `WriteDOT` escribe el árbol en formato Graphviz DOT. Cada nodo muestra su condición
+// de división, la cantidad de muestras y la distribución de clases; `names` son los
+// nombres de las columnas (si es nil se usa `x[i]`).
+func (self *Tree) WriteDOT(w io.Writer, names []string) error {
+	var b strings.Builder
+	b.WriteString("digraph Tree {\n")
+	b.WriteString("\tnode [shape=box, style=\"rounded\", fontname=\"helvetica\"];\n")
+	b.WriteString("\tedge [fontname=\"helvetica\"];\n")
+	if self.Root != nil {
+		id := 0
+		writeDOTNode(&b, self.Root, names, &id)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Escribe `node` y sus descendientes; `id` es el siguiente identificador libre.
+// Devuelve la distribución de clases del nodo para que el padre la acumule.
+func writeDOTNode(b *strings.Builder, node *TreeNode, names []string, id *int) map[string]int {
+	node_id := *id
+	*id += 1
+
+	if node.Labels != nil {
+		fmt.Fprintf(b, "\tn%d [label=\"%s\"];\n", node_id, dotEscape(fmt.Sprintf(
+			"class = %s\nsamples = %d\nvalue = %s", leafClass(node.Labels), countSamples(node.Labels), formatDistribution(node.Labels))))
+		return node.Labels
+	}
+
+	// Se escriben primero los hijos para conocer la distribución del nodo.
+	var children strings.Builder
+	distribution := make(map[string]int)
+	for i, child := range []*TreeNode{node.Left, node.Right} {
+		if child == nil {
+			continue
+		}
+		child_id := *id
+		for k, v := range writeDOTNode(&children, child, names, id) {
+			distribution[k] += v
+		}
+		edge := "true"
+		if i == 1 {
+			edge = "false"
+		}
+		fmt.Fprintf(&children, "\tn%d -> n%d [label=\"%s\"];\n", node_id, child_id, edge)
+	}
+
+	fmt.Fprintf(b, "\tn%d [label=\"%s\"];\n", node_id, dotEscape(fmt.Sprintf(
+		"%s\nsamples = %d\nvalue = %s", splitCondition(node, names), countSamples(distribution), formatDistribution(distribution))))
+	b.WriteString(children.String())
+	return distribution
+}
+
+// `WriteRules` escribe el árbol como reglas if/else legibles. Cada hoja indica la clase
+// predicha, la cantidad de muestras y la distribución de clases.
+func (self *Tree) WriteRules(w io.Writer, names []string) error {
+	var b strings.Builder
+	if self.Root != nil {
+		writeRules(&b, self.Root, names, 0)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Escribe las reglas del subárbol de `node` con la indentación de `depth`.
+func writeRules(b *strings.Builder, node *TreeNode, names []string, depth int) {
+	indent := strings.Repeat("    ", depth)
+	if node.Labels != nil {
+		fmt.Fprintf(b, "%sreturn %s  # samples = %d, value = %s\n", indent, leafClass(node.Labels), countSamples(node.Labels), formatDistribution(node.Labels))
+		return
+	}
+	fmt.Fprintf(b, "%sif %s {\n", indent, splitCondition(node, names))
+	if node.Left != nil {
+		writeRules(b, node.Left, names, depth+1)
+	}
+	fmt.Fprintf(b, "%s} else {\n", indent)
+	if node.Right != nil {
+		writeRules(b, node.Right, names, depth+1)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// Devuelve la condición de división del nodo (rama izquierda), por ejemplo
+// `age <= 52.5` para columnas numéricas o `gender == "Male"` para categóricas.
+func splitCondition(node *TreeNode, names []string) string {
+	name := columnName(names, node.ColumnNo)
+	switch value := node.Value.(type) {
+	case float64:
+		return fmt.Sprintf("%s <= %s", name, strconv.FormatFloat(value, 'f', -1, 64))
+	case string:
+		return fmt.Sprintf("%s == %q", name, value)
+	}
+	if node.Categories != nil {
+		quoted := make([]string, len(node.Categories))
+		for i, category := range node.Categories {
+			quoted[i] = strconv.Quote(category)
+		}
+		return fmt.Sprintf("%s in {%s}", name, strings.Join(quoted, ", "))
+	}
+	return fmt.Sprintf("%s ? %v", name, node.Value)
+}
+
+// Devuelve la clase con más muestras de una hoja (en caso de empate, la menor alfabéticamente).
+func leafClass(labels map[string]int) string {
+	best := ""
+	best_count := -1
+	for _, label := range sortedLabels(labels) {
+		if labels[label] > best_count {
+			best = label
+			best_count = labels[label]
+		}
+	}
+	return best
+}
+
+// Suma las muestras de una distribución de clases.
+func countSamples(labels map[string]int) int {
+	total := 0
+	for _, v := range labels {
+		total += v
+	}
+	return total
+}
+
+// Da formato a una distribución de clases, por ejemplo `{0: 450, 1: 50}`.
+func formatDistribution(labels map[string]int) string {
+	parts := make([]string, 0, len(labels))
+	for _, label := range sortedLabels(labels) {
+		parts = append(parts, fmt.Sprintf("%s: %d", label, labels[label]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// Devuelve las clases de una distribución ordenadas alfabéticamente.
+func sortedLabels(labels map[string]int) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Escapa una etiqueta para DOT: comillas, barras y saltos de línea.
+func dotEscape(label string) string {
+	label = strings.ReplaceAll(label, "\\", "\\\\")
+	label = strings.ReplaceAll(label, "\"", "\\\"")
+	return strings.ReplaceAll(label, "\n", "\\n")
+}
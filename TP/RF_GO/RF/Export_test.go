@@ -0,0 +1,109 @@
+package RF
+
+import (
+	"strings"
+	"testing"
+)
+
+// Árbol armado a mano con una división numérica, una por subconjunto de categorías y una
+// en el formato anterior (una sola categoría en `Value`).
+func exportFixture() *Tree {
+	return &Tree{Root: &TreeNode{
+		ColumnNo: 1,
+		Value:    52.5,
+		Left: &TreeNode{
+			ColumnNo:   0,
+			Categories: []string{"current", "former"},
+			Left:       &TreeNode{Labels: map[string]int{"0": 3, "1": 7}},
+			Right:      &TreeNode{Labels: map[string]int{"0": 40}},
+		},
+		Right: &TreeNode{
+			ColumnNo: 2,
+			Value:    "Male",
+			Left:     &TreeNode{Labels: map[string]int{"0": 5, "1": 5}},
+			Right:    &TreeNode{Labels: map[string]int{"1": 12, "0": 1}},
+		},
+	}}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var b strings.Builder
+	if err := exportFixture().WriteDOT(&b, []string{"smoking_history", "age", "gender"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph Tree {
+	node [shape=box, style="rounded", fontname="helvetica"];
+	edge [fontname="helvetica"];
+	n0 [label="age <= 52.5\nsamples = 73\nvalue = {0: 49, 1: 24}"];
+	n1 [label="smoking_history in {\"current\", \"former\"}\nsamples = 50\nvalue = {0: 43, 1: 7}"];
+	n2 [label="class = 1\nsamples = 10\nvalue = {0: 3, 1: 7}"];
+	n1 -> n2 [label="true"];
+	n3 [label="class = 0\nsamples = 40\nvalue = {0: 40}"];
+	n1 -> n3 [label="false"];
+	n0 -> n1 [label="true"];
+	n4 [label="gender == \"Male\"\nsamples = 23\nvalue = {0: 6, 1: 17}"];
+	n5 [label="class = 0\nsamples = 10\nvalue = {0: 5, 1: 5}"];
+	n4 -> n5 [label="true"];
+	n6 [label="class = 1\nsamples = 13\nvalue = {0: 1, 1: 12}"];
+	n4 -> n6 [label="false"];
+	n0 -> n4 [label="false"];
+}
+`
+	if b.String() != expected {
+		t.Fatalf("DOT output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+func TestWriteRules(t *testing.T) {
+	var b strings.Builder
+	if err := exportFixture().WriteRules(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := `if x[1] <= 52.5 {
+    if x[0] in {"current", "former"} {
+        return 1  # samples = 10, value = {0: 3, 1: 7}
+    } else {
+        return 0  # samples = 40, value = {0: 40}
+    }
+} else {
+    if x[2] == "Male" {
+        return 0  # samples = 10, value = {0: 5, 1: 5}
+    } else {
+        return 1  # samples = 13, value = {0: 1, 1: 12}
+    }
+}
+`
+	if b.String() != expected {
+		t.Fatalf("rules output:\n%s\nexpected:\n%s", b.String(), expected)
+	}
+}
+
+// En un árbol entrenado, el DOT tiene un nodo por cada nodo del árbol y una arista por cada
+// hijo, y las reglas tienen un `return` por hoja.
+func TestExportTrainedTree(t *testing.T) {
+	forest := smallForest(t, 1, 3)
+	tree := forest.Trees[0]
+	stats := tree.Stats()
+
+	var dot strings.Builder
+	if err := tree.WriteDOT(&dot, forest.ColumnNames()); err != nil {
+		t.Fatal(err)
+	}
+	if nodes := strings.Count(dot.String(), " [label=\"") - strings.Count(dot.String(), " -> "); nodes != stats.Nodes {
+		t.Fatalf("DOT has %d nodes, the tree has %d", nodes, stats.Nodes)
+	}
+	if edges := strings.Count(dot.String(), " -> "); edges != stats.Nodes-1 {
+		t.Fatalf("DOT has %d edges, expected %d", edges, stats.Nodes-1)
+	}
+
+	var rules strings.Builder
+	if err := tree.WriteRules(&rules, forest.ColumnNames()); err != nil {
+		t.Fatal(err)
+	}
+	if leaves := strings.Count(rules.String(), "return "); leaves != stats.Leaves {
+		t.Fatalf("rules have %d leaves, the tree has %d", leaves, stats.Leaves)
+	}
+	if strings.Contains(rules.String(), "x[") {
+		t.Fatal("rules use column indexes instead of the schema names")
+	}
+}
@@ -0,0 +1,92 @@
+package RF
+
+import (
+	"math/rand" // Para elegir umbrales y subconjuntos al azar
+	"sort"      // Para ordenar las categorías
+)
+
+// Función que evalúa una única división al azar de la columna `c`, como en los árboles
+// extremadamente aleatorios (ExtraTrees, Geurts et al. 2006). Para columnas numéricas el
+// umbral se elige uniforme entre el mínimo y el máximo de la columna en las muestras; para
+// columnas categóricas se elige un subconjunto al azar de las categorías presentes.
+// Devuelve lo mismo que `getBestGain`, sin recorrer todos los valores únicos.
+func getRandomSplit(samples [][]interface{}, c int, samples_labels []string, column_type string, current_entropy float64, rng *rand.Rand) (float64, interface{}, int, int) {
+	var value interface{}
+	var categories []string // Categorías presentes, barajadas (solo columnas categóricas)
+	var goes_left func(sample interface{}) bool
+
+	if column_type == NUMERIC {
+		min := samples[0][c].(float64)
+		max := min
+		for j := 1; j < len(samples); j++ {
+			v := samples[j][c].(float64)
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		if min == max {
+			return 0, nil, 0, 0
+		}
+		threshold := min + rng.Float64()*(max-min)
+		value = threshold
+		goes_left = func(sample interface{}) bool { return sample.(float64) <= threshold }
+	} else {
+		present := make(map[string]bool)
+		for j := 0; j < len(samples); j++ {
+			present[samples[j][c].(string)] = true
+		}
+		if len(present) < 2 {
+			return 0, nil, 0, 0
+		}
+		categories = make([]string, 0, len(present))
+		for category := range present {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+
+		// Subconjunto propio y no vacío: se baraja y se toman los primeros k (1 <= k < n).
+		rng.Shuffle(len(categories), func(i, j int) { categories[i], categories[j] = categories[j], categories[i] })
+		k := 1 + rng.Intn(len(categories)-1)
+		subset := categories[:k]
+		in_subset := make(map[string]bool, k)
+		for _, category := range subset {
+			in_subset[category] = true
+		}
+		value = subset
+		goes_left = func(sample interface{}) bool { return in_subset[sample.(string)] }
+	}
+
+	map_l := make(map[string]float64) // Distribución de etiquetas a la izquierda
+	map_r := make(map[string]float64) // Distribución de etiquetas a la derecha
+	total_l := 0
+	total_r := 0
+	for j := 0; j < len(samples); j++ {
+		if goes_left(samples[j][c]) {
+			total_l += 1
+			map_l[samples_labels[j]] += 1.0
+		} else {
+			total_r += 1
+			map_r[samples_labels[j]] += 1.0
+		}
+	}
+
+	p1 := float64(total_r) / float64(len(samples))
+	p2 := float64(total_l) / float64(len(samples))
+	gain := current_entropy - (p1*getEntropy(map_r, total_r) + p2*getEntropy(map_l, total_l))
+
+	// Igual que en `getBestSubset`, la rama izquierda de una división categórica es la de
+	// menos muestras, para que las categorías no vistas sigan la rama mayoritaria.
+	if subset, ok := value.([]string); ok {
+		if total_l > total_r {
+			subset = categories[len(subset):]
+			total_l, total_r = total_r, total_l
+		}
+		subset = append([]string(nil), subset...)
+		sort.Strings(subset)
+		value = subset
+	}
+	return gain, value, total_l, total_r
+}
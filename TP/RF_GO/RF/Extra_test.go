@@ -0,0 +1,39 @@
+package RF
+
+import (
+	"testing"
+	"time"
+)
+
+// Compara la exactitud de ExtraTrees con la del bosque estándar sobre el mismo holdout.
+func TestExtraTreesAccuracy(t *testing.T) {
+	inputs, labels, schema := diabetesFixture(t)
+	train_inputs, train_labels, test_inputs, test_labels := SplitDataset(inputs, labels, 0.2, 11)
+	test_inputs, test_labels = test_inputs[:10000], test_labels[:10000]
+
+	accuracy := make(map[bool]float64)
+	for _, extra := range []bool{false, true} {
+		start := time.Now()
+		forest := BuildForestWithConfig(train_inputs, train_labels, ForestConfig{
+			TreesAmount:           10,
+			SamplesAmount:         3000,
+			SelectedFeatureAmount: 3,
+			Seed:                  11,
+			Quiet:                 true,
+			Schema:                schema,
+			ExtraTrees:            extra,
+		})
+		elapsed := time.Since(start)
+		accuracy[extra] = Evaluate(forest, test_inputs, test_labels, 0).Accuracy()
+		t.Logf("extra=%v: accuracy %.4f, trained in %v", extra, accuracy[extra], elapsed)
+	}
+
+	// El conjunto es desbalanceado (91.5% de clase 0): se exige superar a la clase mayoritaria
+	// y quedar cerca del bosque estándar.
+	if accuracy[true] < 0.93 {
+		t.Fatalf("ExtraTrees accuracy %.4f is not better than the majority class", accuracy[true])
+	}
+	if accuracy[true] < accuracy[false]-0.02 {
+		t.Fatalf("ExtraTrees accuracy %.4f is far below the standard forest (%.4f)", accuracy[true], accuracy[false])
+	}
+}
@@ -0,0 +1,50 @@
+package RF
+
+import (
+	"encoding/json" // Para leer y guardar los modelos en JSON
+	"fmt"           // Para agregar el archivo a los errores de decodificación
+	"os"            // Para abrir y crear los archivos de modelos
+)
+
+// `ReadForest` carga un bosque desde un archivo JSON devolviendo un error en lugar de
+// detener el programa, para que pueda usarse en procesos que no deben caerse.
+func ReadForest(fileName string) (*Forest, error) {
+	forest := &Forest{}
+	if err := ReadJSON(fileName, forest); err != nil {
+		return nil, err
+	}
+	return forest, nil
+}
+
+// `WriteForest` guarda el bosque en un archivo JSON, igual que `DumpForest`, pero devuelve
+// los errores de escritura en lugar de detener el programa.
+func WriteForest(forest *Forest, fileName string) error {
+	return WriteJSON(fileName, forest)
+}
+
+// `ReadJSON` decodifica el archivo JSON `fileName` en `value`. Es el formato de todos los
+// modelos guardados (bosques y modelos de otros paquetes, como GBM).
+func ReadJSON(fileName string, value interface{}) error {
+	in_f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer in_f.Close()
+	if err := json.NewDecoder(in_f).Decode(value); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", fileName, err)
+	}
+	return nil
+}
+
+// `WriteJSON` guarda `value` en el archivo JSON `fileName`, reemplazando su contenido.
+func WriteJSON(fileName string, value interface{}) error {
+	out_f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(out_f).Encode(value); err != nil {
+		out_f.Close()
+		return fmt.Errorf("failed to encode %s: %v", fileName, err)
+	}
+	return out_f.Close()
+}
@@ -0,0 +1,44 @@
+package RF
+
+// Estructura `TreeStats` con las estadísticas de la forma de un árbol.
+type TreeStats struct {
+	Depth  int // Profundidad máxima (la raíz tiene profundidad 0)
+	Nodes  int // Cantidad total de nodos
+	Leaves int // Cantidad de hojas
+}
+
+// `Stats` calcula la profundidad, la cantidad de nodos y la cantidad de hojas del árbol.
+func (self *Tree) Stats() TreeStats {
+	stats := TreeStats{}
+	if self.Root != nil {
+		nodeStats(self.Root, 0, &stats)
+	}
+	return stats
+}
+
+// `Stats` calcula las estadísticas de cada árbol del bosque.
+func (self *Forest) Stats() []TreeStats {
+	stats := make([]TreeStats, len(self.Trees))
+	for i, tree := range self.Trees {
+		stats[i] = tree.Stats()
+	}
+	return stats
+}
+
+// Recorre el subárbol de `node` acumulando sus estadísticas.
+func nodeStats(node *TreeNode, depth int, stats *TreeStats) {
+	stats.Nodes += 1
+	if depth > stats.Depth {
+		stats.Depth = depth
+	}
+	if node.Labels != nil {
+		stats.Leaves += 1
+		return
+	}
+	if node.Left != nil {
+		nodeStats(node.Left, depth+1, stats)
+	}
+	if node.Right != nil {
+		nodeStats(node.Right, depth+1, stats)
+	}
+}
@@ -0,0 +1,243 @@
+package RF
+
+import (
+	"fmt"       // Para mostrar el progreso del entrenamiento
+	"math"      // Para la longitud esperada de los caminos
+	"math/rand" // Para elegir columnas y umbrales al azar
+	"runtime"   // Para elegir la cantidad de goroutines
+	"sort"      // Para ordenar categorías y puntajes
+	"sync"      // Para esperar a las goroutines
+	"time"      // Para la semilla por defecto
+)
+
+// Estructura `IsolationNode` de un árbol de aislamiento. Las divisiones usan el mismo formato
+// que `TreeNode` (umbral en `Value` o subconjunto en `Categories`) y las hojas guardan en
+// `Size` cuántas muestras llegaron a ellas.
+type IsolationNode struct {
+	ColumnNo   int
+	Value      interface{}    `json:",omitempty"`
+	Categories []string       `json:",omitempty"`
+	Left       *IsolationNode `json:",omitempty"`
+	Right      *IsolationNode `json:",omitempty"`
+	Size       int            `json:",omitempty"`
+}
+
+// Estructura `IsolationForest` para detectar registros anómalos (Liu et al. 2008): los
+// registros raros se aíslan con pocas divisiones al azar, por lo que sus caminos son cortos.
+type IsolationForest struct {
+	Trees      []*IsolationNode
+	SampleSize int     // Muestras usadas por árbol
+	Threshold  float64 // Puntaje a partir del cual un registro es anómalo
+	Schema     *Schema `json:",omitempty"`
+}
+
+// Estructura `IsolationConfig` con los parámetros de entrenamiento.
+type IsolationConfig struct {
+	TreesAmount   int     // Cantidad de árboles
+	SampleSize    int     // Muestras (con reemplazo) por árbol; 256 si es 0
+	Contamination float64 // Fracción esperada de anomalías, usada para fijar `Threshold`
+	Seed          int64   // Semilla aleatoria; si es 0 se usa la hora actual
+	Quiet         bool    // Si es true no se imprime el progreso
+	Schema        *Schema // Columnas de las entradas
+}
+
+// `BuildIsolationForest` entrena un bosque de aislamiento con `config.TreesAmount` árboles,
+// construidos en paralelo (uno por goroutine, cada uno con su generador derivado de la
+// semilla, igual que `BuildForestWithConfig`). Luego calcula el puntaje de cada entrada y
+// fija `Threshold` para que se marque la fracción `config.Contamination` de ellas.
+func BuildIsolationForest(inputs [][]interface{}, config IsolationConfig) *IsolationForest {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	sample_size := config.SampleSize
+	if sample_size <= 0 {
+		sample_size = 256
+	}
+	if sample_size > len(inputs) {
+		sample_size = len(inputs)
+	}
+	// Altura máxima: la profundidad promedio de un árbol binario con `sample_size` hojas.
+	height_limit := int(math.Ceil(math.Log2(float64(sample_size))))
+
+	forest := &IsolationForest{Trees: make([]*IsolationNode, config.TreesAmount), SampleSize: sample_size}
+	var wg sync.WaitGroup
+	for i := 0; i < config.TreesAmount; i++ {
+		wg.Add(1)
+		go func(x int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed + int64(x)))
+			samples := getSamples(inputs, bootstrapIndex(len(inputs), sample_size, rng))
+			forest.Trees[x] = buildIsolationTree(samples, 0, height_limit, rng)
+		}(i)
+	}
+	wg.Wait()
+	if !config.Quiet {
+		fmt.Printf("%v built %d isolation trees\n", time.Now(), config.TreesAmount)
+	}
+
+	// Umbral: el cuantil 1 - contaminación de los puntajes de entrenamiento.
+	scores := forest.Scores(inputs, 0)
+	sort.Float64s(scores)
+	forest.Threshold = 1
+	if config.Contamination > 0 {
+		index := int(float64(len(scores)) * (1 - config.Contamination))
+		if index < 0 {
+			index = 0
+		}
+		if index < len(scores) {
+			forest.Threshold = scores[index]
+		}
+	}
+
+	if config.Schema != nil {
+		forest.Schema = config.Schema.Observe(inputs)
+	}
+	return forest
+}
+
+// Construye un árbol de aislamiento: en cada nodo elige al azar una columna que tenga al
+// menos dos valores distintos y una división al azar de esa columna.
+func buildIsolationTree(samples [][]interface{}, depth, height_limit int, rng *rand.Rand) *IsolationNode {
+	if depth >= height_limit || len(samples) <= 1 {
+		return &IsolationNode{Size: len(samples)}
+	}
+
+	columns := getRandomRange(len(samples[0]), len(samples[0]), rng)
+	for _, c := range columns {
+		value, categories, ok := randomIsolationSplit(samples, c, rng)
+		if !ok {
+			continue
+		}
+		left := make([][]interface{}, 0, len(samples))
+		right := make([][]interface{}, 0, len(samples))
+		for _, sample := range samples {
+			if SplitGoesLeft(sample[c], value, categories) {
+				left = append(left, sample)
+			} else {
+				right = append(right, sample)
+			}
+		}
+		return &IsolationNode{
+			ColumnNo:   c,
+			Value:      value,
+			Categories: categories,
+			Left:       buildIsolationTree(left, depth+1, height_limit, rng),
+			Right:      buildIsolationTree(right, depth+1, height_limit, rng),
+		}
+	}
+	// Todas las muestras son iguales: no se pueden separar.
+	return &IsolationNode{Size: len(samples)}
+}
+
+// Elige una división al azar de la columna `c`: un umbral uniforme en [mínimo, máximo) para
+// columnas numéricas o un subconjunto propio de las categorías presentes. Devuelve false si
+// la columna tiene un único valor en las muestras.
+func randomIsolationSplit(samples [][]interface{}, c int, rng *rand.Rand) (interface{}, []string, bool) {
+	if _, ok := samples[0][c].(float64); ok {
+		min := math.Inf(1)
+		max := math.Inf(-1)
+		for _, sample := range samples {
+			if v, ok := sample[c].(float64); ok {
+				min = math.Min(min, v)
+				max = math.Max(max, v)
+			}
+		}
+		if !(min < max) {
+			return nil, nil, false
+		}
+		return min + rng.Float64()*(max-min), nil, true
+	}
+
+	present := make(map[string]bool)
+	for _, sample := range samples {
+		if v, ok := sample[c].(string); ok {
+			present[v] = true
+		}
+	}
+	if len(present) < 2 {
+		return nil, nil, false
+	}
+	categories := make([]string, 0, len(present))
+	for category := range present {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	rng.Shuffle(len(categories), func(i, j int) { categories[i], categories[j] = categories[j], categories[i] })
+	subset := categories[:1+rng.Intn(len(categories)-1)]
+	sort.Strings(subset)
+	return nil, subset, true
+}
+
+// Longitud promedio de un camino en un árbol de búsqueda binaria con `n` elementos: se usa
+// para normalizar los caminos y para estimar la profundidad que falta en una hoja con `n` muestras.
+func averagePathLength(n int) float64 {
+	if n <= 1 {
+		return 0
+	}
+	if n == 2 {
+		return 1
+	}
+	harmonic := math.Log(float64(n-1)) + 0.5772156649 // Número armónico H(n-1) aproximado
+	return 2*harmonic - 2*float64(n-1)/float64(n)
+}
+
+// Longitud del camino de `input` en el árbol: la profundidad de la hoja a la que llega más
+// la longitud promedio `c(Size)` del subárbol que no se construyó por el límite de altura.
+func isolationPathLength(node *IsolationNode, input []interface{}) float64 {
+	depth := 0.0
+	for node.Left != nil {
+		if SplitGoesLeft(input[node.ColumnNo], node.Value, node.Categories) {
+			node = node.Left
+		} else {
+			node = node.Right
+		}
+		depth += 1
+	}
+	return depth + averagePathLength(node.Size)
+}
+
+// `Score` devuelve el puntaje de anomalía de `input`, entre 0 y 1: cercano a 1 si es anómalo,
+// alrededor de 0.5 o menos si es normal.
+func (self *IsolationForest) Score(input []interface{}) float64 {
+	if len(self.Trees) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, tree := range self.Trees {
+		total += isolationPathLength(tree, input)
+	}
+	mean := total / float64(len(self.Trees))
+	return math.Pow(2, -mean/averagePathLength(self.SampleSize))
+}
+
+// `IsAnomaly` indica si el puntaje de `input` alcanza el umbral del bosque.
+func (self *IsolationForest) IsAnomaly(input []interface{}) bool {
+	return self.Score(input) >= self.Threshold
+}
+
+// `Scores` calcula el puntaje de cada entrada en paralelo con `workers` goroutines
+// (una por CPU si es 0), en el mismo orden que `inputs`.
+func (self *IsolationForest) Scores(inputs [][]interface{}, workers int) []float64 {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	scores := make([]float64, len(inputs))
+	part_size := (len(inputs) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(inputs); start += part_size {
+		end := start + part_size
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				scores[i] = self.Score(inputs[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return scores
+}
@@ -0,0 +1,89 @@
+package RF
+
+import (
+	"testing"
+)
+
+// Registros imposibles agregados a diabetes.csv quedan en el 1% de puntajes más altos.
+func TestIsolationPlantedOutliers(t *testing.T) {
+	// Solo las columnas numéricas, como recomienda el README para `rf anomalies`.
+	schema, err := ParseSchema("age:numeric,bmi:numeric,HbA1c_level:numeric,blood_glucose_level:numeric")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs, _, schema, err := LoadCSV("../diabetes.csv", "diabetes", schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Un valor fuera de rango solo se aísla enseguida en los árboles cuya muestra lo contiene,
+	// así que se usan 2000 registros y muestras de 1024 para que aparezca en muchos árboles.
+	data := append([][]interface{}{}, inputs[:2000]...)
+	planted := [][]interface{}{
+		{45.0, 250.0, 5.8, 2400.0},
+		{50.0, 27.3, 6.0, 2400.0},
+		{38.0, 250.0, 5.5, 120.0},
+		{210.0, 26.0, 19.0, 140.0},
+	}
+	for i, row := range planted {
+		// Se intercalan entre los registros normales.
+		position := (i + 1) * len(data) / (len(planted) + 1)
+		data = append(data[:position], append([][]interface{}{row}, data[position:]...)...)
+	}
+
+	forest := BuildIsolationForest(data, IsolationConfig{
+		TreesAmount:   100,
+		SampleSize:    1024,
+		Contamination: 0.01,
+		Seed:          7,
+		Quiet:         true,
+		Schema:        schema,
+	})
+	scores := forest.Scores(data, 0)
+	for _, row := range planted {
+		score := forest.Score(row)
+		rank := 0
+		for _, other := range scores {
+			if other > score {
+				rank += 1
+			}
+		}
+		if rank >= len(data)/100 {
+			t.Errorf("planted row %v has score %.4f, ranked %d of %d", row, score, rank+1, len(data))
+		}
+		if !forest.IsAnomaly(row) {
+			t.Errorf("planted row %v is not flagged (score %.4f, threshold %.4f)", row, score, forest.Threshold)
+		}
+	}
+	if normal := forest.Score(inputs[0]); normal >= forest.Threshold {
+		t.Errorf("a normal record is flagged with score %.4f", normal)
+	}
+}
+
+// El largo de un camino es la profundidad de la hoja más c(Size) de la hoja.
+func TestIsolationPathLength(t *testing.T) {
+	tree := &IsolationNode{
+		ColumnNo: 0,
+		Value:    10.0,
+		Left:     &IsolationNode{Size: 1},
+		Right: &IsolationNode{
+			ColumnNo:   1,
+			Categories: []string{"a"},
+			Left:       &IsolationNode{Size: 2},
+			Right:      &IsolationNode{Size: 40},
+		},
+	}
+	cases := []struct {
+		input  []interface{}
+		length float64
+	}{
+		{[]interface{}{5.0, "a"}, 1},
+		{[]interface{}{5000.0, "a"}, 2 + 1},
+		{[]interface{}{20.0, "b"}, 2 + averagePathLength(40)},
+	}
+	for _, c := range cases {
+		if length := isolationPathLength(tree, c.input); length != c.length {
+			t.Errorf("path length of %v is %v, expected %v", c.input, length, c.length)
+		}
+	}
+}
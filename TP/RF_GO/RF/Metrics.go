@@ -0,0 +1,194 @@
+package RF
+
+import (
+	"fmt"     // Para formatear el reporte
+	"runtime" // Para elegir la cantidad de goroutines
+	"sort"    // Para ordenar las clases en el reporte
+	"strings" // Para construir el reporte
+	"sync"    // Para esperar a las goroutines de evaluación
+)
+
+// Estructura `Report` con las métricas de un modelo sobre un conjunto de prueba.
+type Report struct {
+	Total     int                       // Cantidad de registros evaluados
+	Correct   int                       // Cantidad de predicciones correctas
+	Labels    []string                  // Clases presentes, ordenadas
+	Confusion map[string]map[string]int // Matriz de confusión: esperado -> predicho -> cantidad
+}
+
+// Interfaz `Classifier` de los modelos que predicen una clase, como `Forest` o los modelos de GBM.
+type Classifier interface {
+	Predicate(input []interface{}) string
+}
+
+// `Evaluate` predice cada registro de `inputs` en paralelo con `workers` goroutines
+// (una por CPU si es 0) y compara las predicciones con `labels`.
+func Evaluate(model Classifier, inputs [][]interface{}, labels []string, workers int) *Report {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// Cada goroutine evalúa un bloque contiguo y guarda su propia matriz de confusión.
+	partials := make([]map[string]map[string]int, workers)
+	part_size := (len(inputs) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * part_size
+		end := start + part_size
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		confusion := make(map[string]map[string]int)
+		partials[w] = confusion
+		if start >= end {
+			continue
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				addPrediction(confusion, labels[i], model.Predicate(inputs[i]))
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	// Une las matrices parciales.
+	report := &Report{Confusion: make(map[string]map[string]int)}
+	seen := make(map[string]bool)
+	for _, confusion := range partials {
+		for expect, row := range confusion {
+			for output, count := range row {
+				if report.Confusion[expect] == nil {
+					report.Confusion[expect] = make(map[string]int)
+				}
+				report.Confusion[expect][output] += count
+				seen[expect] = true
+				seen[output] = true
+				report.Total += count
+				if expect == output {
+					report.Correct += count
+				}
+			}
+		}
+	}
+	for label := range seen {
+		report.Labels = append(report.Labels, label)
+	}
+	sort.Strings(report.Labels)
+	return report
+}
+
+// Agrega una predicción a la matriz de confusión.
+func addPrediction(confusion map[string]map[string]int, expect, output string) {
+	if confusion[expect] == nil {
+		confusion[expect] = make(map[string]int)
+	}
+	confusion[expect][output] += 1
+}
+
+// `Accuracy` devuelve la fracción de predicciones correctas.
+func (self *Report) Accuracy() float64 {
+	if self.Total == 0 {
+		return 0
+	}
+	return float64(self.Correct) / float64(self.Total)
+}
+
+// `Precision` devuelve la precisión de la clase `label`: aciertos sobre predicciones de esa clase.
+func (self *Report) Precision(label string) float64 {
+	predicted := 0
+	for _, row := range self.Confusion {
+		predicted += row[label]
+	}
+	if predicted == 0 {
+		return 0
+	}
+	return float64(self.Confusion[label][label]) / float64(predicted)
+}
+
+// `Recall` devuelve la exhaustividad de la clase `label`: aciertos sobre registros de esa clase.
+func (self *Report) Recall(label string) float64 {
+	actual := 0
+	for _, count := range self.Confusion[label] {
+		actual += count
+	}
+	if actual == 0 {
+		return 0
+	}
+	return float64(self.Confusion[label][label]) / float64(actual)
+}
+
+// `F1` devuelve la media armónica entre la precisión y la exhaustividad de `label`.
+func (self *Report) F1(label string) float64 {
+	p := self.Precision(label)
+	r := self.Recall(label)
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+// `String` da formato de texto al reporte: exactitud, métricas por clase y matriz de confusión.
+func (self *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "records:  %d\n", self.Total)
+	fmt.Fprintf(&b, "accuracy: %.4f\n\n", self.Accuracy())
+
+	fmt.Fprintf(&b, "%-12s %10s %10s %10s %10s\n", "class", "precision", "recall", "f1", "support")
+	for _, label := range self.Labels {
+		support := 0
+		for _, count := range self.Confusion[label] {
+			support += count
+		}
+		fmt.Fprintf(&b, "%-12s %10.4f %10.4f %10.4f %10d\n", label, self.Precision(label), self.Recall(label), self.F1(label), support)
+	}
+
+	fmt.Fprintf(&b, "\nconfusion matrix (rows: expected, columns: predicted)\n%-12s", "")
+	for _, label := range self.Labels {
+		fmt.Fprintf(&b, " %10s", label)
+	}
+	b.WriteString("\n")
+	for _, expect := range self.Labels {
+		fmt.Fprintf(&b, "%-12s", expect)
+		for _, output := range self.Labels {
+			fmt.Fprintf(&b, " %10d", self.Confusion[expect][output])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// `AUC` calcula el área bajo la curva ROC de los puntajes `scores` para la clase `positive`,
+// como la probabilidad de que un registro positivo al azar tenga un puntaje mayor que uno
+// negativo (los empates cuentan la mitad). Devuelve 0 si falta alguna de las dos clases.
+func AUC(scores []float64, labels []string, positive string) float64 {
+	index := make([]int, len(scores))
+	for i := range index {
+		index[i] = i
+	}
+	sort.Slice(index, func(i, j int) bool { return scores[index[i]] < scores[index[j]] })
+
+	// Suma de los rangos de los positivos, con el rango promedio para los puntajes empatados.
+	rank_sum := 0.0
+	positives := 0
+	for start := 0; start < len(index); {
+		end := start
+		for end < len(index) && scores[index[end]] == scores[index[start]] {
+			end += 1
+		}
+		rank := float64(start+end+1) / 2
+		for _, i := range index[start:end] {
+			if labels[i] == positive {
+				rank_sum += rank
+				positives += 1
+			}
+		}
+		start = end
+	}
+	negatives := len(scores) - positives
+	if positives == 0 || negatives == 0 {
+		return 0
+	}
+	return (rank_sum - float64(positives*(positives+1))/2) / float64(positives*negatives)
+}
@@ -0,0 +1,114 @@
+package RF
+
+import (
+	"math"
+	"testing"
+)
+
+// Bosque de un árbol que predice la clase guardada en la primera columna del registro ("a", "b" o "c").
+func echoForest() *Forest {
+	leaf := func(label string) *TreeNode { return &TreeNode{Labels: map[string]int{label: 1}} }
+	return &Forest{Trees: []*Tree{{Root: &TreeNode{
+		Value: "a",
+		Left:  leaf("a"),
+		Right: &TreeNode{Value: "b", Left: leaf("b"), Right: leaf("c")},
+	}}}}
+}
+
+// Matriz de confusión, precisión, exhaustividad y F1 comparadas con valores calculados a mano.
+func TestReport(t *testing.T) {
+	// expected -> predicted: a->a 3, a->b 1, b->b 2, b->c 2, c->a 1, c->c 1
+	pairs := [][2]string{
+		{"a", "a"}, {"a", "a"}, {"a", "a"}, {"a", "b"},
+		{"b", "b"}, {"b", "b"}, {"b", "c"}, {"b", "c"},
+		{"c", "a"}, {"c", "c"},
+	}
+	inputs := make([][]interface{}, len(pairs))
+	labels := make([]string, len(pairs))
+	for i, pair := range pairs {
+		labels[i] = pair[0]
+		inputs[i] = []interface{}{pair[1]}
+	}
+
+	for _, workers := range []int{1, 3, 16} {
+		report := Evaluate(echoForest(), inputs, labels, workers)
+		if report.Total != 10 || report.Correct != 6 || report.Accuracy() != 0.6 {
+			t.Fatalf("workers %d: total %d, correct %d, accuracy %v", workers, report.Total, report.Correct, report.Accuracy())
+		}
+		if len(report.Labels) != 3 || report.Labels[0] != "a" || report.Labels[2] != "c" {
+			t.Fatalf("workers %d: labels %v", workers, report.Labels)
+		}
+		expected := map[string]map[string]int{
+			"a": {"a": 3, "b": 1},
+			"b": {"b": 2, "c": 2},
+			"c": {"a": 1, "c": 1},
+		}
+		for expect, row := range expected {
+			for output, count := range row {
+				if report.Confusion[expect][output] != count {
+					t.Fatalf("workers %d: confusion[%s][%s] = %d, expected %d", workers, expect, output, report.Confusion[expect][output], count)
+				}
+			}
+		}
+
+		// precision: a 3/4, b 2/3, c 1/3; recall: a 3/4, b 2/4, c 1/2.
+		metrics := map[string][3]float64{
+			"a": {3.0 / 4, 3.0 / 4, 3.0 / 4},
+			"b": {2.0 / 3, 1.0 / 2, 4.0 / 7},
+			"c": {1.0 / 3, 1.0 / 2, 2.0 / 5},
+		}
+		for label, m := range metrics {
+			got := [3]float64{report.Precision(label), report.Recall(label), report.F1(label)}
+			for k := range got {
+				if math.Abs(got[k]-m[k]) > 1e-12 {
+					t.Fatalf("workers %d: class %s metrics %v, expected %v", workers, label, got, m)
+				}
+			}
+		}
+	}
+	if p := (&Report{}).Precision("x"); p != 0 {
+		t.Fatalf("precision of an empty report = %v", p)
+	}
+}
+
+// AUC de ejemplos pequeños calculados a mano, incluyendo empates.
+func TestAUC(t *testing.T) {
+	cases := []struct {
+		scores []float64
+		labels []string
+		auc    float64
+	}{
+		{[]float64{0.1, 0.4, 0.35, 0.8}, []string{"0", "0", "1", "1"}, 0.75},
+		{[]float64{0.1, 0.2, 0.8, 0.9}, []string{"0", "0", "1", "1"}, 1},
+		{[]float64{0.9, 0.8, 0.2, 0.1}, []string{"0", "0", "1", "1"}, 0},
+		// Pares positivo-negativo: (0.5, 0.5) empate, (0.5, 0.2) gana, (0.7, 0.5) gana, (0.7, 0.2) gana.
+		{[]float64{0.5, 0.2, 0.5, 0.7}, []string{"0", "0", "1", "1"}, 3.5 / 4},
+		{[]float64{0.3, 0.3, 0.3}, []string{"0", "1", "1"}, 0.5},
+		{[]float64{0.3, 0.6}, []string{"1", "1"}, 0},
+	}
+	for _, c := range cases {
+		if auc := AUC(c.scores, c.labels, "1"); math.Abs(auc-c.auc) > 1e-12 {
+			t.Errorf("AUC(%v, %v) = %v, expected %v", c.scores, c.labels, auc, c.auc)
+		}
+	}
+}
+
+// Estadísticas de un árbol armado a mano.
+func TestTreeStats(t *testing.T) {
+	leaf := func() *TreeNode { return &TreeNode{Labels: map[string]int{"0": 1}} }
+	tree := &Tree{Root: &TreeNode{
+		Value: 1.0,
+		Left:  leaf(),
+		Right: &TreeNode{Value: 2.0, Left: leaf(), Right: &TreeNode{Value: 3.0, Left: leaf(), Right: leaf()}},
+	}}
+	if stats := tree.Stats(); stats != (TreeStats{Depth: 3, Nodes: 7, Leaves: 4}) {
+		t.Fatalf("stats %+v", stats)
+	}
+	if stats := (&Tree{}).Stats(); stats != (TreeStats{}) {
+		t.Fatalf("stats of an empty tree %+v", stats)
+	}
+	forest := &Forest{Trees: []*Tree{tree, {Root: leaf()}}}
+	if stats := forest.Stats(); len(stats) != 2 || stats[1] != (TreeStats{Nodes: 1, Leaves: 1}) {
+		t.Fatalf("forest stats %+v", stats)
+	}
+}
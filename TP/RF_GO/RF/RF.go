@@ -6,21 +6,51 @@ import (
 	"math"             // Operaciones matemáticas básicas
 	"math/rand"        // Generación de números aleatorios
 	"os"               // Para manipulación de archivos (abrir, crear)
+	"sort"             // Para recorrer las clases en orden al desempatar
 	"sync"             // Para concurrencia: mutex y sincronización
 	"time"             // Para obtener la hora actual (usada para la semilla aleatoria)
 )
 
 // Estructura `Forest` que contiene un slice de punteros a `Tree` (árboles de decisión)
+// y, si se entrenó con un esquema, las columnas que espera con sus valores observados.
 type Forest struct {
-	Trees []*Tree
+	Trees  []*Tree
+	Schema *Schema `json:",omitempty"`
+}
+
+// Estructura `ForestConfig` con los parámetros de entrenamiento de un bosque.
+type ForestConfig struct {
+	TreesAmount           int     // Cantidad de árboles
+	SamplesAmount         int     // Cantidad de muestras (con reemplazo) por árbol
+	SelectedFeatureAmount int     // Cantidad de características evaluadas en cada nodo
+	Seed                  int64   // Semilla aleatoria; si es 0 se usa la hora actual
+	Quiet                 bool    // Si es true no se imprime el progreso del entrenamiento
+	Schema                *Schema // Columnas de las entradas; el bosque guarda las categorías y rangos observados
+	ExtraTrees            bool    // Si es true los árboles eligen umbrales al azar (Extremely Randomized Trees)
 }
 
 // `BuildForest` crea un bosque aleatorio con `treesAmount` cantidad de árboles.
 // Recibe las entradas (`inputs`), etiquetas (`labels`), cantidad de árboles (`treesAmount`),
 // cantidad de muestras (`samplesAmount`) y cantidad de características seleccionadas (`selectedFeatureAmount`).
 func BuildForest(inputs [][]interface{}, labels []string, treesAmount, samplesAmount, selectedFeatureAmount int) *Forest {
+	return BuildForestWithConfig(inputs, labels, ForestConfig{
+		TreesAmount:           treesAmount,
+		SamplesAmount:         samplesAmount,
+		SelectedFeatureAmount: selectedFeatureAmount,
+	})
+}
+
+// `BuildForestWithConfig` crea un bosque aleatorio con los parámetros de `config`.
+// Cada árbol usa su propio generador aleatorio derivado de la semilla, por lo que
+// con la misma semilla se obtiene el mismo bosque aunque los árboles se entrenen en paralelo.
+func BuildForestWithConfig(inputs [][]interface{}, labels []string, config ForestConfig) *Forest {
+	treesAmount := config.TreesAmount
+
 	// Inicializa la semilla aleatoria usando el tiempo actual para evitar generar siempre el mismo conjunto de números aleatorios.
-	rand.Seed(time.Now().UnixNano())
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
 
 	// Crea una instancia del bosque.
 	forest := &Forest{}
@@ -40,16 +70,21 @@ func BuildForest(inputs [][]interface{}, labels []string, treesAmount, samplesAm
 	for i := 0; i < treesAmount; i++ {
 		go func(x int) {
 			// Imprime en consola cuándo comienza a construirse un árbol.
-			fmt.Printf(">> %v buiding %vth tree...\n", time.Now(), x)
+			if !config.Quiet {
+				fmt.Printf(">> %v buiding %vth tree...\n", time.Now(), x)
+			}
 
-			// Llama a `BuildTree` para construir un árbol de decisión y lo almacena en el bosque.
-			forest.Trees[x] = BuildTree(inputs, labels, samplesAmount, selectedFeatureAmount)
+			// Construye el árbol con un generador propio y lo almacena en el bosque.
+			rng := rand.New(rand.NewSource(seed + int64(x)))
+			forest.Trees[x] = newTree(inputs, labels, config.SamplesAmount, config.SelectedFeatureAmount, config.ExtraTrees, rng)
 
 			// Bloquea el acceso al contador de progreso para incrementarlo de manera segura.
 			mutex.Lock()
 			prog_counter += 1
 			// Imprime el porcentaje de progreso actual.
-			fmt.Printf("%v tranning progress %.0f%%\n", time.Now(), float64(prog_counter)/float64(treesAmount)*100)
+			if !config.Quiet {
+				fmt.Printf("%v tranning progress %.0f%%\n", time.Now(), float64(prog_counter)/float64(treesAmount)*100)
+			}
 			// Desbloquea el mutex.
 			mutex.Unlock()
 
@@ -63,8 +98,15 @@ func BuildForest(inputs [][]interface{}, labels []string, treesAmount, samplesAm
 		<-done_flag // Bloquea hasta que se reciba una señal de cada árbol.
 	}
 
+	// Guarda el esquema con las categorías y rangos observados en el entrenamiento.
+	if config.Schema != nil {
+		forest.Schema = config.Schema.Observe(inputs)
+	}
+
 	// Imprime un mensaje cuando todos los árboles han sido entrenados.
-	fmt.Println("all done.")
+	if !config.Quiet {
+		fmt.Println("all done.")
+	}
 	return forest // Devuelve el bosque entrenado.
 }
 
@@ -82,6 +124,55 @@ func DefaultForest(inputs [][]interface{}, labels []string, treesAmount int) *Fo
 // `Predicate` predice la clase para un conjunto de datos de entrada (`input`).
 // Recorre todos los árboles en el bosque y cuenta las predicciones de cada árbol.
 func (self *Forest) Predicate(input []interface{}) string {
+	label, _ := self.PredicateProba(input)
+	return label
+}
+
+// `PredicateProba` predice la clase para `input` y devuelve también su probabilidad,
+// es decir, la fracción de votos normalizados que obtuvo la clase ganadora.
+func (self *Forest) PredicateProba(input []interface{}) (string, float64) {
+	counter := self.Probabilities(input)
+
+	// Recorre las clases en orden alfabético para que un empate se resuelva siempre a favor
+	// de la menor, igual que `CompiledForest`.
+	labels := make([]string, 0, len(counter))
+	for k := range counter {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+
+	// Encuentra la clase con la mayor cantidad de votos.
+	max_c := 0.0
+	max_label := ""
+	for _, k := range labels {
+		// Si la frecuencia de esta clase es mayor a la máxima registrada, la actualiza.
+		if v := counter[k]; v > max_c || max_label == "" {
+			max_c = v
+			max_label = k
+		}
+	}
+	// Devuelve la clase con más votos y su probabilidad.
+	return max_label, max_c
+}
+
+// `PredictRecord` predice la clase de un registro con campos por nombre.
+// Usa el esquema del bosque para validar y ordenar los campos; devuelve un error
+// descriptivo si falta una columna, un valor no es numérico o una categoría es desconocida.
+func (self *Forest) PredictRecord(record map[string]any) (string, float64, error) {
+	if self.Schema == nil {
+		return "", 0, fmt.Errorf("forest has no schema; train it with ForestConfig.Schema")
+	}
+	input, err := self.Schema.Convert(record)
+	if err != nil {
+		return "", 0, err
+	}
+	label, proba := self.PredicateProba(input)
+	return label, proba, nil
+}
+
+// `Probabilities` devuelve la probabilidad de cada clase para `input`.
+// Cada árbol aporta la distribución de etiquetas de su hoja y el resultado se promedia.
+func (self *Forest) Probabilities(input []interface{}) map[string]float64 {
 	// Mapa para contar la frecuencia de predicciones para cada clase.
 	counter := make(map[string]float64)
 
@@ -100,25 +191,18 @@ func (self *Forest) Predicate(input []interface{}) string {
 		}
 	}
 
-	// Encuentra la clase con la mayor cantidad de votos.
-	max_c := 0.0
-	max_label := ""
-	for k, v := range counter {
-		// Si la frecuencia de esta clase es mayor o igual a la máxima registrada, la actualiza.
-		if v >= max_c {
-			max_c = v
-			max_label = k
-		}
+	// Divide por la cantidad de árboles para obtener probabilidades entre 0 y 1.
+	for k := range counter {
+		counter[k] /= float64(len(self.Trees))
 	}
-	// Devuelve la clase con más votos.
-	return max_label
+	return counter
 }
 
 // `DumpForest` guarda el bosque en un archivo JSON.
 // Esto permite guardar el modelo entrenado para reutilizarlo más tarde.
 func DumpForest(forest *Forest, fileName string) {
 	// Abre o crea el archivo donde se almacenará el bosque.
-	out_f, err := os.OpenFile(fileName, os.O_CREATE|os.O_RDWR, 0777)
+	out_f, err := os.OpenFile(fileName, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0777)
 	if err != nil {
 		panic("failed to create " + fileName) // Error si no puede crear el archivo.
 	}
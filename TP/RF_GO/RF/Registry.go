@@ -0,0 +1,274 @@
+package RF
+
+import (
+	"errors"        // Para construir errores de validación
+	"fmt"           // Para formatear mensajes de error y de progreso
+	"os"            // Para leer el directorio de modelos
+	"path/filepath" // Para construir rutas dentro del directorio de modelos
+	"sort"          // Para ordenar los modelos del más reciente al más antiguo
+	"strings"       // Para filtrar los archivos por extensión
+	"sync"          // Para serializar las recargas del modelo
+	"sync/atomic"   // Para intercambiar el modelo activo sin bloquear las predicciones
+	"time"          // Para el intervalo de sondeo del directorio
+)
+
+// `Validator` es una validación adicional que el usuario puede aplicar a un bosque
+// recién cargado (por ejemplo, exigir una precisión mínima sobre un conjunto de prueba).
+type Validator func(forest *Forest) error
+
+// Estructura `Registry` que mantiene el bosque activo y lo reemplaza en caliente
+// cuando aparece un archivo de modelo nuevo en el directorio vigilado.
+// Las predicciones en curso terminan con el bosque anterior, mientras que las nuevas
+// usan el bosque recién validado.
+type Registry struct {
+	dir             string                 // Directorio que contiene los modelos (*.json)
+	validate        Validator              // Validación adicional opcional
+	current         atomic.Pointer[Forest] // Bosque activo
+	previous        atomic.Pointer[Forest] // Bosque anterior, usado para `Rollback`
+	mutex           sync.Mutex             // Evita dos recargas simultáneas
+	loaded          string                 // Archivo del bosque activo
+	loaded_previous string                 // Archivo del bosque anterior
+	seen            map[string]time.Time   // Fecha de modificación de cada archivo ya evaluado
+	stop_flag       chan bool              // Canal para detener el sondeo
+	wg              sync.WaitGroup         // Espera a que termine la goroutine de sondeo
+}
+
+// `NewRegistry` crea un registro para el directorio `dir` y carga el modelo válido más
+// reciente: si el último archivo no pasa la validación, prueba con los anteriores.
+// Devuelve un error si no existe ningún modelo válido en el directorio.
+func NewRegistry(dir string, validate Validator) (*Registry, error) {
+	registry := &Registry{
+		dir:      dir,
+		validate: validate,
+		seen:     make(map[string]time.Time),
+	}
+	models, err := registry.modelFiles()
+	if err != nil {
+		return nil, err
+	}
+	problems := make([]string, 0)
+	for _, model := range models {
+		registry.seen[model.name] = model.mod_time
+		forest, err := registry.load(model.name)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		registry.current.Store(forest)
+		registry.loaded = model.name
+		return registry, nil
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("no valid model found in %s: %s", dir, strings.Join(problems, "; "))
+	}
+	return nil, fmt.Errorf("no valid model found in %s", dir)
+}
+
+// `Current` devuelve el bosque activo. El puntero devuelto sigue siendo válido
+// aunque el registro lo reemplace después.
+func (self *Registry) Current() *Forest {
+	return self.current.Load()
+}
+
+// `Loaded` devuelve la ruta del archivo del bosque activo.
+func (self *Registry) Loaded() string {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.loaded
+}
+
+// `Predicate` predice la clase de `input` con el bosque activo.
+func (self *Registry) Predicate(input []interface{}) string {
+	return self.Current().Predicate(input)
+}
+
+// `Reload` busca en el directorio el modelo más reciente que aún no haya sido evaluado,
+// lo valida y, si es correcto, lo activa. Devuelve `true` si se cambió el modelo activo.
+// Si la validación falla se conserva el bosque actual y se devuelve el error.
+func (self *Registry) Reload() (bool, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	fileName, mod_time, err := self.newestModel()
+	if err != nil {
+		return false, err
+	}
+	// No hay archivos nuevos o modificados desde la última evaluación.
+	if fileName == "" {
+		return false, nil
+	}
+	self.seen[fileName] = mod_time
+
+	forest, err := self.load(fileName)
+	if err != nil {
+		// Rollback: el bosque activo no cambia.
+		return false, err
+	}
+
+	if old := self.current.Swap(forest); old != nil {
+		self.previous.Store(old)
+		self.loaded_previous = self.loaded
+	}
+	self.loaded = fileName
+	return true, nil
+}
+
+// Lee el bosque de `fileName` y lo valida con `ValidateForest` y con la validación del usuario.
+func (self *Registry) load(fileName string) (*Forest, error) {
+	forest, err := ReadForest(fileName)
+	if err == nil {
+		err = ValidateForest(forest)
+	}
+	if err == nil && self.validate != nil {
+		err = self.validate(forest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rejected model %s: %v", fileName, err)
+	}
+	return forest, nil
+}
+
+// `Rollback` reactiva el bosque anterior al activo, si existe.
+func (self *Registry) Rollback() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	old := self.previous.Load()
+	if old == nil {
+		return errors.New("no previous model to roll back to")
+	}
+	self.previous.Store(self.current.Swap(old))
+	self.loaded, self.loaded_previous = self.loaded_previous, self.loaded
+	return nil
+}
+
+// `Watch` revisa el directorio cada `interval` en una goroutine y recarga el modelo
+// cuando aparece uno nuevo. Los errores de validación se imprimen y el modelo activo se mantiene.
+func (self *Registry) Watch(interval time.Duration) {
+	self.mutex.Lock()
+	if self.stop_flag != nil {
+		self.mutex.Unlock()
+		return
+	}
+	self.stop_flag = make(chan bool)
+	stop_flag := self.stop_flag
+	self.mutex.Unlock()
+
+	self.wg.Add(1)
+	go func() {
+		defer self.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop_flag:
+				return
+			case <-ticker.C:
+				swapped, err := self.Reload()
+				if err != nil {
+					fmt.Printf("%v model reload failed: %v\n", time.Now(), err)
+				} else if swapped {
+					fmt.Printf("%v model reloaded from %s\n", time.Now(), self.Loaded())
+				}
+			}
+		}
+	}()
+}
+
+// `Close` detiene el sondeo iniciado por `Watch`.
+func (self *Registry) Close() {
+	self.mutex.Lock()
+	stop_flag := self.stop_flag
+	self.stop_flag = nil
+	self.mutex.Unlock()
+
+	if stop_flag != nil {
+		close(stop_flag)
+		self.wg.Wait()
+	}
+}
+
+// Archivo de modelo del directorio con su fecha de modificación.
+type modelFile struct {
+	name     string
+	mod_time time.Time
+}
+
+// `modelFiles` devuelve los archivos *.json del directorio, del más reciente al más antiguo.
+func (self *Registry) modelFiles() ([]modelFile, error) {
+	entries, err := os.ReadDir(self.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]modelFile, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		models = append(models, modelFile{name: filepath.Join(self.dir, entry.Name()), mod_time: info.ModTime()})
+	}
+	sort.SliceStable(models, func(i, j int) bool { return models[i].mod_time.After(models[j].mod_time) })
+	return models, nil
+}
+
+// `newestModel` devuelve el archivo *.json más reciente del directorio que no haya sido
+// evaluado con su fecha de modificación actual. Devuelve "" si no hay ninguno.
+func (self *Registry) newestModel() (string, time.Time, error) {
+	models, err := self.modelFiles()
+	if err != nil || len(models) == 0 {
+		return "", time.Time{}, err
+	}
+	newest := models[0]
+
+	// Se ignora el archivo si ya fue evaluado y no ha cambiado desde entonces.
+	if seen, ok := self.seen[newest.name]; ok && seen.Equal(newest.mod_time) {
+		return "", time.Time{}, nil
+	}
+	return newest.name, newest.mod_time, nil
+}
+
+// `ValidateForest` comprueba que el bosque tenga árboles y que cada árbol esté bien formado:
+// los nodos internos tienen dos hijos y un valor de división, y las hojas tienen etiquetas.
+func ValidateForest(forest *Forest) error {
+	if forest == nil || len(forest.Trees) == 0 {
+		return errors.New("forest has no trees")
+	}
+	for i, tree := range forest.Trees {
+		if tree == nil || tree.Root == nil {
+			return fmt.Errorf("tree %d is empty", i)
+		}
+		if err := validateNode(tree.Root); err != nil {
+			return fmt.Errorf("tree %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Recorre recursivamente un nodo verificando su estructura.
+func validateNode(node *TreeNode) error {
+	if node.Labels != nil {
+		total := 0
+		for _, v := range node.Labels {
+			total += v
+		}
+		if total <= 0 {
+			return errors.New("leaf without samples")
+		}
+		return nil
+	}
+	if node.Left == nil || node.Right == nil {
+		return fmt.Errorf("node on column %d is missing a child", node.ColumnNo)
+	}
+	if node.Value == nil && len(node.Categories) == 0 {
+		return fmt.Errorf("node on column %d has no split value", node.ColumnNo)
+	}
+	if err := validateNode(node.Left); err != nil {
+		return err
+	}
+	return validateNode(node.Right)
+}
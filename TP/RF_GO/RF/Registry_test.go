@@ -0,0 +1,149 @@
+package RF
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Bosque de `trees` árboles de una sola hoja, que votan "0" salvo el último, que vota "1".
+func leafForest(trees int) *Forest {
+	forest := &Forest{Trees: make([]*Tree, trees)}
+	for i := range forest.Trees {
+		label := "0"
+		if i == trees-1 {
+			label = "1"
+		}
+		forest.Trees[i] = &Tree{Root: &TreeNode{Labels: map[string]int{label: 1}}}
+	}
+	return forest
+}
+
+// Guarda `forest` en `dir/name` con la fecha de modificación `mod_time`.
+func writeModel(t *testing.T, dir, name string, forest *Forest, mod_time time.Time) string {
+	fileName := filepath.Join(dir, name)
+	if forest != nil {
+		DumpForest(forest, fileName)
+	} else if err := os.WriteFile(fileName, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(fileName, mod_time, mod_time); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+// El modelo se reemplaza mientras varias goroutines predicen (ejecutar con -race), y
+// `Rollback` reactiva el anterior junto con su archivo.
+func TestRegistryHotSwapAndRollback(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	first := writeModel(t, dir, "a.json", leafForest(3), start)
+	registry, err := NewRegistry(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registry.Loaded() != first {
+		t.Fatalf("loaded %s, expected %s", registry.Loaded(), first)
+	}
+	if err := registry.Rollback(); err == nil {
+		t.Fatal("expected an error without a previous model")
+	}
+
+	stop := make(chan bool)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if label := registry.Predicate(nil); label != "0" && label != "1" {
+					t.Errorf("unexpected label %q", label)
+					return
+				}
+			}
+		}()
+	}
+
+	old := registry.Current()
+	second := writeModel(t, dir, "b.json", leafForest(4), start.Add(time.Minute))
+	swapped, err := registry.Reload()
+	close(stop)
+	wg.Wait()
+	if err != nil || !swapped {
+		t.Fatalf("Reload() = %v, %v; expected true, nil", swapped, err)
+	}
+	if registry.Loaded() != second || len(registry.Current().Trees) != 4 {
+		t.Fatalf("loaded %s with %d trees, expected %s with 4", registry.Loaded(), len(registry.Current().Trees), second)
+	}
+	if swapped, err := registry.Reload(); swapped || err != nil {
+		t.Fatalf("second Reload() = %v, %v; expected false, nil", swapped, err)
+	}
+
+	if err := registry.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if registry.Current() != old || registry.Loaded() != first {
+		t.Fatalf("after rollback loaded %s, expected %s", registry.Loaded(), first)
+	}
+	if err := registry.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if registry.Loaded() != second {
+		t.Fatalf("after second rollback loaded %s, expected %s", registry.Loaded(), second)
+	}
+}
+
+// Un archivo inválido o rechazado por el validador no reemplaza al modelo activo, y al
+// iniciar se usa el modelo válido más reciente.
+func TestRegistryRejectsInvalidModels(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	valid := writeModel(t, dir, "a.json", leafForest(3), start)
+	writeModel(t, dir, "b.json", nil, start.Add(time.Minute))
+
+	registry, err := NewRegistry(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registry.Loaded() != valid {
+		t.Fatalf("loaded %s at startup, expected the older valid %s", registry.Loaded(), valid)
+	}
+
+	writeModel(t, dir, "c.json", nil, start.Add(2*time.Minute))
+	if swapped, err := registry.Reload(); swapped || err == nil {
+		t.Fatalf("Reload() of a corrupt file = %v, %v; expected false and an error", swapped, err)
+	}
+	if registry.Loaded() != valid {
+		t.Fatalf("loaded %s after a rejected model, expected %s", registry.Loaded(), valid)
+	}
+
+	small := func(forest *Forest) error {
+		if len(forest.Trees) < 5 {
+			return os.ErrInvalid
+		}
+		return nil
+	}
+	if _, err := NewRegistry(dir, small); err == nil {
+		t.Fatal("expected an error when no model passes the validator")
+	}
+	registry, err = NewRegistry(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeModel(t, dir, "d.json", leafForest(2), start.Add(3*time.Minute))
+	registry.validate = small
+	if swapped, err := registry.Reload(); swapped || err == nil {
+		t.Fatalf("Reload() of a model rejected by the validator = %v, %v", swapped, err)
+	}
+	if registry.Loaded() != valid {
+		t.Fatalf("loaded %s after a rejected model, expected %s", registry.Loaded(), valid)
+	}
+}
@@ -0,0 +1,215 @@
+package RF
+
+import (
+	"encoding/json" // Para leer esquemas guardados en JSON
+	"fmt"           // Para formatear mensajes de error
+	"math"          // Para detectar valores numéricos inválidos
+	"os"            // Para abrir archivos de esquema
+	"sort"          // Para ordenar las categorías observadas
+	"strconv"       // Para convertir texto a números
+	"strings"       // Para separar la especificación de columnas
+)
+
+// Estructura `Column` que describe una columna de entrada del bosque:
+// su nombre, su tipo (`CAT` o `NUMERIC`) y, una vez entrenado el bosque,
+// las categorías y el rango numérico observados en el entrenamiento.
+// Las categorías se validan en `Convert`; el rango es solo informativo (lo muestra
+// `rf inspect`): un valor fuera de él es válido y sigue la rama del extremo más cercano.
+type Column struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Inferred   bool     `json:"inferred,omitempty"`   // Tipo asumido por falta de esquema (ver `CatSchema`)
+	Categories []string `json:"categories,omitempty"` // Categorías observadas (columnas `CAT`)
+	Min        *float64 `json:"min,omitempty"`        // Mínimo observado (columnas `NUMERIC`), no se valida
+	Max        *float64 `json:"max,omitempty"`        // Máximo observado (columnas `NUMERIC`), no se valida
+}
+
+// Estructura `RecordError` que reúne todos los problemas encontrados al validar un registro.
+type RecordError struct {
+	Problems []string
+}
+
+func (self *RecordError) Error() string {
+	return "invalid record: " + strings.Join(self.Problems, "; ")
+}
+
+// Estructura `Schema` que describe las columnas, en el orden en que el bosque las espera.
+type Schema struct {
+	Columns []Column `json:"columns"`
+}
+
+// `ParseSchema` construye un esquema a partir de una especificación de la forma
+// "gender:cat,age:numeric,...". Si una columna no indica tipo, se asume `CAT`.
+func ParseSchema(spec string) (*Schema, error) {
+	schema := &Schema{}
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, column_type, found := strings.Cut(field, ":")
+		if !found {
+			column_type = CAT
+		}
+		schema.Columns = append(schema.Columns, Column{Name: strings.TrimSpace(name), Type: strings.TrimSpace(column_type)})
+	}
+	if err := schema.check(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// `LoadSchema` carga un esquema desde un archivo JSON.
+func LoadSchema(fileName string) (*Schema, error) {
+	in_f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer in_f.Close()
+	schema := &Schema{}
+	if err := json.NewDecoder(in_f).Decode(schema); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", fileName, err)
+	}
+	if err := schema.check(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// `CatSchema` crea un esquema donde todas las columnas son categóricas,
+// que es como `test.go` entrena el bosque a partir del CSV. Las columnas quedan marcadas
+// como `Inferred`: pueden ser numéricas, así que `Convert` no rechaza valores no observados.
+func CatSchema(names []string) *Schema {
+	schema := &Schema{}
+	for _, name := range names {
+		schema.Columns = append(schema.Columns, Column{Name: name, Type: CAT, Inferred: true})
+	}
+	return schema
+}
+
+// `Names` devuelve los nombres de las columnas en orden.
+func (self *Schema) Names() []string {
+	names := make([]string, len(self.Columns))
+	for i, column := range self.Columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// `Convert` ordena y convierte los campos de `record` al formato que espera el bosque:
+// `string` para las columnas categóricas y `float64` para las numéricas.
+// Si la columna fue declarada categórica y tiene categorías observadas, rechaza las
+// categorías desconocidas; las columnas `Inferred` aceptan cualquier valor.
+// Devuelve un `*RecordError` con todos los problemas encontrados.
+func (self *Schema) Convert(record map[string]interface{}) ([]interface{}, error) {
+	input := make([]interface{}, len(self.Columns))
+	problems := make([]string, 0)
+	for i, column := range self.Columns {
+		value, ok := record[column.Name]
+		if !ok || value == nil {
+			problems = append(problems, fmt.Sprintf("missing column %q", column.Name))
+			continue
+		}
+		converted, err := convertValue(value, column.Type)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("column %q: %v", column.Name, err))
+			continue
+		}
+		if category, ok := converted.(string); ok && !column.Inferred && len(column.Categories) > 0 && !column.hasCategory(category) {
+			problems = append(problems, fmt.Sprintf("column %q: unknown category %q (allowed: %s)", column.Name, category, strings.Join(column.Categories, ", ")))
+			continue
+		}
+		input[i] = converted
+	}
+	if len(problems) > 0 {
+		return nil, &RecordError{Problems: problems}
+	}
+	return input, nil
+}
+
+// `Observe` devuelve una copia del esquema con las categorías y los rangos numéricos
+// observados en `inputs`, cuyas filas deben seguir el orden de las columnas.
+func (self *Schema) Observe(inputs [][]interface{}) *Schema {
+	observed := &Schema{Columns: make([]Column, len(self.Columns))}
+	for c, column := range self.Columns {
+		column.Categories = nil
+		column.Min = nil
+		column.Max = nil
+		if column.Type == CAT {
+			seen := make(map[string]bool)
+			for _, input := range inputs {
+				if category, ok := input[c].(string); ok && !seen[category] {
+					seen[category] = true
+					column.Categories = append(column.Categories, category)
+				}
+			}
+			sort.Strings(column.Categories)
+		} else {
+			for _, input := range inputs {
+				value, ok := input[c].(float64)
+				if !ok {
+					continue
+				}
+				if column.Min == nil || value < *column.Min {
+					column.Min = &value
+				}
+				if column.Max == nil || value > *column.Max {
+					column.Max = &value
+				}
+			}
+		}
+		observed.Columns[c] = column
+	}
+	return observed
+}
+
+// Indica si `category` es una de las categorías observadas (que están ordenadas).
+func (self *Column) hasCategory(category string) bool {
+	i := sort.SearchStrings(self.Categories, category)
+	return i < len(self.Categories) && self.Categories[i] == category
+}
+
+// Verifica que el esquema tenga columnas con nombre y tipos conocidos.
+func (self *Schema) check() error {
+	if len(self.Columns) == 0 {
+		return fmt.Errorf("schema has no columns")
+	}
+	for _, column := range self.Columns {
+		if column.Name == "" {
+			return fmt.Errorf("schema has a column without name")
+		}
+		if column.Type != CAT && column.Type != NUMERIC {
+			return fmt.Errorf("column %q has unknown type %q", column.Name, column.Type)
+		}
+	}
+	return nil
+}
+
+// Convierte un valor leído de CSV (texto) o de JSON (texto, número o booleano) al tipo de la columna.
+func convertValue(value interface{}, column_type string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if column_type == NUMERIC {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, fmt.Errorf("%q is not a number", v)
+			}
+			return f, nil
+		}
+		return v, nil
+	case float64:
+		if column_type == NUMERIC {
+			return v, nil
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		if column_type == NUMERIC {
+			if v {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		}
+		return strconv.FormatBool(v), nil
+	}
+	return nil, fmt.Errorf("unsupported value %v", value)
+}
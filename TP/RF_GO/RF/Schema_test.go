@@ -0,0 +1,89 @@
+package RF
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// Bosque pequeño con una columna categórica declarada, una numérica y una inferida.
+func schemaForest(t *testing.T) *Forest {
+	schema, err := ParseSchema("color:cat,size:numeric")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema.Columns = append(schema.Columns, CatSchema([]string{"shape"}).Columns...)
+	inputs := make([][]interface{}, 200)
+	labels := make([]string, 200)
+	for i := range inputs {
+		color := []string{"red", "green", "blue"}[i%3]
+		inputs[i] = []interface{}{color, float64(i % 10), []string{"round", "square"}[i%2]}
+		labels[i] = fmt.Sprint(color == "red")
+	}
+	return BuildForestWithConfig(inputs, labels, ForestConfig{
+		TreesAmount:           5,
+		SamplesAmount:         100,
+		SelectedFeatureAmount: 2,
+		Seed:                  1,
+		Quiet:                 true,
+		Schema:                schema,
+	})
+}
+
+// `Convert` y `PredictRecord` rechazan los registros inválidos con un `*RecordError`
+// que enumera cada problema, y aceptan los válidos.
+func TestSchemaConvert(t *testing.T) {
+	forest := schemaForest(t)
+	cases := []struct {
+		name     string
+		record   map[string]any
+		problems []string
+	}{
+		{"valid", map[string]any{"color": "red", "size": 3.0, "shape": "round"}, nil},
+		{"numeric text", map[string]any{"color": "blue", "size": " 4.5 ", "shape": "square"}, nil},
+		{"inferred column accepts unseen values", map[string]any{"color": "green", "size": 1.0, "shape": "triangle"}, nil},
+		{"out of observed range", map[string]any{"color": "red", "size": 1e6, "shape": "round"}, nil},
+		{"missing column", map[string]any{"color": "red", "shape": "round"}, []string{`missing column "size"`}},
+		{"null value", map[string]any{"color": nil, "size": 2.0, "shape": "round"}, []string{`missing column "color"`}},
+		{"non-numeric value", map[string]any{"color": "red", "size": "big", "shape": "round"}, []string{`column "size": "big" is not a number`}},
+		{"unknown category", map[string]any{"color": "pink", "size": 2.0, "shape": "round"}, []string{`column "color": unknown category "pink" (allowed: blue, green, red)`}},
+		{"several problems", map[string]any{"color": "pink", "size": "big"}, []string{
+			`column "color": unknown category "pink" (allowed: blue, green, red)`,
+			`column "size": "big" is not a number`,
+			`missing column "shape"`,
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			input, err := forest.Schema.Convert(c.record)
+			_, _, predict_err := forest.PredictRecord(c.record)
+			if c.problems == nil {
+				if err != nil || predict_err != nil {
+					t.Fatalf("unexpected errors %v / %v", err, predict_err)
+				}
+				if _, ok := input[1].(float64); !ok || len(input) != 3 {
+					t.Fatalf("converted input %#v", input)
+				}
+				return
+			}
+			for _, err := range []error{err, predict_err} {
+				var record_err *RecordError
+				if !errors.As(err, &record_err) {
+					t.Fatalf("got %v, want a *RecordError", err)
+				}
+				if fmt.Sprint(record_err.Problems) != fmt.Sprint(c.problems) {
+					t.Fatalf("problems %q, want %q", record_err.Problems, c.problems)
+				}
+			}
+		})
+	}
+}
+
+// `RecordError` reúne todos los problemas en un único mensaje.
+func TestRecordError(t *testing.T) {
+	err := &RecordError{Problems: []string{`missing column "age"`, `column "bmi": "x" is not a number`}}
+	want := `invalid record: missing column "age"; column "bmi": "x" is not a number`
+	if err.Error() != want {
+		t.Fatalf("got %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,251 @@
+package RF
+
+import (
+	"bufio"         // Para leer y escribir líneas de manera eficiente
+	"encoding/csv"  // Para leer y escribir registros CSV
+	"encoding/json" // Para leer y escribir registros JSON Lines
+	"fmt"           // Para formatear valores y mensajes de error
+	"io"            // Para trabajar con cualquier entrada y salida (stdin/stdout)
+	"runtime"       // Para elegir la cantidad de workers por defecto
+	"strconv"       // Para formatear probabilidades
+	"strings"       // Para descartar líneas vacías
+)
+
+// Formatos de entrada y salida soportados por `PredictStream`.
+const CSV_FORMAT = "csv"
+const JSONL_FORMAT = "jsonl"
+
+// Estructura `StreamOptions` con la configuración de `PredictStream`.
+type StreamOptions struct {
+	Schema       *Schema // Columnas del bosque; si es nil se usa la cabecera del CSV como columnas categóricas
+	InputFormat  string  // `CSV_FORMAT` o `JSONL_FORMAT`
+	OutputFormat string  // `CSV_FORMAT` o `JSONL_FORMAT`; si está vacío se usa el formato de entrada
+	Workers      int     // Cantidad de goroutines que predicen; si es 0 se usa la cantidad de CPUs
+}
+
+// Registro leído de la entrada, pendiente de predicción.
+type streamJob struct {
+	line   int                    // Número de registro, para los mensajes de error
+	names  []string               // Nombres de los campos en el orden en que se escriben
+	record map[string]interface{} // Valores del registro por nombre de campo
+	result chan streamResult      // Canal por donde el worker devuelve la predicción
+}
+
+// Resultado de la predicción de un registro.
+type streamResult struct {
+	label string
+	proba float64
+	err   error
+}
+
+// `PredictStream` lee registros de `in`, los predice con `forest` usando un grupo de
+// goroutines y escribe en `out` cada registro seguido de las columnas `prediction` y
+// `probability`. El orden de salida es el mismo que el de entrada.
+func PredictStream(forest *Forest, in io.Reader, out io.Writer, options StreamOptions) error {
+	if options.InputFormat != CSV_FORMAT && options.InputFormat != JSONL_FORMAT {
+		return fmt.Errorf("unknown input format %q", options.InputFormat)
+	}
+	if options.OutputFormat == "" {
+		options.OutputFormat = options.InputFormat
+	}
+	if options.OutputFormat != CSV_FORMAT && options.OutputFormat != JSONL_FORMAT {
+		return fmt.Errorf("unknown output format %q", options.OutputFormat)
+	}
+	if options.InputFormat == JSONL_FORMAT && options.Schema == nil {
+		return fmt.Errorf("a schema is required to read %s", JSONL_FORMAT)
+	}
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+
+	// Prepara el lector; en CSV la cabecera define los nombres de los campos.
+	var next func() (*streamJob, error)
+	var err error
+	schema := options.Schema
+	if options.InputFormat == CSV_FORMAT {
+		schema, next, err = newCSVStream(in, schema)
+	} else {
+		next = newJSONLStream(in, schema)
+	}
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		// Entrada vacía: no hay nada que predecir.
+		return nil
+	}
+
+	// Canal cerrado al terminar, para que el lector no quede bloqueado si la escritura falla.
+	done := make(chan bool)
+	defer close(done)
+
+	// `jobs` reparte los registros entre los workers; `order` los conserva en el orden de entrada.
+	jobs := make(chan *streamJob, options.Workers)
+	order := make(chan *streamJob, options.Workers*4)
+
+	// Goroutine lectora: envía cada registro a los workers y a la cola ordenada.
+	go func() {
+		defer close(order)
+		defer close(jobs)
+		for {
+			job, err := next()
+			if job == nil && err == nil {
+				return
+			}
+			if err != nil {
+				// El error se reporta en orden, después de los registros anteriores.
+				job = &streamJob{result: make(chan streamResult, 1)}
+				job.result <- streamResult{err: err}
+			}
+			select {
+			case order <- job:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+			select {
+			case jobs <- job:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// Lanza los workers que predicen los registros.
+	for w := 0; w < options.Workers; w++ {
+		go func() {
+			for job := range jobs {
+				input, err := schema.Convert(job.record)
+				if err != nil {
+					job.result <- streamResult{err: fmt.Errorf("record %d: %v", job.line, err)}
+					continue
+				}
+				label, proba := forest.PredicateProba(input)
+				job.result <- streamResult{label: label, proba: proba}
+			}
+		}()
+	}
+
+	// Escribe los resultados en el mismo orden en que se leyeron los registros.
+	writer := bufio.NewWriter(out)
+	csv_writer := csv.NewWriter(writer)
+	// Escribe los registros ya procesados antes de devolver un error.
+	flush := func() error {
+		csv_writer.Flush()
+		if err := csv_writer.Error(); err != nil {
+			return err
+		}
+		return writer.Flush()
+	}
+	header_written := false
+	for job := range order {
+		result := <-job.result
+		if result.err != nil {
+			flush()
+			return result.err
+		}
+		if options.OutputFormat == JSONL_FORMAT {
+			record := make(map[string]interface{}, len(job.record)+2)
+			for k, v := range job.record {
+				record[k] = v
+			}
+			record["prediction"] = result.label
+			record["probability"] = result.proba
+			line, err := json.Marshal(record)
+			if err != nil {
+				flush()
+				return fmt.Errorf("record %d: %v", job.line, err)
+			}
+			writer.Write(line)
+			writer.WriteByte('\n')
+			continue
+		}
+		if !header_written {
+			csv_writer.Write(append(append([]string{}, job.names...), "prediction", "probability"))
+			header_written = true
+		}
+		row := make([]string, 0, len(job.names)+2)
+		for _, name := range job.names {
+			row = append(row, formatValue(job.record[name]))
+		}
+		row = append(row, result.label, strconv.FormatFloat(result.proba, 'f', 4, 64))
+		csv_writer.Write(row)
+	}
+	return flush()
+}
+
+// Prepara la lectura de registros CSV con cabecera. Si `schema` es nil, todas las columnas
+// de la cabecera se usan como categóricas. Devuelve un esquema nil si la entrada está vacía.
+func newCSVStream(in io.Reader, schema *Schema) (*Schema, func() (*streamJob, error), error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv header: %v", err)
+	}
+	if schema == nil {
+		schema = CatSchema(header)
+	}
+
+	line := 0
+	next := func() (*streamJob, error) {
+		fields, err := reader.Read()
+		line += 1
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) != len(header) {
+			return nil, fmt.Errorf("record %d: expected %d fields, got %d", line, len(header), len(fields))
+		}
+		record := make(map[string]interface{}, len(header))
+		for i, name := range header {
+			record[name] = fields[i]
+		}
+		return &streamJob{line: line, names: header, record: record, result: make(chan streamResult, 1)}, nil
+	}
+	return schema, next, nil
+}
+
+// Prepara la lectura de registros JSON Lines (un objeto por línea).
+func newJSONLStream(in io.Reader, schema *Schema) func() (*streamJob, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	names := schema.Names()
+	line := 0
+	return func() (*streamJob, error) {
+		for scanner.Scan() {
+			line += 1
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			record := make(map[string]interface{})
+			if err := json.Unmarshal([]byte(text), &record); err != nil {
+				return nil, fmt.Errorf("record %d: %v", line, err)
+			}
+			return &streamJob{line: line, names: names, record: record, result: make(chan streamResult, 1)}, nil
+		}
+		return nil, scanner.Err()
+	}
+}
+
+// Convierte un valor de un registro a texto para la salida CSV.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,164 @@
+package RF
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Devuelve la cabecera y las primeras `n` filas de diabetes.csv como texto CSV.
+func diabetesLines(t *testing.T, n int) (string, []string) {
+	in_f, err := os.Open("../diabetes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in_f.Close()
+	scanner := bufio.NewScanner(in_f)
+	scanner.Scan()
+	header := scanner.Text()
+	rows := make([]string, 0, n)
+	for len(rows) < n && scanner.Scan() {
+		rows = append(rows, scanner.Text())
+	}
+	return header, rows
+}
+
+// La salida conserva el orden de entrada con varios workers y coincide con `PredicateProba`.
+func TestPredictStreamOrder(t *testing.T) {
+	forest := smallForest(t, 5, 1)
+	_, _, schema := diabetesFixture(t)
+	header, rows := diabetesLines(t, 500)
+	input := header + "\n" + strings.Join(rows, "\n") + "\n"
+
+	for _, workers := range []int{1, 8} {
+		var out bytes.Buffer
+		err := PredictStream(forest, strings.NewReader(input), &out, StreamOptions{Schema: schema, InputFormat: CSV_FORMAT, Workers: workers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(&out).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != len(rows)+1 {
+			t.Fatalf("workers %d: got %d records, expected %d", workers, len(records), len(rows)+1)
+		}
+		columns := len(records[0])
+		if records[0][columns-2] != "prediction" || records[0][columns-1] != "probability" {
+			t.Fatalf("unexpected header %v", records[0])
+		}
+		for i, row := range rows {
+			record := records[i+1]
+			if strings.Join(record[:columns-2], ",") != row {
+				t.Fatalf("workers %d: record %d is %v, expected %s", workers, i, record, row)
+			}
+			fields := make(map[string]interface{})
+			for c, name := range records[0][:columns-2] {
+				fields[name] = record[c]
+			}
+			converted, err := schema.Convert(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			label, proba := forest.PredicateProba(converted)
+			if record[columns-2] != label {
+				t.Fatalf("workers %d: record %d predicted %s, expected %s", workers, i, record[columns-2], label)
+			}
+			if expected := strconv.FormatFloat(proba, 'f', 4, 64); record[columns-1] != expected {
+				t.Fatalf("workers %d: record %d has probability %s, expected %s", workers, i, record[columns-1], expected)
+			}
+		}
+	}
+}
+
+// En JSON Lines cada objeto de salida corresponde al objeto de entrada de la misma línea.
+func TestPredictStreamJSONL(t *testing.T) {
+	forest := smallForest(t, 5, 1)
+	_, _, schema := diabetesFixture(t)
+	var in bytes.Buffer
+	for i := 0; i < 200; i++ {
+		line, _ := json.Marshal(map[string]interface{}{
+			"id": i, "gender": "Female", "age": float64(20 + i%60), "hypertension": "0", "heart_disease": "0",
+			"smoking_history": "never", "bmi": 25.0, "HbA1c_level": 4.0 + float64(i%40)/10, "blood_glucose_level": float64(80 + i),
+		})
+		in.Write(line)
+		in.WriteByte('\n')
+	}
+	var out bytes.Buffer
+	if err := PredictStream(forest, &in, &out, StreamOptions{Schema: schema, InputFormat: JSONL_FORMAT, Workers: 8}); err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(&out)
+	i := 0
+	for ; scanner.Scan(); i++ {
+		record := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		if record["id"] != float64(i) {
+			t.Fatalf("line %d has id %v", i, record["id"])
+		}
+		if _, ok := record["prediction"].(string); !ok {
+			t.Fatalf("line %d has no prediction: %v", i, record)
+		}
+	}
+	if i != 200 {
+		t.Fatalf("got %d lines, expected 200", i)
+	}
+}
+
+// Un registro inválido detiene el proceso con un error que lo identifica, y los registros
+// anteriores quedan escritos en la salida.
+func TestPredictStreamError(t *testing.T) {
+	forest := smallForest(t, 5, 1)
+	_, _, schema := diabetesFixture(t)
+	header, rows := diabetesLines(t, 300)
+	bad := strings.Split(rows[200], ",")
+	bad[5] = "not-a-number" // bmi
+	rows[200] = strings.Join(bad, ",")
+	input := header + "\n" + strings.Join(rows, "\n") + "\n"
+
+	var out bytes.Buffer
+	err := PredictStream(forest, strings.NewReader(input), &out, StreamOptions{Schema: schema, InputFormat: CSV_FORMAT, Workers: 8})
+	if err == nil || !strings.Contains(err.Error(), "record 201") {
+		t.Fatalf("expected an error on record 201, got %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 201 {
+		t.Fatalf("got %d records before the error, expected the header and 200 rows", len(records))
+	}
+
+	// Una fila con otra cantidad de campos es un error de lectura, reportado en orden.
+	out.Reset()
+	input = header + "\n" + strings.Join(rows[:10], "\n") + "\n1,2\n" + strings.Join(rows[10:20], "\n") + "\n"
+	err = PredictStream(forest, strings.NewReader(input), &out, StreamOptions{Schema: schema, InputFormat: CSV_FORMAT, Workers: 4})
+	if err == nil || !strings.Contains(err.Error(), "record 11") {
+		t.Fatalf("expected an error on record 11, got %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 11 {
+		t.Fatalf("got %d lines before the error, expected 11", lines)
+	}
+}
+
+// Con un empate de votos gana siempre la clase menor, en cada llamada.
+func TestPredicateProbaTie(t *testing.T) {
+	forest := &Forest{Trees: []*Tree{
+		{Root: &TreeNode{Labels: map[string]int{"1": 3}}},
+		{Root: &TreeNode{Labels: map[string]int{"0": 2}}},
+		{Root: &TreeNode{Labels: map[string]int{"2": 1, "1": 1}}},
+		{Root: &TreeNode{Labels: map[string]int{"2": 1, "0": 1}}},
+	}}
+	for i := 0; i < 100; i++ {
+		if label, proba := forest.PredicateProba(nil); label != "0" || proba != 0.375 {
+			t.Fatalf("tie resolved as %s (%v), expected 0 (0.375)", label, proba)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package RF
 import (
 	"math"      // Paquete utilizado para funciones matemáticas como logaritmos.
 	"math/rand" // Paquete para generar números aleatorios.
+	"sort"      // Paquete para ordenar las categorías de las divisiones por subconjunto.
 )
 
 // Declaramos dos constantes que representan tipos de columnas.
@@ -18,6 +19,11 @@ type TreeNode struct {
 	Left     *TreeNode    // Subárbol izquierdo (muestras que cumplen con la condición de división).
 	Right    *TreeNode    // Subárbol derecho (muestras que no cumplen con la condición de división).
 	Labels   map[string]int // Mapa que almacena las etiquetas de las muestras para nodos hoja (finales).
+
+	// Categorías (ordenadas) que van al subárbol izquierdo en una división categórica por
+	// subconjunto; el resto, incluidas las categorías no vistas al entrenar, va a la derecha.
+	// Los modelos anteriores usan `Value` con una única categoría.
+	Categories []string `json:",omitempty"`
 }
 
 // Estructura que representa un árbol de decisión.
@@ -27,7 +33,7 @@ type Tree struct {
 
 // Función que genera un rango de enteros aleatorios entre 0 y N, seleccionando M elementos únicos.
 // Esta función se utiliza para seleccionar un subconjunto aleatorio de características en los nodos del árbol (para Random Forest).
-func getRandomRange(N int, M int) []int {
+func getRandomRange(N int, M int, rng *rand.Rand) []int {
 	tmp := make([]int, N) // Se crea un slice de tamaño N.
 	for i := 0; i < N; i++ {
 		tmp[i] = i // Inicializa el slice con valores secuenciales del 0 al N-1.
@@ -35,7 +41,7 @@ func getRandomRange(N int, M int) []int {
 	// Se seleccionan M valores aleatorios del slice.
 	for i := 0; i < M; i++ {
 		// Intercambia el valor en la posición i con otro valor aleatorio dentro de las posiciones restantes.
-		j := i + int(rand.Float64()*float64(N-i))
+		j := i + int(rng.Float64()*float64(N-i))
 		tmp[i], tmp[j] = tmp[j], tmp[i]
 	}
 
@@ -114,6 +120,11 @@ func getBestGain(samples [][]interface{}, c int, samples_labels []string, column
 	best_total_r := 0          // Número de elementos en la rama derecha
 	best_total_l := 0          // Número de elementos en la rama izquierda
 
+	// Las columnas categóricas se dividen por subconjuntos de categorías
+	if column_type == CAT {
+		return getBestSubset(samples, c, samples_labels, current_entropy)
+	}
+
 	// Almacena los valores únicos de la columna c
 	uniq_values := make(map[interface{}]int)
 	for i := 0; i < len(samples); i++ {
@@ -127,19 +138,7 @@ func getBestGain(samples [][]interface{}, c int, samples_labels []string, column
 		total_l := 0                      // Número de elementos en la rama izquierda
 		total_r := 0                      // Número de elementos en la rama derecha
 
-		// Evaluación según el tipo de columna (categórica o numérica)
-		if column_type == CAT {
-			// Si la columna es categórica
-			for j := 0; j < len(samples); j++ {
-				if samples[j][c] == value {
-					total_l += 1
-					map_l[samples_labels[j]] += 1.0
-				} else {
-					total_r += 1
-					map_r[samples_labels[j]] += 1.0
-				}
-			}
-		}
+		// Evaluación de la columna numérica
 		if column_type == NUMERIC {
 			// Si la columna es numérica
 			for j := 0; j < len(samples); j++ {
@@ -176,13 +175,133 @@ func getBestGain(samples [][]interface{}, c int, samples_labels []string, column
 	return best_gain, best_value, best_total_l, best_total_r
 }
 
+// Función que encuentra el mejor subconjunto de categorías de la columna categórica `c`.
+// Con etiquetas binarias ordena las categorías por su proporción de la primera clase y evalúa
+// solo los prefijos de ese orden: para la entropía, la mejor partición en dos grupos es siempre
+// uno de ellos (Breiman et al.), por lo que basta con k-1 evaluaciones en lugar de 2^(k-1).
+// Con más de dos clases no hay un orden que garantice la mejor partición: se repite lo
+// anterior ordenando por la proporción de cada clase y se agrega cada categoría contra el resto,
+// en total c·(k-1)+k evaluaciones, que pueden no encontrar la partición óptima.
+// El subconjunto devuelto ([]string ordenado) es siempre la rama con menos muestras, de modo que
+// las categorías no vistas al entrenar sigan la rama mayoritaria.
+func getBestSubset(samples [][]interface{}, c int, samples_labels []string, current_entropy float64) (float64, interface{}, int, int) {
+	// Distribución de etiquetas por categoría
+	counts := make(map[string]map[string]float64)
+	totals := make(map[string]int)
+	classes := make(map[string]bool)
+	for j := 0; j < len(samples); j++ {
+		category := samples[j][c].(string)
+		if counts[category] == nil {
+			counts[category] = make(map[string]float64)
+		}
+		counts[category][samples_labels[j]] += 1.0
+		totals[category] += 1
+		classes[samples_labels[j]] = true
+	}
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	if len(categories) < 2 {
+		return 0, nil, 0, 0
+	}
+
+	// Subconjuntos candidatos: los prefijos del orden por proporción de cada clase.
+	// Con dos clases basta con el orden de la primera (el de la segunda es el inverso).
+	labels := make([]string, 0, len(classes))
+	for label := range classes {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	if len(labels) == 2 {
+		labels = labels[:1]
+	}
+	candidates := make([][]string, 0, len(labels)*len(categories))
+	for _, label := range labels {
+		order := append([]string{}, categories...)
+		rate := func(category string) float64 {
+			return counts[category][label] / float64(totals[category])
+		}
+		sort.SliceStable(order, func(i, j int) bool { return rate(order[i]) < rate(order[j]) })
+		for k := 1; k < len(order); k++ {
+			candidates = append(candidates, order[:k])
+		}
+	}
+	if len(classes) > 2 {
+		for k := range categories {
+			candidates = append(candidates, categories[k:k+1])
+		}
+	}
+
+	var best_subset []string
+	best_gain := 0.0
+	best_total_l := 0
+	best_total_r := 0
+	for _, subset := range candidates {
+		map_l := make(map[string]float64) // Distribución de etiquetas a la izquierda
+		map_r := make(map[string]float64) // Distribución de etiquetas a la derecha
+		total_l := 0
+		in_subset := make(map[string]bool, len(subset))
+		for _, category := range subset {
+			in_subset[category] = true
+			total_l += totals[category]
+			for label, v := range counts[category] {
+				map_l[label] += v
+			}
+		}
+		for category, labels := range counts {
+			if !in_subset[category] {
+				for label, v := range labels {
+					map_r[label] += v
+				}
+			}
+		}
+		total_r := len(samples) - total_l
+
+		p1 := float64(total_r) / float64(len(samples))
+		p2 := float64(total_l) / float64(len(samples))
+		entropy_gain := current_entropy - (p1*getEntropy(map_r, total_r) + p2*getEntropy(map_l, total_l))
+		if entropy_gain >= best_gain {
+			best_gain = entropy_gain
+			best_subset = subset
+			best_total_l = total_l
+			best_total_r = total_r
+		}
+	}
+	if best_subset == nil {
+		return 0, nil, 0, 0
+	}
+
+	// La rama izquierda es la de menos muestras
+	in_best := make(map[string]bool, len(best_subset))
+	for _, category := range best_subset {
+		in_best[category] = true
+	}
+	left := make([]string, 0, len(categories))
+	for _, category := range categories {
+		if in_best[category] == (best_total_l <= best_total_r) {
+			left = append(left, category)
+		}
+	}
+	if best_total_l > best_total_r {
+		best_total_l, best_total_r = best_total_r, best_total_l
+	}
+	sort.Strings(left)
+	return best_gain, left, best_total_l, best_total_r
+}
+
 // Función para dividir el conjunto de muestras basado en un valor específico y una columna.
 // Los índices de las muestras que cumplen con la condición se almacenan en part_l y part_r.
 func splitSamples(samples [][]interface{}, column_type string, c int, value interface{}, part_l *[]int, part_r *[]int) {
 	if column_type == CAT {
-		// Si la columna es categórica
+		// Si la columna es categórica, `value` es el subconjunto de categorías de la izquierda
+		in_subset := make(map[string]bool)
+		for _, category := range value.([]string) {
+			in_subset[category] = true
+		}
 		for j := 0; j < len(samples); j++ {
-			if samples[j][c] == value {
+			if in_subset[samples[j][c].(string)] {
 				*part_l = append(*part_l, j)
 			} else {
 				*part_r = append(*part_r, j)
@@ -201,11 +320,12 @@ func splitSamples(samples [][]interface{}, column_type string, c int, value inte
 	}
 }
 
-// Construcción recursiva del árbol de decisión.
-func buildTree(samples [][]interface{}, samples_labels []string, selected_feature_count int) *TreeNode {
+// Construcción recursiva del árbol de decisión. Si `extra` es true, cada columna candidata
+// se evalúa con una única división al azar (ExtraTrees) en lugar de buscar la mejor.
+func buildTree(samples [][]interface{}, samples_labels []string, selected_feature_count int, extra bool, rng *rand.Rand) *TreeNode {
 	column_count := len(samples[0])              // Número total de columnas
 	split_count := selected_feature_count        // Número de características seleccionadas
-	columns_choosen := getRandomRange(column_count, split_count) // Columnas seleccionadas al azar
+	columns_choosen := getRandomRange(column_count, split_count, rng) // Columnas seleccionadas al azar
 
 	best_gain := 0.0
 	var best_part_l []int = make([]int, 0, len(samples)) // Índices de la rama izquierda
@@ -233,7 +353,14 @@ func buildTree(samples [][]interface{}, samples_labels []string, selected_featur
 		}
 
 		// Calcula la ganancia de información para la columna actual
-		gain, value, total_l, total_r := getBestGain(samples, c, samples_labels, column_type, current_entropy)
+		var gain float64
+		var value interface{}
+		var total_l, total_r int
+		if extra {
+			gain, value, total_l, total_r = getRandomSplit(samples, c, samples_labels, column_type, current_entropy, rng)
+		} else {
+			gain, value, total_l, total_r = getBestGain(samples, c, samples_labels, column_type, current_entropy)
+		}
 
 		// Si la ganancia es mejor que la actual, actualiza los mejores valores
 		if gain >= best_gain {
@@ -249,11 +376,15 @@ func buildTree(samples [][]interface{}, samples_labels []string, selected_featur
 	// Si se encuentra una buena división, crea un nodo y divide el conjunto
 	if best_gain > 0 && best_total_l > 0 && best_total_r > 0 {
 		node := &TreeNode{}
-		node.Value = best_value
+		if best_column_type == CAT {
+			node.Categories = best_value.([]string)
+		} else {
+			node.Value = best_value
+		}
 		node.ColumnNo = best_column
 		splitSamples(samples, best_column_type, best_column, best_value, &best_part_l, &best_part_r)
-		node.Left = buildTree(getSamples(samples, best_part_l), getLabels(samples_labels, best_part_l), selected_feature_count)
-		node.Right = buildTree(getSamples(samples, best_part_r), getLabels(samples_labels, best_part_r), selected_feature_count)
+		node.Left = buildTree(getSamples(samples, best_part_l), getLabels(samples_labels, best_part_l), selected_feature_count, extra, rng)
+		node.Right = buildTree(getSamples(samples, best_part_r), getLabels(samples_labels, best_part_r), selected_feature_count, extra, rng)
 		return node
 	}
 
@@ -282,6 +413,16 @@ func predicate(node *TreeNode, input []interface{}) map[string]int {
 	c := node.ColumnNo
 	value := input[c]
 
+	// División por subconjunto de categorías
+	if node.Categories != nil {
+		if node.GoesLeft(value) && node.Left != nil {
+			return predicate(node.Left, input)
+		} else if node.Right != nil {
+			return predicate(node.Right, input)
+		}
+		return nil
+	}
+
 	// Según el tipo de dato de la columna, continúa la predicción
 	switch value.(type) {
 	case float64:
@@ -301,25 +442,65 @@ func predicate(node *TreeNode, input []interface{}) map[string]int {
 	return nil
 }
 
+// `GoesLeft` indica si `value` cumple la condición de división del nodo (y por lo tanto
+// sigue por el subárbol izquierdo). Los valores de tipo distinto al de la división y las
+// categorías que no están en el subconjunto no la cumplen.
+func (self *TreeNode) GoesLeft(value interface{}) bool {
+	return SplitGoesLeft(value, self.Value, self.Categories)
+}
+
+// `SplitGoesLeft` evalúa una condición de división: `value <= split` si `split` es numérico,
+// pertenencia a `categories` (ordenadas) si hay subconjunto, o `value == split` en otro caso.
+// La comparten todos los árboles que usan el mismo formato de nodo (por ejemplo, GBM).
+func SplitGoesLeft(value interface{}, split interface{}, categories []string) bool {
+	switch value := value.(type) {
+	case float64:
+		threshold, ok := split.(float64)
+		return ok && value <= threshold
+	case string:
+		if categories != nil {
+			i := sort.SearchStrings(categories, value)
+			return i < len(categories) && categories[i] == value
+		}
+		return value == split
+	}
+	return false
+}
+
 // Función que construye un árbol a partir de las entradas y etiquetas proporcionadas.
 func BuildTree(inputs [][]interface{}, labels []string, samples_count, selected_feature_count int) *Tree {
+	return newTree(inputs, labels, samples_count, selected_feature_count, false, rand.New(rand.NewSource(rand.Int63())))
+}
 
-	// Selecciona una muestra aleatoria del conjunto de datos
-	samples := make([][]interface{}, samples_count)
-	samples_labels := make([]string, samples_count)
-	for i := 0; i < samples_count; i++ {
-		j := int(rand.Float64() * float64(len(inputs)))
-		samples[i] = inputs[j]
-		samples_labels[i] = labels[j]
+// Construye un árbol usando el generador aleatorio `rng` para el muestreo y la selección de columnas.
+// Con `extra` construye un árbol extremadamente aleatorio (ver `getRandomSplit`).
+func newTree(inputs [][]interface{}, labels []string, samples_count, selected_feature_count int, extra bool, rng *rand.Rand) *Tree {
+	// La cantidad de características no puede superar la cantidad de columnas.
+	if selected_feature_count > len(inputs[0]) {
+		selected_feature_count = len(inputs[0])
 	}
 
+	// Selecciona una muestra aleatoria del conjunto de datos
+	index := bootstrapIndex(len(inputs), samples_count, rng)
+	samples := getSamples(inputs, index)
+	samples_labels := getLabels(labels, index)
+
 	// Crea y construye el árbol
 	tree := &Tree{}
-	tree.Root = buildTree(samples, samples_labels, selected_feature_count)
+	tree.Root = buildTree(samples, samples_labels, selected_feature_count, extra, rng)
 
 	return tree
 }
 
+// Elige `count` índices al azar entre 0 y `total` - 1, con reemplazo (muestreo bootstrap).
+func bootstrapIndex(total, count int, rng *rand.Rand) []int {
+	index := make([]int, count)
+	for i := 0; i < count; i++ {
+		index[i] = int(rng.Float64() * float64(total))
+	}
+	return index
+}
+
 // Función para predecir la clase de una entrada utilizando el árbol construido.
 func PredicateTree(tree *Tree, input []interface{}) map[string]int {
 	return predicate(tree.Root, input)
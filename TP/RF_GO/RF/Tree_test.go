@@ -0,0 +1,132 @@
+package RF
+
+import (
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Entropía de la distribución de `labels`, como la calcula el árbol antes de dividir.
+func labelsEntropy(labels []string) float64 {
+	counts := make(map[string]float64)
+	for _, label := range labels {
+		counts[label] += 1
+	}
+	return getEntropy(counts, len(labels))
+}
+
+// Crea `n` registros de la categoría `category` con `positives` etiquetas "1" y el resto "0".
+func categoryRows(category string, n, positives int, inputs *[][]interface{}, labels *[]string) {
+	for i := 0; i < n; i++ {
+		*inputs = append(*inputs, []interface{}{category})
+		if i < positives {
+			*labels = append(*labels, "1")
+		} else {
+			*labels = append(*labels, "0")
+		}
+	}
+}
+
+// Con etiquetas binarias, la mejor división agrupa las categorías con proporciones parecidas
+// aunque no sean vecinas alfabéticamente, y la rama izquierda es la de menos muestras.
+func TestBestSubsetBinary(t *testing.T) {
+	var inputs [][]interface{}
+	var labels []string
+	categoryRows("a", 20, 18, &inputs, &labels)
+	categoryRows("b", 40, 4, &inputs, &labels)
+	categoryRows("c", 20, 17, &inputs, &labels)
+	categoryRows("d", 40, 2, &inputs, &labels)
+	categoryRows("e", 10, 9, &inputs, &labels)
+
+	gain, value, total_l, total_r := getBestSubset(inputs, 0, labels, labelsEntropy(labels))
+	if !reflect.DeepEqual(value, []string{"a", "c", "e"}) || total_l != 50 || total_r != 80 {
+		t.Fatalf("best subset %v with %d/%d samples", value, total_l, total_r)
+	}
+	if gain <= 0 {
+		t.Fatalf("gain %v", gain)
+	}
+}
+
+// Con más de dos clases, la heurística encuentra una división que agrupa varias categorías,
+// mejor que cualquier división de una categoría contra el resto.
+func TestBestSubsetMulticlass(t *testing.T) {
+	var inputs [][]interface{}
+	var labels []string
+	for _, row := range []struct {
+		category string
+		label    string
+		n        int
+	}{{"a", "x", 10}, {"b", "x", 10}, {"c", "y", 10}, {"d", "z", 10}} {
+		for i := 0; i < row.n; i++ {
+			inputs = append(inputs, []interface{}{row.category})
+			labels = append(labels, row.label)
+		}
+	}
+	_, value, total_l, total_r := getBestSubset(inputs, 0, labels, labelsEntropy(labels))
+	subset := value.([]string)
+	if len(subset) != 2 || total_l != 20 || total_r != 20 {
+		t.Fatalf("best subset %v with %d/%d samples, expected {a, b} against {c, d}", subset, total_l, total_r)
+	}
+	if !reflect.DeepEqual(subset, []string{"a", "b"}) && !reflect.DeepEqual(subset, []string{"c", "d"}) {
+		t.Fatalf("best subset %v, expected {a, b} or {c, d}", subset)
+	}
+}
+
+// Un bosque con divisiones por subconjunto conserva sus subconjuntos y sus predicciones al
+// guardarse y cargarse, y una categoría no vista al entrenar sigue la rama con más muestras.
+func TestSubsetSplitPersistence(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	var inputs [][]interface{}
+	var labels []string
+	for i := 0; i < 2000; i++ {
+		category := []string{"a", "b", "c", "d", "e", "f"}[rng.Intn(6)]
+		label := "0"
+		if (category == "b" || category == "e") != (rng.Float64() < 0.05) {
+			label = "1"
+		}
+		inputs = append(inputs, []interface{}{category, rng.Float64()})
+		labels = append(labels, label)
+	}
+	schema, err := ParseSchema("group:cat,noise:numeric")
+	if err != nil {
+		t.Fatal(err)
+	}
+	forest := BuildForestWithConfig(inputs, labels, ForestConfig{
+		TreesAmount:           3,
+		SamplesAmount:         1000,
+		SelectedFeatureAmount: 2,
+		Seed:                  5,
+		Quiet:                 true,
+		Schema:                schema,
+	})
+
+	root := forest.Trees[0].Root
+	if root.ColumnNo != 0 || !reflect.DeepEqual(root.Categories, []string{"b", "e"}) {
+		t.Fatalf("root splits column %d on %v, expected the subset {b, e}", root.ColumnNo, root.Categories)
+	}
+	left := countSamples(subtreeCounts(root.Left))
+	right := countSamples(subtreeCounts(root.Right))
+	if left > right || root.GoesLeft("unseen") {
+		t.Fatalf("an unseen category does not follow the larger branch (%d left, %d right)", left, right)
+	}
+
+	fileName := filepath.Join(t.TempDir(), "forest.json")
+	DumpForest(forest, fileName)
+	loaded, err := ReadForest(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Trees[0].Root.Categories, root.Categories) {
+		t.Fatalf("loaded subset %v, saved %v", loaded.Trees[0].Root.Categories, root.Categories)
+	}
+	for _, category := range []string{"a", "b", "c", "d", "e", "f", "unseen"} {
+		input := []interface{}{category, 0.5}
+		if !reflect.DeepEqual(loaded.Probabilities(input), forest.Probabilities(input)) {
+			t.Fatalf("category %s: loaded %v, saved %v", category, loaded.Probabilities(input), forest.Probabilities(input))
+		}
+	}
+	if label := loaded.Predicate([]interface{}{"unseen", 0.5}); label != "0" {
+		t.Fatalf("an unseen category predicted %s, expected the majority class 0", label)
+	}
+}
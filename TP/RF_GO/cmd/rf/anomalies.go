@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"tp-test/RF"
+)
+
+// Subcomando `anomalies`: entrena un bosque de aislamiento sobre un CSV y escribe las filas
+// marcadas como anómalas (con su puntaje), en el orden del archivo.
+func runAnomalies(args []string) int {
+	flags := flag.NewFlagSet("anomalies", flag.ContinueOnError)
+	dataPath := flags.String("data", "diabetes.csv", "conjunto de datos CSV con cabecera")
+	target := flags.String("target", "", "columna excluida de las características, como la etiqueta (por defecto, la última)")
+	schemaSpec := flags.String("schema", "", "archivo JSON de esquema o lista \"columna:tipo,...\" con al menos una columna numérica (obligatorio)")
+	trees := flags.Int("trees", 100, "cantidad de árboles")
+	sample := flags.Int("sample", 256, "muestras por árbol")
+	contamination := flags.Float64("contamination", 0.01, "fracción de registros que se marcan como anómalos")
+	seed := flags.Int64("seed", 0, "semilla aleatoria (0: se toma de la hora actual y se muestra al terminar)")
+	outPath := flags.String("o", "", "archivo CSV de salida (por defecto, stdout)")
+	modelPath := flags.String("model", "", "archivo donde se guarda el bosque de aislamiento (opcional)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	schema, err := loadSchema(*schemaSpec)
+	if err != nil {
+		return fail("anomalies", err)
+	}
+	// Con todas las columnas categóricas los valores extremos no se aíslan antes que los demás.
+	numeric := false
+	if schema != nil {
+		for _, column := range schema.Columns {
+			numeric = numeric || column.Type == RF.NUMERIC
+		}
+	}
+	if !numeric {
+		return fail("anomalies", errors.New("-schema must declare at least one numeric column, for example \"age:numeric,bmi:numeric\""))
+	}
+	inputs, _, schema, err := RF.LoadCSV(*dataPath, *target, schema)
+	if err != nil {
+		return fail("anomalies", err)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	forest := RF.BuildIsolationForest(inputs, RF.IsolationConfig{
+		TreesAmount:   *trees,
+		SampleSize:    *sample,
+		Contamination: *contamination,
+		Seed:          *seed,
+		Quiet:         true,
+		Schema:        schema,
+	})
+	if *modelPath != "" {
+		if err := RF.WriteJSON(*modelPath, forest); err != nil {
+			return fail("anomalies", err)
+		}
+	}
+	scores := forest.Scores(inputs, 0)
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		out_f, err := os.Create(*outPath)
+		if err != nil {
+			return fail("anomalies", err)
+		}
+		defer out_f.Close()
+		out = out_f
+	}
+
+	// Se vuelve a leer el archivo para copiar las filas tal como están.
+	in_f, err := os.Open(*dataPath)
+	if err != nil {
+		return fail("anomalies", err)
+	}
+	defer in_f.Close()
+	reader := csv.NewReader(in_f)
+	writer := csv.NewWriter(out)
+	header, err := reader.Read()
+	if err != nil {
+		return fail("anomalies", err)
+	}
+	writer.Write(append(header, "anomaly_score"))
+	flagged := 0
+	for i := 0; i < len(inputs); i++ {
+		fields, err := reader.Read()
+		if err != nil {
+			return fail("anomalies", err)
+		}
+		if scores[i] >= forest.Threshold {
+			writer.Write(append(fields, strconv.FormatFloat(scores[i], 'f', 4, 64)))
+			flagged += 1
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fail("anomalies", err)
+	}
+	fmt.Fprintf(os.Stderr, "flagged %d of %d records (threshold %.4f, seed %d)\n", flagged, len(inputs), forest.Threshold, *seed)
+	return 0
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"tp-test/GBM"
+	"tp-test/RF"
+)
+
+// Subcomando `boost`: entrena un clasificador de gradient boosting y lo guarda en JSON.
+func runBoost(args []string) int {
+	defaults := GBM.DefaultConfig()
+	flags := flag.NewFlagSet("boost", flag.ContinueOnError)
+	dataPath := flags.String("data", "diabetes.csv", "conjunto de datos CSV con cabecera")
+	target := flags.String("target", "", "columna de la etiqueta (por defecto, la última)")
+	schemaSpec := flags.String("schema", "", "archivo JSON de esquema o lista \"columna:tipo,...\" (por defecto, todas categóricas)")
+	rounds := flags.Int("rounds", defaults.Rounds, "cantidad máxima de rondas")
+	rate := flags.Float64("rate", defaults.LearningRate, "tasa de aprendizaje")
+	depth := flags.Int("depth", defaults.MaxDepth, "profundidad máxima de cada árbol")
+	minLeaf := flags.Int("min-leaf", defaults.MinSamplesLeaf, "mínimo de registros por hoja")
+	subsample := flags.Float64("subsample", defaults.Subsample, "fracción de registros usada en cada ronda")
+	validation := flags.Float64("validation", 0.1, "fracción de los registros de entrenamiento usada para la parada temprana (0: sin parada temprana)")
+	patience := flags.Int("patience", defaults.Patience, "rondas sin mejora en validación antes de detenerse")
+	workers := flags.Int("workers", 0, "goroutines para buscar divisiones (por defecto, una por CPU)")
+	seed := flags.Int64("seed", 0, "semilla aleatoria (0: se toma de la hora actual y se muestra al terminar)")
+	holdout := flags.Float64("holdout", 0, "fracción de registros reservada para evaluar (0: entrenar con todos)")
+	modelPath := flags.String("model", "gbm.json", "archivo donde se guarda el modelo")
+	quiet := flags.Bool("quiet", false, "no mostrar el progreso del entrenamiento")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *holdout < 0 || *holdout >= 1 || *validation < 0 || *validation >= 1 {
+		return fail("boost", fmt.Errorf("-holdout and -validation must be in [0, 1), got %v and %v", *holdout, *validation))
+	}
+
+	schema, err := loadSchema(*schemaSpec)
+	if err != nil {
+		return fail("boost", err)
+	}
+	inputs, labels, schema, err := RF.LoadCSV(*dataPath, *target, schema)
+	if err != nil {
+		return fail("boost", err)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	train_inputs, train_labels := inputs, labels
+	var test_inputs [][]interface{}
+	var test_labels []string
+	if *holdout > 0 {
+		train_inputs, train_labels, test_inputs, test_labels = RF.SplitDataset(inputs, labels, *holdout, *seed)
+	}
+
+	config := GBM.Config{
+		Rounds:         *rounds,
+		LearningRate:   *rate,
+		MaxDepth:       *depth,
+		MinSamplesLeaf: *minLeaf,
+		Lambda:         defaults.Lambda,
+		Subsample:      *subsample,
+		Workers:        *workers,
+		Seed:           *seed,
+		Quiet:          *quiet,
+		Schema:         schema,
+		Patience:       *patience,
+	}
+	if *validation > 0 {
+		train_inputs, train_labels, config.ValidationInputs, config.ValidationLabels = RF.SplitDataset(train_inputs, train_labels, *validation, *seed+1)
+	}
+
+	start := time.Now()
+	model, err := GBM.Train(train_inputs, train_labels, config)
+	if err != nil {
+		return fail("boost", err)
+	}
+	fmt.Printf("trained %d rounds on %d records in %v (seed %d)\n", len(model.Trees), len(train_inputs), time.Since(start), *seed)
+
+	if err := GBM.DumpModel(model, *modelPath); err != nil {
+		return fail("boost", err)
+	}
+	fmt.Println("model saved to", *modelPath)
+
+	if len(test_inputs) > 0 {
+		fmt.Printf("\nholdout evaluation\n%v", RF.Evaluate(model, test_inputs, test_labels, 0))
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"tp-test/RF"
+)
+
+// Subcomando `codegen`: genera un archivo Go independiente con el bosque como funciones if/else.
+func runCodegen(args []string) int {
+	flags := flag.NewFlagSet("codegen", flag.ContinueOnError)
+	modelPath := flags.String("model", "forest.json", "bosque entrenado")
+	packageName := flags.String("package", "model", "nombre del paquete generado")
+	outPath := flags.String("o", "", "archivo de salida (por defecto, stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	forest, err := RF.ReadForest(*modelPath)
+	if err != nil {
+		return fail("codegen", err)
+	}
+
+	var source bytes.Buffer
+	if err := forest.WriteGo(&source, *packageName); err != nil {
+		return fail("codegen", err)
+	}
+	if *outPath == "" {
+		os.Stdout.Write(source.Bytes())
+		return 0
+	}
+	if err := os.WriteFile(*outPath, source.Bytes(), 0644); err != nil {
+		return fail("codegen", err)
+	}
+	fmt.Printf("generated %s (%d trees, %d bytes)\n", *outPath, len(forest.Trees), source.Len())
+	return 0
+}
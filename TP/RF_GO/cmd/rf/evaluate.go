@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"tp-test/RF"
+)
+
+// Subcomando `evaluate`: muestra exactitud, métricas por clase y matriz de confusión.
+func runEvaluate(args []string) int {
+	flags := flag.NewFlagSet("evaluate", flag.ContinueOnError)
+	modelPath := flags.String("model", "forest.json", "bosque entrenado")
+	dataPath := flags.String("data", "diabetes.csv", "conjunto de datos CSV etiquetado")
+	target := flags.String("target", "", "columna de la etiqueta (por defecto, la última)")
+	schemaSpec := flags.String("schema", "", "archivo JSON de esquema o lista \"columna:tipo,...\" (por defecto, el guardado en el bosque)")
+	workers := flags.Int("workers", 0, "cantidad de goroutines de predicción (por defecto, una por CPU)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	forest, err := RF.ReadForest(*modelPath)
+	if err != nil {
+		return fail("evaluate", err)
+	}
+	schema, err := loadSchema(*schemaSpec)
+	if err != nil {
+		return fail("evaluate", err)
+	}
+	if schema == nil {
+		schema = forest.Schema
+	}
+	inputs, labels, _, err := RF.LoadCSV(*dataPath, *target, schema)
+	if err != nil {
+		return fail("evaluate", err)
+	}
+
+	report := RF.Evaluate(forest, inputs, labels, *workers)
+	fmt.Print(report)
+
+	// Con dos clases se agrega el AUC de la probabilidad de la segunda (por ejemplo, "1").
+	if len(report.Labels) == 2 {
+		positive := report.Labels[1]
+		scores := make([]float64, len(inputs))
+		for i, input := range inputs {
+			scores[i] = forest.Probabilities(input)[positive]
+		}
+		fmt.Printf("\nauc (%s): %.4f\n", positive, RF.AUC(scores, labels, positive))
+	}
+	return 0
+}
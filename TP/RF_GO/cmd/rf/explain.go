@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"tp-test/RF"
+)
+
+// Subcomando `explain`: lee registros JSON Lines de stdin y explica cada predicción
+// con el camino recorrido en cada árbol y la contribución de cada columna.
+func runExplain(args []string) int {
+	flags := flag.NewFlagSet("explain", flag.ContinueOnError)
+	modelPath := flags.String("model", "forest.json", "bosque entrenado")
+	schemaSpec := flags.String("schema", "", "archivo JSON de esquema o lista \"columna:tipo,...\" (por defecto, el guardado en el bosque)")
+	format := flags.String("format", "text", "salida: text o json (una explicación por línea)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "text" && *format != "json" {
+		return fail("explain", fmt.Errorf("unknown format %q", *format))
+	}
+
+	forest, err := RF.ReadForest(*modelPath)
+	if err != nil {
+		return fail("explain", err)
+	}
+	schema, err := loadSchema(*schemaSpec)
+	if err != nil {
+		return fail("explain", err)
+	}
+	if schema == nil {
+		schema = forest.Schema
+	}
+	if schema == nil {
+		return fail("explain", fmt.Errorf("forest has no schema; use -schema"))
+	}
+	if forest.Schema == nil {
+		forest.Schema = schema
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		record := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return fail("explain", fmt.Errorf("record %d: %v", line, err))
+		}
+		input, err := schema.Convert(record)
+		if err != nil {
+			return fail("explain", fmt.Errorf("record %d: %v", line, err))
+		}
+		explanation := forest.Explain(input)
+		if *format == "json" {
+			encoder.Encode(explanation)
+		} else {
+			fmt.Println(explanation)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fail("explain", err)
+	}
+	return 0
+}
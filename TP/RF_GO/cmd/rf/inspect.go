@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"tp-test/RF"
+)
+
+// Subcomando `inspect`: muestra estadísticas de los árboles del bosque o exporta
+// un árbol como Graphviz DOT o como reglas if/else.
+func runInspect(args []string) int {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	modelPath := flags.String("model", "forest.json", "bosque entrenado")
+	format := flags.String("format", "stats", "salida: stats (estadísticas), dot (Graphviz) o rules (reglas if/else)")
+	treeIndex := flags.Int("tree", 0, "árbol a exportar con -format dot o rules (-1: todos)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	forest, err := RF.ReadForest(*modelPath)
+	if err != nil {
+		return fail("inspect", err)
+	}
+
+	if *format == "stats" {
+		printStats(forest)
+		return 0
+	}
+	if *format != "dot" && *format != "rules" {
+		return fail("inspect", fmt.Errorf("unknown format %q", *format))
+	}
+
+	trees := forest.Trees
+	if *treeIndex >= 0 {
+		if *treeIndex >= len(forest.Trees) {
+			return fail("inspect", fmt.Errorf("tree %d out of range (forest has %d trees)", *treeIndex, len(forest.Trees)))
+		}
+		trees = forest.Trees[*treeIndex : *treeIndex+1]
+	}
+	names := forest.ColumnNames()
+	for i, tree := range trees {
+		if *format == "dot" {
+			err = tree.WriteDOT(os.Stdout, names)
+		} else {
+			if len(trees) > 1 {
+				fmt.Printf("# tree %d\n", i)
+			}
+			err = tree.WriteRules(os.Stdout, names)
+		}
+		if err != nil {
+			return fail("inspect", err)
+		}
+	}
+	return 0
+}
+
+// Imprime el esquema del bosque y la profundidad, nodos y hojas de cada árbol.
+func printStats(forest *RF.Forest) {
+	// Columnas que espera el bosque, con los valores observados en el entrenamiento.
+	if forest.Schema != nil {
+		fmt.Printf("%-22s %-8s %s\n", "column", "type", "observed")
+		for _, column := range forest.Schema.Columns {
+			observed := strings.Join(column.Categories, ", ")
+			if column.Min != nil && column.Max != nil {
+				observed = fmt.Sprintf("[%v, %v]", *column.Min, *column.Max)
+			}
+			fmt.Printf("%-22s %-8s %s\n", column.Name, column.Type, observed)
+		}
+		fmt.Println()
+	}
+
+	stats := forest.Stats()
+	fmt.Printf("%-6s %8s %8s %8s\n", "tree", "depth", "nodes", "leaves")
+	total := RF.TreeStats{}
+	for i, s := range stats {
+		fmt.Printf("%-6d %8d %8d %8d\n", i, s.Depth, s.Nodes, s.Leaves)
+		total.Depth += s.Depth
+		total.Nodes += s.Nodes
+		total.Leaves += s.Leaves
+	}
+	if n := float64(len(stats)); n > 0 {
+		fmt.Printf("%-6s %8.1f %8.1f %8.1f\n", "mean", float64(total.Depth)/n, float64(total.Nodes)/n, float64(total.Leaves)/n)
+	}
+}
@@ -0,0 +1,71 @@
+// Comando `rf`: entrena, evalúa, predice e inspecciona bosques aleatorios
+// sin necesidad de modificar el código para cada ejecución.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"tp-test/RF"
+)
+
+// Subcomandos disponibles, con su descripción para la ayuda.
+var commands = []struct {
+	name        string
+	description string
+	run         func(args []string) int
+}{
+	{"train", "entrena un bosque a partir de un CSV y lo guarda en JSON", runTrain},
+	{"evaluate", "muestra las métricas de un bosque sobre un CSV etiquetado", runEvaluate},
+	{"predict", "predice registros CSV o JSON Lines leídos de stdin", runPredict},
+	{"inspect", "muestra estadísticas de los árboles de un bosque", runInspect},
+	{"explain", "explica predicciones: caminos y contribución de cada columna", runExplain},
+	{"codegen", "genera código Go independiente a partir de un bosque", runCodegen},
+	{"boost", "entrena un clasificador de gradient boosting (GBM) a partir de un CSV", runBoost},
+	{"anomalies", "marca registros anómalos de un CSV con un bosque de aislamiento", runAnomalies},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, command := range commands {
+		if command.name == os.Args[1] {
+			os.Exit(command.run(os.Args[2:]))
+		}
+	}
+	if os.Args[1] != "help" && os.Args[1] != "-h" && os.Args[1] != "--help" {
+		fmt.Fprintf(os.Stderr, "rf: unknown command %q\n\n", os.Args[1])
+	}
+	usage()
+	os.Exit(2)
+}
+
+// Imprime la ayuda general del comando.
+func usage() {
+	fmt.Fprintln(os.Stderr, "uso: rf <comando> [opciones]")
+	fmt.Fprintln(os.Stderr, "\ncomandos:")
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", command.name, command.description)
+	}
+	fmt.Fprintln(os.Stderr, "\nUse \"rf <comando> -h\" para ver las opciones de cada comando.")
+}
+
+// Carga el esquema indicado por la opción `-schema`: un archivo JSON si existe,
+// o una lista "columna:tipo,...". Devuelve nil si la opción está vacía.
+func loadSchema(spec string) (*RF.Schema, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(spec); err == nil {
+		return RF.LoadSchema(spec)
+	}
+	return RF.ParseSchema(spec)
+}
+
+// Imprime un error del subcomando `command` y devuelve el código de salida.
+func fail(command string, err error) int {
+	fmt.Fprintf(os.Stderr, "rf %s: %v\n", command, err)
+	return 1
+}
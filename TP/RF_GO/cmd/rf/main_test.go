@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tp-test/RF"
+)
+
+// Ejecuta el subcomando `run` con `stdin` como entrada estándar y devuelve su código de salida
+// y lo que escribió en la salida estándar.
+func runCommand(t *testing.T, run func(args []string) int, stdin string, args ...string) (int, string) {
+	dir := t.TempDir()
+	in_f, err := os.Create(filepath.Join(dir, "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in_f.Close()
+	in_f.WriteString(stdin)
+	in_f.Seek(0, 0)
+	out_f, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out_f.Close()
+
+	old_stdin, old_stdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = in_f, out_f
+	code := run(args)
+	os.Stdin, os.Stdout = old_stdin, old_stdout
+
+	out, err := os.ReadFile(out_f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code, string(out)
+}
+
+// Un bosque entrenado sin `-schema` predice valores numéricos que no aparecieron al entrenar,
+// mientras que uno con la columna declarada categórica rechaza las categorías desconocidas.
+func TestTrainPredictUnseenValues(t *testing.T) {
+	model := filepath.Join(t.TempDir(), "forest.json")
+	code, _ := runCommand(t, runTrain, "", "-data", "../../diabetes.csv", "-trees", "5", "-samples", "300", "-seed", "1", "-quiet", "-model", model)
+	if code != 0 {
+		t.Fatalf("train exited with %d", code)
+	}
+
+	input := "gender,age,hypertension,heart_disease,smoking_history,bmi,HbA1c_level,blood_glucose_level\n" +
+		"Female,44.5,0,0,never,25.1977,6.05,143\n" +
+		"Male,80.0,1,0,former,31.4159,7.1,260\n"
+	code, out := runCommand(t, runPredict, input, "-model", model)
+	if code != 0 {
+		t.Fatalf("predict exited with %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], ",prediction,probability") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Split(line, ",")
+		if label := fields[len(fields)-2]; label != "0" && label != "1" {
+			t.Fatalf("unexpected prediction in %q", line)
+		}
+	}
+
+	declared := filepath.Join(t.TempDir(), "declared.json")
+	code, _ = runCommand(t, runTrain, "", "-data", "../../diabetes.csv", "-schema", RF.DIABETES_SCHEMA, "-trees", "5", "-samples", "300", "-seed", "1", "-quiet", "-model", declared)
+	if code != 0 {
+		t.Fatalf("train exited with %d", code)
+	}
+	if code, _ := runCommand(t, runPredict, input, "-model", declared); code != 0 {
+		t.Fatalf("predict with a declared schema exited with %d", code)
+	}
+	unknown := strings.Replace(input, "never", "sometimes", 1)
+	if code, _ := runCommand(t, runPredict, unknown, "-model", declared); code != 1 {
+		t.Fatalf("predict of an unknown declared category exited with %d, expected 1", code)
+	}
+}
+
+// `anomalies` rechaza el esquema por defecto, en el que todas las columnas son categóricas.
+func TestAnomaliesRequiresNumericSchema(t *testing.T) {
+	if code, _ := runCommand(t, runAnomalies, "", "-data", "../../diabetes.csv", "-trees", "5"); code != 1 {
+		t.Fatalf("anomalies without -schema exited with %d, expected 1", code)
+	}
+	if code, _ := runCommand(t, runAnomalies, "", "-data", "../../diabetes.csv", "-schema", "gender,smoking_history", "-trees", "5"); code != 1 {
+		t.Fatalf("anomalies with only categorical columns exited with %d, expected 1", code)
+	}
+	out := filepath.Join(t.TempDir(), "anomalies.csv")
+	code, _ := runCommand(t, runAnomalies, "", "-data", "../../diabetes.csv", "-schema", "age:numeric,bmi:numeric", "-trees", "5", "-seed", "1", "-o", out)
+	if code != 0 {
+		t.Fatalf("anomalies exited with %d", code)
+	}
+	flagged, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(flagged), "\n"); lines < 2 {
+		t.Fatalf("no record was flagged:\n%s", flagged)
+	}
+}
+
+// Una fracción de `-holdout` fuera de [0, 1) o un modelo que no se puede guardar terminan con
+// un error en lugar de un pánico.
+func TestTrainRejectsInvalidOptions(t *testing.T) {
+	for _, holdout := range []string{"1", "1.5", "-0.1"} {
+		if code, _ := runCommand(t, runTrain, "", "-data", "../../diabetes.csv", "-holdout", holdout, "-quiet"); code != 1 {
+			t.Fatalf("train -holdout %s exited with %d, expected 1", holdout, code)
+		}
+	}
+	model := filepath.Join(t.TempDir(), "missing", "forest.json")
+	if code, _ := runCommand(t, runTrain, "", "-data", "../../diabetes.csv", "-trees", "2", "-samples", "100", "-seed", "1", "-quiet", "-model", model); code != 1 {
+		t.Fatalf("train into a missing directory exited with %d, expected 1", code)
+	}
+}
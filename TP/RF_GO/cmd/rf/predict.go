@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"tp-test/RF"
+)
+
+// Subcomando `predict`: lee registros CSV o JSON Lines de stdin, los predice con un bosque
+// guardado y escribe en stdout cada registro con las columnas de predicción y probabilidad.
+func runPredict(args []string) int {
+	flags := flag.NewFlagSet("predict", flag.ContinueOnError)
+	modelPath := flags.String("model", "forest.json", "bosque entrenado (JSON generado por DumpForest)")
+	schemaSpec := flags.String("schema", "", "archivo JSON de esquema o lista \"columna:tipo,...\" (por defecto, el guardado en el bosque)")
+	inFormat := flags.String("in", RF.CSV_FORMAT, "formato de entrada: csv o jsonl")
+	outFormat := flags.String("out", "", "formato de salida: csv o jsonl (por defecto, el de entrada)")
+	workers := flags.Int("workers", 0, "cantidad de goroutines de predicción (por defecto, una por CPU)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	forest, err := RF.ReadForest(*modelPath)
+	if err != nil {
+		return fail("predict", err)
+	}
+	schema, err := loadSchema(*schemaSpec)
+	if err != nil {
+		return fail("predict", err)
+	}
+	// Sin esquema explícito se usa el del bosque, que valida las categorías.
+	if schema == nil {
+		schema = forest.Schema
+	}
+
+	err = RF.PredictStream(forest, os.Stdin, os.Stdout, RF.StreamOptions{
+		Schema:       schema,
+		InputFormat:  *inFormat,
+		OutputFormat: *outFormat,
+		Workers:      *workers,
+	})
+	if err != nil {
+		return fail("predict", err)
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+
+	"tp-test/RF"
+)
+
+// Subcomando `train`: entrena un bosque y lo guarda con `WriteForest`.
+func runTrain(args []string) int {
+	flags := flag.NewFlagSet("train", flag.ContinueOnError)
+	dataPath := flags.String("data", "diabetes.csv", "conjunto de datos CSV con cabecera")
+	target := flags.String("target", "", "columna de la etiqueta (por defecto, la última)")
+	schemaSpec := flags.String("schema", "", "archivo JSON de esquema o lista \"columna:tipo,...\" (por defecto, todas categóricas)")
+	trees := flags.Int("trees", 10, "cantidad de árboles")
+	samples := flags.Int("samples", 500, "muestras por árbol (0: tantas como registros)")
+	features := flags.Int("features", 0, "características evaluadas por nodo (0: raíz cuadrada del total)")
+	seed := flags.Int64("seed", 0, "semilla aleatoria (0: se toma de la hora actual y se muestra al terminar)")
+	holdout := flags.Float64("holdout", 0, "fracción de registros reservada para evaluar (0: entrenar con todos)")
+	modelPath := flags.String("model", "forest.json", "archivo donde se guarda el bosque")
+	quiet := flags.Bool("quiet", false, "no mostrar el progreso del entrenamiento")
+	extra := flags.Bool("extra", false, "árboles extremadamente aleatorios: umbrales al azar en lugar del mejor (ExtraTrees)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *holdout < 0 || *holdout >= 1 {
+		return fail("train", fmt.Errorf("-holdout must be in [0, 1), got %v", *holdout))
+	}
+
+	schema, err := loadSchema(*schemaSpec)
+	if err != nil {
+		return fail("train", err)
+	}
+	inputs, labels, schema, err := RF.LoadCSV(*dataPath, *target, schema)
+	if err != nil {
+		return fail("train", err)
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	train_inputs, train_labels := inputs, labels
+	var test_inputs [][]interface{}
+	var test_labels []string
+	if *holdout > 0 {
+		train_inputs, train_labels, test_inputs, test_labels = RF.SplitDataset(inputs, labels, *holdout, *seed)
+	}
+
+	config := RF.ForestConfig{
+		TreesAmount:           *trees,
+		SamplesAmount:         *samples,
+		SelectedFeatureAmount: *features,
+		Seed:                  *seed,
+		Quiet:                 *quiet,
+		Schema:                schema,
+		ExtraTrees:            *extra,
+	}
+	if config.SamplesAmount <= 0 {
+		config.SamplesAmount = len(train_inputs)
+	}
+	if config.SelectedFeatureAmount <= 0 {
+		config.SelectedFeatureAmount = int(math.Sqrt(float64(len(schema.Columns))))
+	}
+
+	start := time.Now()
+	forest := RF.BuildForestWithConfig(train_inputs, train_labels, config)
+	fmt.Printf("trained %d trees on %d records in %v (seed %d)\n", config.TreesAmount, len(train_inputs), time.Since(start), *seed)
+
+	if err := RF.WriteForest(forest, *modelPath); err != nil {
+		return fail("train", err)
+	}
+	fmt.Println("model saved to", *modelPath)
+
+	if len(test_inputs) > 0 {
+		fmt.Printf("\nholdout evaluation\n%v", RF.Evaluate(forest, test_inputs, test_labels, 0))
+	}
+	return 0
+}
@@ -0,0 +1,192 @@
+// Package DL implementa la red neuronal de DL_Secuencial y DL_Concurrente (una capa oculta
+// y una salida sigmoide) y un perceptrón multicapa general (`MLP`) con capas de cualquier
+// tamaño, sesgos, activaciones ReLU, tanh, sigmoide y softmax, y pérdidas de entropía
+// cruzada y error cuadrático. Ambas se entrenan por mini-lotes, secuencialmente o con
+// paralelismo de datos según `Workers`.
+package DL
+
+import (
+	"fmt"
+	"math"
+
+	"pc2/ML"
+)
+
+// Estructura `RedNeuronal` con los parámetros de entrenamiento y los pesos aprendidos.
+type RedNeuronal struct {
+	Ocultas         int
+	Epocas          int // 10 si es 0
+	TasaAprendizaje float64
+	TamañoLote      int   // Ejemplos por paso de gradiente; 32 si es 0
+	Workers         int   // Goroutines que calculan el gradiente de cada mini-lote; con 1 o menos se entrena secuencialmente
+	Semilla         int64 // Semilla de los pesos iniciales; si es 0 se usa la hora actual
+
+	Entradas     int
+	Salidas      int
+	PesosOcultos [][]float64
+	PesosSalidas [][]float64
+}
+
+// Inicializar los pesos de la red neuronal con valores aleatorios
+func (red *RedNeuronal) inicializarPesos() {
+	rng := ML.NewRand(red.Semilla)
+	red.PesosOcultos = ML.NewMatrix(red.Ocultas, red.Entradas)
+	for i := range red.PesosOcultos {
+		for j := range red.PesosOcultos[i] {
+			red.PesosOcultos[i][j] = rng.Float64() * 0.1
+		}
+	}
+
+	red.PesosSalidas = ML.NewMatrix(red.Salidas, red.Ocultas)
+	for i := range red.PesosSalidas {
+		for j := range red.PesosSalidas[i] {
+			red.PesosSalidas[i][j] = rng.Float64() * 0.1
+		}
+	}
+}
+
+// Función de activación Sigmoid
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+// Derivada de la función de activación Sigmoid, a partir de su valor ya activado
+func derivadaSigmoid(sig float64) float64 {
+	return sig * (1 - sig)
+}
+
+// Propagación hacia adelante
+func (red *RedNeuronal) propagacionHaciaAdelante(entrada []float64) ([]float64, []float64) {
+	ocultas := make([]float64, red.Ocultas)
+	salidas := make([]float64, red.Salidas)
+
+	for i := 0; i < red.Ocultas; i++ {
+		sum := 0.0
+		for j := 0; j < red.Entradas; j++ {
+			sum += entrada[j] * red.PesosOcultos[i][j]
+		}
+		ocultas[i] = sigmoid(sum)
+	}
+
+	for i := 0; i < red.Salidas; i++ {
+		sum := 0.0
+		for j := 0; j < red.Ocultas; j++ {
+			sum += ocultas[j] * red.PesosSalidas[i][j]
+		}
+		salidas[i] = sigmoid(sum)
+	}
+
+	return salidas, ocultas
+}
+
+// Pesos de la red como vectores: las filas de `PesosOcultos` y luego las de `PesosSalidas`.
+func (red *RedNeuronal) parametros() [][]float64 {
+	return append(append([][]float64{}, red.PesosOcultos...), red.PesosSalidas...)
+}
+
+// Retropropagación: suma en `g` (con la forma de `parametros`) el gradiente del error
+// cuadrático ½(salida - esperada)² del ejemplo y devuelve ese error. Solo lee los pesos.
+func (red *RedNeuronal) retropropagacion(entrada []float64, salidaEsperada float64, g [][]float64) float64 {
+	salida, ocultas := red.propagacionHaciaAdelante(entrada)
+	error := salida[0] - salidaEsperada
+	deltaSalida := error * derivadaSigmoid(salida[0])
+
+	// Gradiente de los pesos de salida
+	for i := 0; i < red.Salidas; i++ {
+		for j := 0; j < red.Ocultas; j++ {
+			g[red.Ocultas+i][j] += deltaSalida * ocultas[j]
+		}
+	}
+
+	// Gradiente de los pesos de entrada a capa oculta
+	for i := 0; i < red.Ocultas; i++ {
+		sum := 0.0
+		for j := 0; j < red.Salidas; j++ {
+			sum += deltaSalida * red.PesosSalidas[j][i]
+		}
+		deltaOculta := sum * derivadaSigmoid(ocultas[i])
+		for j := 0; j < red.Entradas; j++ {
+			g[i][j] += deltaOculta * entrada[j]
+		}
+	}
+	return error * error / 2
+}
+
+// `Fit` inicializa los pesos y entrena la red con las etiquetas 0 o 1 de `train` durante
+// `Epocas` épocas (ver `Epoca`); para usar validación, callbacks o puntos de control se
+// entrena con `ML.Entrenar`.
+func (red *RedNeuronal) Fit(train *ML.Dataset) error {
+	if red.Epocas <= 0 {
+		red.Epocas = 10
+	}
+	_, err := ML.Entrenar(red, train, ML.Entrenamiento{Epocas: red.Epocas})
+	return err
+}
+
+// `Preparar` inicializa los pesos para `train`, salvo al retomar un punto de control.
+func (red *RedNeuronal) Preparar(train *ML.Dataset, reanudar bool) error {
+	if red.TamañoLote <= 0 {
+		red.TamañoLote = 32
+	}
+	if reanudar {
+		if red.Entradas != train.Features() {
+			return fmt.Errorf("dl: checkpoint has %d inputs, dataset has %d features", red.Entradas, train.Features())
+		}
+		return nil
+	}
+	red.Entradas = train.Features()
+	red.Salidas = 1
+	red.inicializarPesos()
+	return nil
+}
+
+// `Epoca` recorre el dataset en mini-lotes, en orden. Con varios `Workers`, cada goroutine
+// calcula el gradiente de su tramo de cada lote en un búfer propio y los búferes se reducen
+// en árbol (ver `ML.GradienteParalelo`); los pesos se actualizan una vez por lote, sin
+// carreras. El paso usa la suma de los gradientes del lote, por lo que la tasa equivale a
+// la del entrenamiento ejemplo por ejemplo del programa original.
+func (red *RedNeuronal) Epoca(train *ML.Dataset, epoca int) (float64, error) {
+	parametros := red.parametros()
+	paralelo := ML.NuevoGradienteParalelo(red.Workers, parametros, func(w int, indices []int, g [][]float64) float64 {
+		perdida := 0.0
+		for _, i := range indices {
+			perdida += red.retropropagacion(train.X[i], train.Y[i], g)
+		}
+		return perdida
+	})
+	defer paralelo.Cerrar()
+
+	orden := make([]int, train.Len())
+	for i := range orden {
+		orden[i] = i
+	}
+	total := 0.0
+	for _, lote := range ML.Lotes(orden, red.TamañoLote) {
+		g, perdida := paralelo.Calcular(lote)
+		total += perdida
+		ML.PasoGradiente(parametros, g, red.TasaAprendizaje)
+	}
+	return total / float64(train.Len()), nil
+}
+
+// `Loss` devuelve el error cuadrático medio ½(salida - y)² de la red sobre `d`.
+func (red *RedNeuronal) Loss(d *ML.Dataset) float64 {
+	if d.Len() == 0 {
+		return 0
+	}
+	total := 0.0
+	for i, x := range d.X {
+		salida, _ := red.propagacionHaciaAdelante(x)
+		total += (salida[0] - d.Y[i]) * (salida[0] - d.Y[i]) / 2
+	}
+	return total / float64(d.Len())
+}
+
+// `Predict` clasifica una instancia: 1 si la salida de la red supera 0.5, 0 si no.
+func (red *RedNeuronal) Predict(instancia []float64) float64 {
+	salida, _ := red.propagacionHaciaAdelante(instancia)
+	if salida[0] > 0.5 {
+		return 1
+	}
+	return 0
+}
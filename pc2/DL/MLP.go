@@ -0,0 +1,449 @@
+package DL
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"pc2/ML"
+	"pc2/Optimizadores"
+)
+
+// Funciones de activación.
+const RELU = "relu"
+const TANH = "tanh"
+const SIGMOID = "sigmoid"
+const SOFTMAX = "softmax" // Solo en la capa de salida, con entropía cruzada
+const LINEAL = "lineal"   // Identidad
+
+// Pérdidas.
+const ENTROPIA_CRUZADA = "entropia" // Clasificación: `Y` es el índice de la clase
+const MSE = "mse"                   // Regresión: ½‖salida - y‖², con `Y` como único objetivo
+
+// Inicializaciones de los pesos.
+const XAVIER = "xavier" // Glorot: uniforme en ±√(6 / (entradas + salidas)), para tanh y sigmoide
+const HE = "he"         // Normal con desviación √(2 / entradas), para ReLU
+
+// Estructura `Capa` densa: salida = activación(`Pesos`·entrada + `Sesgos`). `Pesos` guarda la
+// matriz `Salidas`×`Entradas` por filas en un solo slice, para que los optimizadores y la
+// reducción de gradientes la recorran como un vector.
+type Capa struct {
+	Entradas   int
+	Salidas    int
+	Activacion string
+	Pesos      []float64
+	Sesgos     []float64
+}
+
+// Estructura `MLP` con un perceptrón multicapa: la configuración y las capas aprendidas.
+type MLP struct {
+	Ocultas         []int   // Neuronas de cada capa oculta
+	Activacion      string  // Activación de las capas ocultas; RELU si está vacía
+	Salida          string  // Activación de salida; SOFTMAX con entropía cruzada y LINEAL con MSE si está vacía
+	Perdida         string  // ENTROPIA_CRUZADA (por defecto) o MSE
+	Inicializacion  string  // XAVIER o HE; si está vacía, HE para ReLU y XAVIER para el resto
+	Epocas          int     // 10 si es 0
+	TamañoLote      int     // Ejemplos por paso de gradiente; 32 si es 0
+	TasaAprendizaje float64 // Tasa del SGD por defecto; 0.01 si es 0
+	Workers         int     // Goroutines que calculan el gradiente de cada mini-lote; con 1 o menos, secuencial
+	Semilla         int64   // Semilla de la inicialización y del orden; si es 0 se usa la hora actual
+
+	// Optimizador que aplica el gradiente medio de cada mini-lote; si es nil, SGD con
+	// `TasaAprendizaje` constante.
+	Optimizador Optimizadores.Optimizador `json:"-"`
+
+	Capas             []*Capa
+	EstadoOptimizador *Optimizadores.Estado `json:",omitempty"` // Momentos del optimizador, para los puntos de control
+}
+
+// Completa la configuración por defecto y la valida.
+func (red *MLP) preparar() error {
+	if red.Activacion == "" {
+		red.Activacion = RELU
+	}
+	if red.Perdida == "" {
+		red.Perdida = ENTROPIA_CRUZADA
+	}
+	if red.Salida == "" {
+		red.Salida = SOFTMAX
+		if red.Perdida == MSE {
+			red.Salida = LINEAL
+		}
+	}
+	if red.Epocas <= 0 {
+		red.Epocas = 10
+	}
+	if red.TamañoLote <= 0 {
+		red.TamañoLote = 32
+	}
+	if red.TasaAprendizaje <= 0 {
+		red.TasaAprendizaje = 0.01
+	}
+	switch red.Activacion {
+	case RELU, TANH, SIGMOID, LINEAL:
+	default:
+		return fmt.Errorf("mlp: unknown hidden activation %q", red.Activacion)
+	}
+	switch {
+	case red.Perdida == ENTROPIA_CRUZADA && (red.Salida == SOFTMAX || red.Salida == SIGMOID):
+	case red.Perdida == MSE && red.Salida != SOFTMAX:
+	default:
+		return fmt.Errorf("mlp: unsupported loss %q with output activation %q", red.Perdida, red.Salida)
+	}
+	return nil
+}
+
+// Cantidad de salidas para los datos de entrenamiento: una por clase con softmax y una en
+// los demás casos.
+func (red *MLP) salidas(train *ML.Dataset) (int, error) {
+	if red.Perdida == MSE {
+		return 1, nil
+	}
+	clases := 0
+	for _, y := range train.Y {
+		if y < 0 || y != math.Trunc(y) {
+			return 0, fmt.Errorf("mlp: label %v is not a class index", y)
+		}
+		if int(y)+1 > clases {
+			clases = int(y) + 1
+		}
+	}
+	if red.Salida == SIGMOID {
+		if clases > 2 {
+			return 0, errors.New("mlp: a sigmoid output needs labels 0 and 1")
+		}
+		return 1, nil
+	}
+	if clases < 2 {
+		return 0, errors.New("mlp: training labels have a single class")
+	}
+	return clases, nil
+}
+
+// Crea las capas con la inicialización configurada.
+func (red *MLP) inicializar(entradas, salidas int) {
+	rng := ML.NewRand(red.Semilla)
+	tamaños := append(append([]int{entradas}, red.Ocultas...), salidas)
+	red.Capas = make([]*Capa, len(tamaños)-1)
+	for l := range red.Capas {
+		capa := &Capa{Entradas: tamaños[l], Salidas: tamaños[l+1], Activacion: red.Activacion}
+		if l == len(red.Capas)-1 {
+			capa.Activacion = red.Salida
+		}
+		capa.Pesos = make([]float64, capa.Salidas*capa.Entradas)
+		capa.Sesgos = make([]float64, capa.Salidas)
+
+		inicializacion := red.Inicializacion
+		if inicializacion == "" {
+			inicializacion = XAVIER
+			if capa.Activacion == RELU {
+				inicializacion = HE
+			}
+		}
+		for k := range capa.Pesos {
+			if inicializacion == HE {
+				capa.Pesos[k] = rng.NormFloat64() * math.Sqrt(2/float64(capa.Entradas))
+			} else {
+				limite := math.Sqrt(6 / float64(capa.Entradas+capa.Salidas))
+				capa.Pesos[k] = (rng.Float64()*2 - 1) * limite
+			}
+		}
+		red.Capas[l] = capa
+	}
+}
+
+// Aplica la activación de la capa a `z` y guarda el resultado en `a`.
+func (capa *Capa) activar(z, a []float64) {
+	switch capa.Activacion {
+	case RELU:
+		for k, v := range z {
+			a[k] = math.Max(v, 0)
+		}
+	case TANH:
+		for k, v := range z {
+			a[k] = math.Tanh(v)
+		}
+	case SIGMOID:
+		for k, v := range z {
+			a[k] = 1 / (1 + math.Exp(-v))
+		}
+	case SOFTMAX:
+		maximo := math.Inf(-1)
+		for _, v := range z {
+			maximo = math.Max(maximo, v)
+		}
+		total := 0.0
+		for k, v := range z {
+			a[k] = math.Exp(v - maximo)
+			total += a[k]
+		}
+		for k := range a {
+			a[k] /= total
+		}
+	default:
+		copy(a, z)
+	}
+}
+
+// Derivada de la activación respecto de `z`, a partir de `z` y de la activación `a`.
+func (capa *Capa) derivada(z, a float64) float64 {
+	switch capa.Activacion {
+	case RELU:
+		if z > 0 {
+			return 1
+		}
+		return 0
+	case TANH:
+		return 1 - a*a
+	case SIGMOID:
+		return a * (1 - a)
+	}
+	return 1
+}
+
+// Valores intermedios de una propagación: `a[0]` es la entrada y `a[l+1]`, `z[l+1]` la
+// salida de la capa l después y antes de la activación.
+type propagacion struct {
+	z [][]float64
+	a [][]float64
+}
+
+// Reserva los vectores de una propagación para la arquitectura de la red.
+func (red *MLP) nuevaPropagacion() *propagacion {
+	p := &propagacion{z: make([][]float64, len(red.Capas)+1), a: make([][]float64, len(red.Capas)+1)}
+	for l, capa := range red.Capas {
+		p.z[l+1] = make([]float64, capa.Salidas)
+		p.a[l+1] = make([]float64, capa.Salidas)
+	}
+	return p
+}
+
+// Propagación hacia adelante de `x`; devuelve la activación de la capa de salida.
+func (red *MLP) propagar(x []float64, p *propagacion) []float64 {
+	p.a[0] = x
+	for l, capa := range red.Capas {
+		entrada, z := p.a[l], p.z[l+1]
+		for o := 0; o < capa.Salidas; o++ {
+			suma := capa.Sesgos[o]
+			fila := capa.Pesos[o*capa.Entradas : (o+1)*capa.Entradas]
+			for k, v := range entrada {
+				suma += fila[k] * v
+			}
+			z[o] = suma
+		}
+		capa.activar(z, p.a[l+1])
+	}
+	return p.a[len(red.Capas)]
+}
+
+// Pérdida de una salida para la etiqueta `y`.
+func (red *MLP) perdida(salida []float64, y float64) float64 {
+	switch {
+	case red.Perdida == MSE:
+		d := salida[0] - y
+		return d * d / 2
+	case red.Salida == SIGMOID:
+		p := math.Min(math.Max(salida[0], 1e-15), 1-1e-15)
+		return -(y*math.Log(p) + (1-y)*math.Log(1-p))
+	}
+	return -math.Log(math.Max(salida[int(y)], 1e-15))
+}
+
+// `Parametros` devuelve los parámetros de la red como vectores: los pesos y los sesgos de
+// cada capa, en orden. Los vectores comparten memoria con las capas.
+func (red *MLP) Parametros() [][]float64 {
+	parametros := make([][]float64, 0, 2*len(red.Capas))
+	for _, capa := range red.Capas {
+		parametros = append(parametros, capa.Pesos, capa.Sesgos)
+	}
+	return parametros
+}
+
+// Retropropagación: suma a `g` el gradiente de la pérdida del ejemplo (`x`, `y`) respecto
+// de cada parámetro y devuelve esa pérdida. Con softmax o sigmoide y entropía cruzada el
+// error de la salida es simplemente salida - objetivo.
+func (red *MLP) retropropagar(x []float64, y float64, p *propagacion, g [][]float64) float64 {
+	salida := red.propagar(x, p)
+	ultima := len(red.Capas) - 1
+	delta := make([]float64, len(salida))
+	switch {
+	case red.Perdida == ENTROPIA_CRUZADA && red.Salida == SOFTMAX:
+		copy(delta, salida)
+		delta[int(y)] -= 1
+	case red.Perdida == ENTROPIA_CRUZADA:
+		delta[0] = salida[0] - y
+	default:
+		delta[0] = (salida[0] - y) * red.Capas[ultima].derivada(p.z[ultima+1][0], salida[0])
+	}
+
+	for l := ultima; l >= 0; l-- {
+		capa := red.Capas[l]
+		entrada := p.a[l]
+		for o, d := range delta {
+			if d == 0 {
+				continue
+			}
+			g[2*l+1][o] += d
+			fila := g[2*l][o*capa.Entradas : (o+1)*capa.Entradas]
+			for k, v := range entrada {
+				fila[k] += d * v
+			}
+		}
+		if l == 0 {
+			break
+		}
+		// δ de la capa anterior: (Wᵀ δ) ⊙ f'(z).
+		anterior := red.Capas[l-1]
+		siguiente := make([]float64, capa.Entradas)
+		for o, d := range delta {
+			if d == 0 {
+				continue
+			}
+			fila := capa.Pesos[o*capa.Entradas : (o+1)*capa.Entradas]
+			for k, w := range fila {
+				siguiente[k] += w * d
+			}
+		}
+		for k := range siguiente {
+			siguiente[k] *= anterior.derivada(p.z[l][k], p.a[l][k])
+		}
+		delta = siguiente
+	}
+	return red.perdida(salida, y)
+}
+
+// `Gradiente` suma en `g` (con la forma de `Parametros`) los gradientes de la pérdida de los
+// ejemplos `indices` de `d` y devuelve la suma de sus pérdidas (sin promediar).
+func (red *MLP) Gradiente(d *ML.Dataset, indices []int, g [][]float64) float64 {
+	return red.gradiente(d, indices, g, red.nuevaPropagacion())
+}
+
+// Igual que `Gradiente`, con los vectores de la propagación `p`.
+func (red *MLP) gradiente(d *ML.Dataset, indices []int, g [][]float64, p *propagacion) float64 {
+	total := 0.0
+	for _, i := range indices {
+		total += red.retropropagar(d.X[i], d.Y[i], p, g)
+	}
+	return total
+}
+
+// `Fit` inicializa la red y la entrena `Epocas` épocas con descenso de gradiente por
+// mini-lotes (ver `Epoca`); para usar validación, callbacks o puntos de control se entrena
+// con `ML.Entrenar`.
+func (red *MLP) Fit(train *ML.Dataset) error {
+	if err := red.preparar(); err != nil {
+		return err
+	}
+	_, err := ML.Entrenar(red, train, ML.Entrenamiento{Epocas: red.Epocas})
+	return err
+}
+
+// `Preparar` valida la configuración y, salvo al retomar un punto de control, crea las
+// capas para `train` y el estado del optimizador.
+func (red *MLP) Preparar(train *ML.Dataset, reanudar bool) error {
+	if err := red.preparar(); err != nil {
+		return err
+	}
+	if !reanudar {
+		salidas, err := red.salidas(train)
+		if err != nil {
+			return err
+		}
+		red.Semilla = ML.FijarSemilla(red.Semilla)
+		red.inicializar(train.Features(), salidas)
+		red.EstadoOptimizador = nil
+	} else if len(red.Capas) == 0 || red.Capas[0].Entradas != train.Features() {
+		return fmt.Errorf("mlp: checkpoint does not match %d features", train.Features())
+	}
+
+	optimizador := red.Optimizador
+	if optimizador == nil {
+		optimizador = &Optimizadores.SGD{Tasa: Optimizadores.Constante(red.TasaAprendizaje)}
+	}
+	if red.EstadoOptimizador == nil {
+		red.EstadoOptimizador = Optimizadores.NuevoEstado(optimizador, red.Parametros())
+	} else {
+		red.EstadoOptimizador.Asociar(optimizador, red.Parametros())
+	}
+	return nil
+}
+
+// `Epoca` recorre `train` en mini-lotes desordenados y aplica el optimizador con el
+// gradiente medio de cada uno. Con varios `Workers` el gradiente de cada mini-lote se
+// calcula en paralelo (ver `ML.GradienteParalelo`) y se aplica una sola vez, así que el
+// entrenamiento es el mismo que el secuencial salvo por el orden de las sumas.
+func (red *MLP) Epoca(train *ML.Dataset, epoca int) (float64, error) {
+	workers := red.Workers
+	if workers > red.TamañoLote {
+		workers = red.TamañoLote
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	propagaciones := make([]*propagacion, workers)
+	for w := range propagaciones {
+		propagaciones[w] = red.nuevaPropagacion()
+	}
+	paralelo := ML.NuevoGradienteParalelo(workers, red.Parametros(), func(w int, indices []int, g [][]float64) float64 {
+		return red.gradiente(train, indices, g, propagaciones[w])
+	})
+	defer paralelo.Cerrar()
+
+	total := 0.0
+	for _, lote := range ML.Lotes(ML.RandEpoca(red.Semilla, epoca).Perm(train.Len()), red.TamañoLote) {
+		g, perdida := paralelo.Calcular(lote)
+		total += perdida
+		escalar(g, 1/float64(len(lote)))
+		red.EstadoOptimizador.Paso(g)
+	}
+	return total / float64(train.Len()), nil
+}
+
+// Multiplica los gradientes `g` por `factor`.
+func escalar(g [][]float64, factor float64) {
+	for _, v := range g {
+		for j := range v {
+			v[j] *= factor
+		}
+	}
+}
+
+// `Loss` devuelve la pérdida media de la red sobre `d`.
+func (red *MLP) Loss(d *ML.Dataset) float64 {
+	if d.Len() == 0 {
+		return 0
+	}
+	p := red.nuevaPropagacion()
+	total := 0.0
+	for i, x := range d.X {
+		total += red.perdida(red.propagar(x, p), d.Y[i])
+	}
+	return total / float64(d.Len())
+}
+
+// `Output` devuelve la activación de la capa de salida para `x`: las probabilidades de cada
+// clase con softmax, la probabilidad de la clase 1 con sigmoide o el valor predicho.
+func (red *MLP) Output(x []float64) []float64 {
+	return append([]float64(nil), red.propagar(x, red.nuevaPropagacion())...)
+}
+
+// `Predict` devuelve la clase más probable o, con MSE, el valor predicho.
+func (red *MLP) Predict(x []float64) float64 {
+	salida := red.Output(x)
+	switch {
+	case red.Perdida == MSE:
+		return salida[0]
+	case red.Salida == SIGMOID:
+		if salida[0] >= 0.5 {
+			return 1
+		}
+		return 0
+	}
+	mejor := 0
+	for k, p := range salida {
+		if p > salida[mejor] {
+			mejor = k
+		}
+	}
+	return float64(mejor)
+}
@@ -0,0 +1,198 @@
+package DL
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"pc2/ML"
+	"pc2/Optimizadores"
+)
+
+// Compara el gradiente de la retropropagación con diferencias centradas de la pérdida,
+// para cada combinación de activación oculta, activación de salida y pérdida.
+func TestGradienteNumerico(t *testing.T) {
+	casos := []struct {
+		activacion, salida, perdida string
+	}{
+		{RELU, SOFTMAX, ENTROPIA_CRUZADA},
+		{TANH, SOFTMAX, ENTROPIA_CRUZADA},
+		{SIGMOID, SOFTMAX, ENTROPIA_CRUZADA},
+		{TANH, SIGMOID, ENTROPIA_CRUZADA},
+		{TANH, LINEAL, MSE},
+		{RELU, TANH, MSE},
+		{SIGMOID, SIGMOID, MSE},
+	}
+	for _, caso := range casos {
+		datos := ML.Blobs(20, 4, 3, 1, 1)
+		if caso.salida == SIGMOID || caso.perdida == MSE {
+			for i := range datos.Y {
+				datos.Y[i] = math.Mod(datos.Y[i], 2)
+			}
+		}
+		red := &MLP{Ocultas: []int{5, 4}, Activacion: caso.activacion, Salida: caso.salida, Perdida: caso.perdida, Semilla: 3}
+		if err := red.preparar(); err != nil {
+			t.Fatal(err)
+		}
+		salidas, err := red.salidas(datos)
+		if err != nil {
+			t.Fatal(err)
+		}
+		red.inicializar(datos.Features(), salidas)
+
+		todos := make([]int, datos.Len())
+		for i := range todos {
+			todos[i] = i
+		}
+		var g [][]float64
+		for _, v := range red.Parametros() {
+			g = append(g, make([]float64, len(v)))
+		}
+		red.Gradiente(datos, todos, g)
+		perdida := func() float64 { return red.Loss(datos) * float64(datos.Len()) }
+
+		const h = 1e-6
+		peor := 0.0
+		for p, valores := range red.Parametros() {
+			for k := range valores {
+				original := valores[k]
+				valores[k] = original + h
+				mas := perdida()
+				valores[k] = original - h
+				menos := perdida()
+				valores[k] = original
+				numerico := (mas - menos) / (2 * h)
+				relativo := math.Abs(numerico-g[p][k]) / math.Max(1, math.Abs(numerico)+math.Abs(g[p][k]))
+				peor = math.Max(peor, relativo)
+			}
+		}
+		if peor > 1e-6 {
+			t.Errorf("%s/%s/%s: relative gradient error %.2e", caso.activacion, caso.salida, caso.perdida, peor)
+		}
+	}
+}
+
+// Una red con capas ocultas separa los círculos concéntricos y las nubes de varias clases.
+func TestMLPClasificacion(t *testing.T) {
+	casos := []struct {
+		nombre string
+		datos  *ML.Dataset
+		red    *MLP
+		minimo float64
+	}{
+		{"circles", ML.Circles(2000, 0, 1), &MLP{Ocultas: []int{16, 16}, Epocas: 100, TasaAprendizaje: 0.1, Semilla: 1}, 0.95},
+		{"circles tanh", ML.Circles(2000, 0, 1), &MLP{Ocultas: []int{16}, Activacion: TANH, Salida: SIGMOID, Epocas: 100, TasaAprendizaje: 0.1, Semilla: 1}, 0.95},
+		{"circles adam", ML.Circles(2000, 0, 1), &MLP{Ocultas: []int{16, 16}, Epocas: 30, Optimizador: &Optimizadores.Adam{Tasa: Optimizadores.Constante(0.01)}, Semilla: 1}, 0.95},
+		{"blobs", ML.Blobs(2000, 5, 4, 0.2, 2), &MLP{Ocultas: []int{8}, Epocas: 20, TasaAprendizaje: 0.05, Semilla: 2}, 0.95},
+	}
+	for _, caso := range casos {
+		train, test := caso.datos.Split(0.8, 1)
+		if err := caso.red.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		precision := ML.Accuracy(caso.red, test)
+		t.Logf("%s: accuracy %.4f", caso.nombre, precision)
+		if precision < caso.minimo {
+			t.Errorf("%s: accuracy %.4f, expected at least %.2f", caso.nombre, precision, caso.minimo)
+		}
+	}
+}
+
+// Con paralelismo de datos el entrenamiento es reproducible y coincide con el secuencial
+// salvo por el redondeo del orden de las sumas.
+func TestMLPWorkers(t *testing.T) {
+	train := ML.Blobs(600, 4, 3, 0.3, 3)
+	entrenar := func(workers int) *MLP {
+		red := &MLP{Ocultas: []int{8, 8}, Epocas: 3, TamañoLote: 50, Workers: workers, Semilla: 3}
+		if err := red.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		return red
+	}
+	secuencial := entrenar(1)
+	paralela := entrenar(4)
+	otra := entrenar(4)
+	for p, valores := range paralela.Parametros() {
+		for k, v := range valores {
+			if v != otra.Parametros()[p][k] {
+				t.Fatalf("parameter %d/%d differs between runs with 4 workers", p, k)
+			}
+			if math.Abs(v-secuencial.Parametros()[p][k]) > 1e-9 {
+				t.Fatalf("parameter %d/%d: %v with 4 workers, %v sequentially", p, k, v, secuencial.Parametros()[p][k])
+			}
+		}
+	}
+
+	legado := func(workers int) *RedNeuronal {
+		red := &RedNeuronal{Ocultas: 5, Epocas: 3, TasaAprendizaje: 0.01, Workers: workers, Semilla: 3}
+		if err := red.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		return red
+	}
+	a, b := legado(1), legado(4)
+	for i, fila := range a.PesosOcultos {
+		for j, v := range fila {
+			if math.Abs(v-b.PesosOcultos[i][j]) > 1e-9 {
+				t.Fatalf("hidden weight %d/%d: %v sequentially, %v with 4 workers", i, j, v, b.PesosOcultos[i][j])
+			}
+		}
+	}
+}
+
+// go test ./DL -bench MLP -cpu 1,2,4: el tiempo por época baja con los núcleos disponibles.
+func BenchmarkMLPWorkers(b *testing.B) {
+	train := ML.Blobs(20000, 20, 4, 0.5, 1)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				red := &MLP{Ocultas: []int{64, 32}, Epocas: 1, TamañoLote: 256, Workers: workers, Semilla: 1}
+				if err := red.Fit(train); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Retomar desde un punto de control (con los momentos de Adam) da la misma red que entrenar
+// sin cortes, y la parada temprana deja la red de la mejor época.
+func TestMLPPuntoDeControl(t *testing.T) {
+	train, validacion := ML.Blobs(600, 4, 3, 0.5, 4).Split(0.8, 4)
+	nueva := func() *MLP {
+		return &MLP{Ocultas: []int{8}, TamañoLote: 32, Workers: 2, Semilla: 4, Optimizador: &Optimizadores.Adam{}}
+	}
+	completa := nueva()
+	if _, err := ML.Entrenar(completa, train, ML.Entrenamiento{Epocas: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	archivo := filepath.Join(t.TempDir(), "mlp.json")
+	punto := &ML.PuntoDeControl{Archivo: archivo}
+	if _, err := ML.Entrenar(nueva(), train, ML.Entrenamiento{Epocas: 2, Callbacks: []ML.Callback{punto}}); err != nil {
+		t.Fatal(err)
+	}
+	retomada := nueva()
+	if _, err := ML.Entrenar(retomada, train, ML.Entrenamiento{Epocas: 4, Reanudar: archivo}); err != nil {
+		t.Fatal(err)
+	}
+	for p, valores := range completa.Parametros() {
+		for k, v := range valores {
+			if v != retomada.Parametros()[p][k] {
+				t.Fatalf("parameter %d/%d: %v resumed, %v uninterrupted", p, k, retomada.Parametros()[p][k], v)
+			}
+		}
+	}
+
+	red := nueva()
+	parada := &ML.ParadaTemprana{Paciencia: 3, Restaurar: true}
+	historial, err := ML.Entrenar(red, train, ML.Entrenamiento{Epocas: 200, Validacion: validacion, Metrica: ML.Accuracy, Callbacks: []ML.Callback{parada}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("stopped after %d epochs, best %d (loss %.4f)", len(historial), parada.MejorEpoca, parada.MejorPerdida)
+	if len(historial) == 200 || red.Loss(validacion) != parada.MejorPerdida {
+		t.Fatalf("early stopping did not stop or restore: %d epochs, loss %v, best %v", len(historial), red.Loss(validacion), parada.MejorPerdida)
+	}
+}
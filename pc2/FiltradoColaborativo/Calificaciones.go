@@ -0,0 +1,331 @@
+package FiltradoColaborativo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"pc2/ML"
+)
+
+// Estructura `Calificacion`: el `Valor` que el usuario `Usuario` dio al producto `Producto`.
+type Calificacion struct {
+	Usuario  int
+	Producto int
+	Valor    float64
+}
+
+// Estructura `Calificaciones`: matriz dispersa de calificaciones guardada por usuario (los
+// productos que calificó cada uno) y por producto (los usuarios que lo calificaron), con los
+// índices ordenados. Usuarios y productos se identifican con enteros de 0 a `NumUsuarios`-1
+// y de 0 a `NumProductos`-1. La comparten todos los recomendadores del paquete.
+type Calificaciones struct {
+	NumUsuarios  int
+	NumProductos int
+
+	porUsuario     []ML.Disperso
+	porProducto    []ML.Disperso
+	mediasUsuario  []float64
+	mediasProducto []float64
+	media          float64
+	cantidad       int
+	implicitas     bool
+}
+
+// `NuevasCalificaciones` crea la matriz con las calificaciones de `lista`; las dimensiones
+// son el mayor usuario y el mayor producto más uno. Si un par usuario-producto se repite,
+// queda la última calificación.
+func NuevasCalificaciones(lista []Calificacion) (*Calificaciones, error) {
+	numUsuarios, numProductos := 0, 0
+	for _, c := range lista {
+		if c.Usuario < 0 || c.Producto < 0 {
+			return nil, fmt.Errorf("cf: negative id in rating %+v", c)
+		}
+		if math.IsNaN(c.Valor) || math.IsInf(c.Valor, 0) {
+			return nil, fmt.Errorf("cf: invalid value in rating %+v", c)
+		}
+		numUsuarios = max(numUsuarios, c.Usuario+1)
+		numProductos = max(numProductos, c.Producto+1)
+	}
+	return construir(lista, numUsuarios, numProductos), nil
+}
+
+// `DesdeUsuarios` convierte los usuarios de `DatasetAleatorio` (o del formato original) a
+// `Calificaciones`, usando el `ID` de cada usuario y los IDs de sus productos.
+func DesdeUsuarios(usuarios []Usuario) (*Calificaciones, error) {
+	var lista []Calificacion
+	for _, usuario := range usuarios {
+		productos := make([]int, 0, len(usuario.Calificaciones))
+		for producto := range usuario.Calificaciones {
+			productos = append(productos, producto)
+		}
+		sort.Ints(productos)
+		for _, producto := range productos {
+			lista = append(lista, Calificacion{Usuario: usuario.ID, Producto: producto, Valor: usuario.Calificaciones[producto]})
+		}
+	}
+	return NuevasCalificaciones(lista)
+}
+
+// Crea la matriz de `numUsuarios` × `numProductos` con `lista`, ya validada.
+func construir(lista []Calificacion, numUsuarios, numProductos int) *Calificaciones {
+	ordenadas := append([]Calificacion(nil), lista...)
+	sort.SliceStable(ordenadas, func(a, b int) bool {
+		if ordenadas[a].Usuario != ordenadas[b].Usuario {
+			return ordenadas[a].Usuario < ordenadas[b].Usuario
+		}
+		return ordenadas[a].Producto < ordenadas[b].Producto
+	})
+	// Entre repetidas, el orden estable deja la última al final del grupo.
+	unicas := ordenadas[:0]
+	for _, c := range ordenadas {
+		if n := len(unicas); n > 0 && unicas[n-1].Usuario == c.Usuario && unicas[n-1].Producto == c.Producto {
+			unicas[n-1] = c
+			continue
+		}
+		unicas = append(unicas, c)
+	}
+
+	c := &Calificaciones{
+		NumUsuarios:    numUsuarios,
+		NumProductos:   numProductos,
+		porUsuario:     make([]ML.Disperso, numUsuarios),
+		porProducto:    make([]ML.Disperso, numProductos),
+		mediasUsuario:  make([]float64, numUsuarios),
+		mediasProducto: make([]float64, numProductos),
+		cantidad:       len(unicas),
+	}
+	for _, calificacion := range unicas {
+		u, p := calificacion.Usuario, calificacion.Producto
+		c.porUsuario[u].Indices = append(c.porUsuario[u].Indices, p)
+		c.porUsuario[u].Valores = append(c.porUsuario[u].Valores, calificacion.Valor)
+		c.porProducto[p].Indices = append(c.porProducto[p].Indices, u)
+		c.porProducto[p].Valores = append(c.porProducto[p].Valores, calificacion.Valor)
+		c.media += calificacion.Valor
+	}
+	if c.cantidad > 0 {
+		c.media /= float64(c.cantidad)
+	}
+	medias(c.porUsuario, c.mediasUsuario, c.media)
+	medias(c.porProducto, c.mediasProducto, c.media)
+	return c
+}
+
+// Guarda en `destino` la media de cada fila, o `defecto` si la fila está vacía.
+func medias(filas []ML.Disperso, destino []float64, defecto float64) {
+	for i, fila := range filas {
+		destino[i] = defecto
+		if fila.Len() == 0 {
+			continue
+		}
+		suma := 0.0
+		for _, v := range fila.Valores {
+			suma += v
+		}
+		destino[i] = suma / float64(fila.Len())
+	}
+}
+
+// `Len` devuelve la cantidad de calificaciones.
+func (c *Calificaciones) Len() int {
+	return c.cantidad
+}
+
+// `Usuario` devuelve los productos que calificó el usuario `u` y sus calificaciones.
+func (c *Calificaciones) Usuario(u int) ML.Disperso {
+	if u < 0 || u >= c.NumUsuarios {
+		return ML.Disperso{}
+	}
+	return c.porUsuario[u]
+}
+
+// `Producto` devuelve los usuarios que calificaron el producto `p` y sus calificaciones.
+func (c *Calificaciones) Producto(p int) ML.Disperso {
+	if p < 0 || p >= c.NumProductos {
+		return ML.Disperso{}
+	}
+	return c.porProducto[p]
+}
+
+// `Valor` devuelve la calificación del usuario `u` al producto `p`, si existe.
+func (c *Calificaciones) Valor(u, p int) (float64, bool) {
+	fila := c.Usuario(u)
+	n := sort.SearchInts(fila.Indices, p)
+	if n < fila.Len() && fila.Indices[n] == p {
+		return fila.Valores[n], true
+	}
+	return 0, false
+}
+
+// `Media` devuelve la media de todas las calificaciones.
+func (c *Calificaciones) Media() float64 {
+	return c.media
+}
+
+// `MediaUsuario` devuelve la media de las calificaciones del usuario `u`, o la media global
+// si no calificó nada.
+func (c *Calificaciones) MediaUsuario(u int) float64 {
+	if u < 0 || u >= c.NumUsuarios {
+		return c.media
+	}
+	return c.mediasUsuario[u]
+}
+
+// `MediaProducto` devuelve la media de las calificaciones del producto `p`, o la media
+// global si nadie lo calificó.
+func (c *Calificaciones) MediaProducto(p int) float64 {
+	if p < 0 || p >= c.NumProductos {
+		return c.media
+	}
+	return c.mediasProducto[p]
+}
+
+// `Implicitas` indica si los valores son confianzas de interacciones implícitas (clics,
+// vistas) en lugar de calificaciones; ver `CalificacionesImplicitas`.
+func (c *Calificaciones) Implicitas() bool {
+	return c.implicitas
+}
+
+// Valor que se resta a las calificaciones del usuario `u` al predecir: su media, o 0 con
+// datos implícitos, donde no interactuar equivale a confianza 0.
+func (c *Calificaciones) centroUsuario(u int) float64 {
+	if c.implicitas {
+		return 0
+	}
+	return c.MediaUsuario(u)
+}
+
+// Como `centroUsuario`, para el producto `p`.
+func (c *Calificaciones) centroProducto(p int) float64 {
+	if c.implicitas {
+		return 0
+	}
+	return c.MediaProducto(p)
+}
+
+// Combina las desviaciones de los vecinos: `suma` es la suma de similitud · desviación de los
+// vecinos con valor, `pesos` la de sus similitudes y `total` la de todos los vecinos. Con
+// calificaciones es `centro` más el promedio ponderado (o `centro` si no hay vecinos con
+// valor); con datos implícitos los vecinos sin interacción cuentan como 0.
+func (c *Calificaciones) estimar(centro, suma, pesos, total float64) float64 {
+	if c.implicitas {
+		if total == 0 {
+			return 0
+		}
+		return suma / total
+	}
+	if pesos == 0 {
+		return centro
+	}
+	return centro + suma/pesos
+}
+
+// `Lista` devuelve todas las calificaciones, ordenadas por usuario y producto.
+func (c *Calificaciones) Lista() []Calificacion {
+	lista := make([]Calificacion, 0, c.cantidad)
+	for u, fila := range c.porUsuario {
+		for n, p := range fila.Indices {
+			lista = append(lista, Calificacion{Usuario: u, Producto: p, Valor: fila.Valores[n]})
+		}
+	}
+	return lista
+}
+
+// `Split` mezcla las calificaciones con la semilla `seed` y devuelve la fracción `ratio`
+// para entrenamiento y el resto para prueba, ambas con las dimensiones de `c`.
+func (c *Calificaciones) Split(ratio float64, seed int64) (*Calificaciones, *Calificaciones) {
+	lista := c.Lista()
+	orden := ML.NewRand(seed).Perm(len(lista))
+	numEntrenamiento := int(float64(len(lista)) * ratio)
+	train := make([]Calificacion, numEntrenamiento)
+	test := make([]Calificacion, len(lista)-numEntrenamiento)
+	for k, i := range orden {
+		if k < numEntrenamiento {
+			train[k] = lista[i]
+		} else {
+			test[k-numEntrenamiento] = lista[i]
+		}
+	}
+	entrenamiento, prueba := construir(train, c.NumUsuarios, c.NumProductos), construir(test, c.NumUsuarios, c.NumProductos)
+	entrenamiento.implicitas, prueba.implicitas = c.implicitas, c.implicitas
+	return entrenamiento, prueba
+}
+
+// `CalificacionesAleatorias` genera calificaciones entre 1 y 5 con estructura: cada usuario
+// y cada producto tienen un sesgo y un vector de 3 gustos latentes, y la calificación es
+// 3 + sesgos + producto de los gustos + ruido. Cada par usuario-producto está calificado con
+// probabilidad `densidad`.
+func CalificacionesAleatorias(numUsuarios, numProductos int, densidad float64, semilla int64) *Calificaciones {
+	const factores = 3
+	rng := ML.NewRand(semilla)
+	latentes := func(n int) ([]float64, ML.Matrix) {
+		sesgos := make([]float64, n)
+		gustos := ML.NewMatrix(n, factores)
+		for i := range gustos {
+			sesgos[i] = rng.NormFloat64() * 0.5
+			for f := range gustos[i] {
+				gustos[i][f] = rng.NormFloat64() * 0.7
+			}
+		}
+		return sesgos, gustos
+	}
+	sesgosUsuario, usuarios := latentes(numUsuarios)
+	sesgosProducto, productos := latentes(numProductos)
+
+	var lista []Calificacion
+	for u := 0; u < numUsuarios; u++ {
+		for p := 0; p < numProductos; p++ {
+			if rng.Float64() >= densidad {
+				continue
+			}
+			valor := 3 + sesgosUsuario[u] + sesgosProducto[p] + rng.NormFloat64()*0.3
+			for f := 0; f < factores; f++ {
+				valor += usuarios[u][f] * productos[p][f]
+			}
+			lista = append(lista, Calificacion{Usuario: u, Producto: p, Valor: math.Max(1, math.Min(5, valor))})
+		}
+	}
+	return construir(lista, numUsuarios, numProductos)
+}
+
+// Interfaz `Recomendador`: se entrena con `Calificaciones`, predice calificaciones y
+// recomienda productos no calificados.
+type Recomendador interface {
+	Fit(datos *Calificaciones) error
+	PredictRating(usuario, producto int) float64
+	RecommendTopN(usuario, n int) []Recomendacion
+}
+
+// `RMSE` calcula la raíz del error cuadrático medio de `r` sobre las calificaciones de `prueba`.
+func RMSE(r Recomendador, prueba *Calificaciones) float64 {
+	if prueba.Len() == 0 {
+		return 0
+	}
+	suma := 0.0
+	for _, c := range prueba.Lista() {
+		diferencia := r.PredictRating(c.Usuario, c.Producto) - c.Valor
+		suma += diferencia * diferencia
+	}
+	return math.Sqrt(suma / float64(prueba.Len()))
+}
+
+// `Recall` devuelve la fracción de las calificaciones (o interacciones) de `prueba` cuyo
+// producto está entre los `n` que `r` recomienda a su usuario.
+func Recall(r Recomendador, prueba *Calificaciones, n int) float64 {
+	if prueba.Len() == 0 {
+		return 0
+	}
+	aciertos := 0
+	for u := 0; u < prueba.NumUsuarios; u++ {
+		fila := prueba.Usuario(u)
+		if fila.Len() == 0 {
+			continue
+		}
+		for _, recomendacion := range r.RecommendTopN(u, n) {
+			if m := sort.SearchInts(fila.Indices, recomendacion.Producto); m < fila.Len() && fila.Indices[m] == recomendacion.Producto {
+				aciertos++
+			}
+		}
+	}
+	return float64(aciertos) / float64(prueba.Len())
+}
@@ -0,0 +1,357 @@
+package FiltradoColaborativo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"pc2/ML"
+)
+
+// Métodos de entrenamiento de `Factorizacion` (`Solver`).
+const (
+	ALS = "als"
+	SGD = "sgd"
+)
+
+// Cantidad de mutex de usuarios (y de productos) del SGD en paralelo: el usuario u usa el
+// mutex u % FRANJAS.
+const FRANJAS = 64
+
+// Estructura `Factorizacion`: factorización de la matriz de calificaciones con sesgos,
+// r̂ = μ + b_u + b_p + ⟨x_u, y_p⟩. Minimiza el error cuadrático más λ·n·(b² + ‖x‖²) para cada
+// usuario y producto, con n su cantidad de calificaciones (ALS-WR, Zhou et al. 2008). ALS
+// resuelve en cada época todos los usuarios en paralelo con los productos fijos y luego todos
+// los productos; SGD reparte las calificaciones entre los workers y protege las filas con
+// mutex por franjas.
+type Factorizacion struct {
+	Factores          int     // k; 10 si es 0
+	Regularizacion    float64 // λ; 0.05 si es 0
+	Solver            string  // ALS o SGD; ALS si está vacío
+	TasaAprendizaje   float64 // Tasa de SGD; 0.01 si es 0
+	Epocas            int     // 10 si es 0
+	DesviacionInicial float64 // Desviación de la inicialización normal de los factores; 0.1 si es 0
+	Workers           int
+	Semilla           int64 // Semilla de la inicialización y del orden; si es 0 se usa la hora actual
+
+	Media          float64
+	SesgosUsuario  []float64
+	SesgosProducto []float64
+	Usuarios       ML.Matrix // x_u, uno por fila
+	Productos      ML.Matrix // y_p, uno por fila
+	Historial      []float64 // RMSE de entrenamiento al final de cada época
+
+	datos   *Calificaciones
+	nuevos  []ML.Disperso // Calificaciones de los usuarios agregados con `AgregarUsuario`
+	bloqueo sync.RWMutex  // Protege `nuevos` y las filas que agrega `AgregarUsuario`
+}
+
+// Completa la configuración con los valores por defecto y la valida.
+func (f *Factorizacion) configurar() error {
+	if f.Factores <= 0 {
+		f.Factores = 10
+	}
+	if f.Regularizacion <= 0 {
+		f.Regularizacion = 0.05
+	}
+	if f.Solver == "" {
+		f.Solver = ALS
+	}
+	if f.TasaAprendizaje <= 0 {
+		f.TasaAprendizaje = 0.01
+	}
+	if f.Epocas <= 0 {
+		f.Epocas = 10
+	}
+	if f.DesviacionInicial <= 0 {
+		f.DesviacionInicial = 0.1
+	}
+	if f.Workers < 1 {
+		f.Workers = 1
+	}
+	switch f.Solver {
+	case ALS, SGD:
+	default:
+		return fmt.Errorf("cf: unknown solver %q", f.Solver)
+	}
+	return nil
+}
+
+// `Fit` entrena los sesgos y factores con `datos`. Con varios workers, ALS da el mismo
+// resultado que con uno; SGD no, porque el orden de las actualizaciones depende de la
+// planificación de las goroutines.
+func (f *Factorizacion) Fit(datos *Calificaciones) error {
+	if datos == nil || datos.Len() == 0 {
+		return errors.New("cf: empty ratings")
+	}
+	if datos.Implicitas() {
+		return errors.New("cf: matrix factorization needs explicit ratings")
+	}
+	if err := f.configurar(); err != nil {
+		return err
+	}
+	f.Semilla = ML.FijarSemilla(f.Semilla)
+	f.datos, f.nuevos = datos, nil
+
+	rng := ML.NewRand(f.Semilla)
+	inicial := func(n int) ML.Matrix {
+		m := ML.NewMatrix(n, f.Factores)
+		for _, fila := range m {
+			for j := range fila {
+				fila[j] = rng.NormFloat64() * f.DesviacionInicial
+			}
+		}
+		return m
+	}
+	f.Media = datos.Media()
+	f.SesgosUsuario = make([]float64, datos.NumUsuarios)
+	f.SesgosProducto = make([]float64, datos.NumProductos)
+	f.Usuarios = inicial(datos.NumUsuarios)
+	f.Productos = inicial(datos.NumProductos)
+	f.Historial = nil
+
+	lista := datos.Lista()
+	for epoca := 0; epoca < f.Epocas; epoca++ {
+		if f.Solver == ALS {
+			f.als()
+		} else {
+			f.sgd(lista, epoca)
+		}
+		rmse := RMSE(f, datos)
+		if math.IsNaN(rmse) || math.IsInf(rmse, 0) {
+			return errors.New("cf: training diverged, try a smaller learning rate")
+		}
+		f.Historial = append(f.Historial, rmse)
+	}
+	return nil
+}
+
+// Una época de ALS: primero los usuarios con los productos fijos y después al revés. Cada
+// fila es un problema de mínimos cuadrados independiente, así que se reparten en bloques
+// entre los workers y cada uno escribe solo sus filas.
+func (f *Factorizacion) als() {
+	f.resolverTodas(f.datos.porUsuario, f.SesgosUsuario, f.Usuarios, f.SesgosProducto, f.Productos)
+	f.resolverTodas(f.datos.porProducto, f.SesgosProducto, f.Productos, f.SesgosUsuario, f.Usuarios)
+}
+
+// Resuelve el sesgo y los factores de cada una de `filas` con los de las columnas fijos.
+func (f *Factorizacion) resolverTodas(filas []ML.Disperso, sesgos []float64, factores ML.Matrix, sesgosFijos []float64, fijos ML.Matrix) {
+	const bloque = 64
+	var wg sync.WaitGroup
+	siguiente := make(chan int)
+	for w := 0; w < f.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := f.nuevoSistema()
+			for inicio := range siguiente {
+				for i := inicio; i < min(inicio+bloque, len(filas)); i++ {
+					sesgos[i] = s.resolver(f, filas[i], factores[i], sesgosFijos, fijos)
+				}
+			}
+		}()
+	}
+	for inicio := 0; inicio < len(filas); inicio += bloque {
+		siguiente <- inicio
+	}
+	close(siguiente)
+	wg.Wait()
+}
+
+// Sistema de ecuaciones normales de una fila, (k+1)×(k+1), reutilizado entre filas.
+type sistema struct {
+	a ML.Matrix
+	b []float64
+	x []float64 // [1, y_p]
+}
+
+func (f *Factorizacion) nuevoSistema() *sistema {
+	return &sistema{a: ML.NewMatrix(f.Factores+1, f.Factores+1), b: make([]float64, f.Factores+1), x: make([]float64, f.Factores+1)}
+}
+
+// Minimiza Σ (r − μ − b_p − b − ⟨x, y_p⟩)² + λ·n·(b² + ‖x‖²) sobre las calificaciones de `fila`
+// con b_p e y_p fijos; guarda x en `factores` y devuelve b. Sin calificaciones ambos son 0.
+func (s *sistema) resolver(f *Factorizacion, fila ML.Disperso, factores []float64, sesgosFijos []float64, fijos ML.Matrix) float64 {
+	if fila.Len() == 0 {
+		for j := range factores {
+			factores[j] = 0
+		}
+		return 0
+	}
+	for j := range s.a {
+		for l := range s.a[j] {
+			s.a[j][l] = 0
+		}
+		s.b[j] = 0
+	}
+	s.x[0] = 1
+	for n, c := range fila.Indices {
+		copy(s.x[1:], fijos[c])
+		objetivo := fila.Valores[n] - f.Media - sesgosFijos[c]
+		for j, xj := range s.x {
+			s.b[j] += xj * objetivo
+			for l := 0; l <= j; l++ {
+				s.a[j][l] += xj * s.x[l]
+			}
+		}
+	}
+	lambda := f.Regularizacion * float64(fila.Len())
+	for j := range s.a {
+		s.a[j][j] += lambda
+	}
+	cholesky(s.a, s.b)
+	copy(factores, s.b[1:])
+	return s.b[0]
+}
+
+// Resuelve a·x = b con la factorización de Cholesky de `a`, simétrica definida positiva de la
+// que solo se usa el triángulo inferior; deja x en `b` y destruye `a`.
+func cholesky(a ML.Matrix, b []float64) {
+	n := len(b)
+	for j := 0; j < n; j++ {
+		suma := a[j][j]
+		for k := 0; k < j; k++ {
+			suma -= a[j][k] * a[j][k]
+		}
+		a[j][j] = math.Sqrt(suma)
+		for i := j + 1; i < n; i++ {
+			suma := a[i][j]
+			for k := 0; k < j; k++ {
+				suma -= a[i][k] * a[j][k]
+			}
+			a[i][j] = suma / a[j][j]
+		}
+	}
+	for i := 0; i < n; i++ { // L·z = b
+		for k := 0; k < i; k++ {
+			b[i] -= a[i][k] * b[k]
+		}
+		b[i] /= a[i][i]
+	}
+	for i := n - 1; i >= 0; i-- { // Lᵀ·x = z
+		for k := i + 1; k < n; k++ {
+			b[i] -= a[k][i] * b[k]
+		}
+		b[i] /= a[i][i]
+	}
+}
+
+// Una época de SGD sobre las calificaciones de `lista` en el orden de la época, repartidas en tramos
+// entre los workers. Antes de actualizar un par se toman el mutex de la franja del usuario y
+// luego el de la del producto, siempre en ese orden, así que no hay bloqueos mutuos.
+func (f *Factorizacion) sgd(lista []Calificacion, epoca int) {
+	var usuarios, productos [FRANJAS]sync.Mutex
+	var wg sync.WaitGroup
+	for _, tramo := range ML.Repartir(ML.RandEpoca(f.Semilla, epoca).Perm(len(lista)), f.Workers) {
+		wg.Add(1)
+		go func(tramo []int) {
+			defer wg.Done()
+			anterior := make([]float64, f.Factores)
+			for _, i := range tramo {
+				c := lista[i]
+				usuarios[c.Usuario%FRANJAS].Lock()
+				productos[c.Producto%FRANJAS].Lock()
+				f.paso(c, anterior)
+				productos[c.Producto%FRANJAS].Unlock()
+				usuarios[c.Usuario%FRANJAS].Unlock()
+			}
+		}(tramo)
+	}
+	wg.Wait()
+}
+
+// Paso de SGD con la calificación `c`; `anterior` es espacio para los factores del usuario
+// antes del paso.
+func (f *Factorizacion) paso(c Calificacion, anterior []float64) {
+	eta, lambda := f.TasaAprendizaje, f.Regularizacion
+	x, y := f.Usuarios[c.Usuario], f.Productos[c.Producto]
+	diferencia := c.Valor - f.prediccion(c.Usuario, c.Producto)
+	f.SesgosUsuario[c.Usuario] += eta * (diferencia - lambda*f.SesgosUsuario[c.Usuario])
+	f.SesgosProducto[c.Producto] += eta * (diferencia - lambda*f.SesgosProducto[c.Producto])
+	copy(anterior, x)
+	for j := range x {
+		x[j] += eta * (diferencia*y[j] - lambda*x[j])
+		y[j] += eta * (diferencia*anterior[j] - lambda*y[j])
+	}
+}
+
+// Predicción de un usuario y un producto conocidos.
+func (f *Factorizacion) prediccion(usuario, producto int) float64 {
+	r := f.Media + f.SesgosUsuario[usuario] + f.SesgosProducto[producto]
+	for j, v := range f.Usuarios[usuario] {
+		r += v * f.Productos[producto][j]
+	}
+	return r
+}
+
+// `PredictRating` predice la calificación de `usuario` para `producto`. Si el usuario o el
+// producto no se conocen se usa solo la media y el sesgo del otro.
+func (f *Factorizacion) PredictRating(usuario, producto int) float64 {
+	f.bloqueo.RLock()
+	defer f.bloqueo.RUnlock()
+	conocidoU := usuario >= 0 && usuario < len(f.Usuarios)
+	conocidoP := producto >= 0 && producto < len(f.Productos)
+	switch {
+	case conocidoU && conocidoP:
+		return f.prediccion(usuario, producto)
+	case conocidoU:
+		return f.Media + f.SesgosUsuario[usuario]
+	case conocidoP:
+		return f.Media + f.SesgosProducto[producto]
+	}
+	return f.Media
+}
+
+// `RecommendTopN` devuelve los `n` productos que `usuario` no calificó con mayor calificación
+// predicha.
+func (f *Factorizacion) RecommendTopN(usuario, n int) []Recomendacion {
+	f.bloqueo.RLock()
+	defer f.bloqueo.RUnlock()
+	if usuario < 0 || usuario >= len(f.Usuarios) {
+		return nil
+	}
+	fila := f.datos.Usuario(usuario)
+	if usuario >= f.datos.NumUsuarios {
+		fila = f.nuevos[usuario-f.datos.NumUsuarios]
+	}
+	var recomendaciones []Recomendacion
+	for p := range f.Productos {
+		if m := sort.SearchInts(fila.Indices, p); m < fila.Len() && fila.Indices[m] == p {
+			continue
+		}
+		recomendaciones = append(recomendaciones, Recomendacion{Producto: p, Puntuacion: f.prediccion(usuario, p)})
+	}
+	return mejores(recomendaciones, n)
+}
+
+// `AgregarUsuario` incorpora un usuario nuevo con sus `calificaciones` (ID del producto ->
+// calificación) sin reentrenar: resuelve su sesgo y sus factores con los de los productos
+// fijos, como un paso de ALS (fold-in). Devuelve el ID del usuario para las consultas.
+func (f *Factorizacion) AgregarUsuario(calificaciones map[int]float64) (int, error) {
+	if f.datos == nil {
+		return 0, errors.New("cf: model is not trained")
+	}
+	var fila ML.Disperso
+	for p := range calificaciones {
+		if p < 0 || p >= len(f.Productos) {
+			return 0, fmt.Errorf("cf: unknown product %d", p)
+		}
+		fila.Indices = append(fila.Indices, p)
+	}
+	sort.Ints(fila.Indices)
+	for _, p := range fila.Indices {
+		fila.Valores = append(fila.Valores, calificaciones[p])
+	}
+
+	factores := make([]float64, f.Factores)
+	sesgo := f.nuevoSistema().resolver(f, fila, factores, f.SesgosProducto, f.Productos)
+
+	f.bloqueo.Lock()
+	defer f.bloqueo.Unlock()
+	f.nuevos = append(f.nuevos, fila)
+	f.SesgosUsuario = append(f.SesgosUsuario, sesgo)
+	f.Usuarios = append(f.Usuarios, factores)
+	return len(f.Usuarios) - 1, nil
+}
@@ -0,0 +1,96 @@
+// Package FiltradoColaborativo implementa el filtrado colaborativo basado en usuarios de
+// FiltradoColaborativo_Secuencial y FiltradoColaborativo_Concurrente: predice la
+// calificación de un usuario para un producto a partir de los usuarios más parecidos.
+//
+// `BasadoEnUsuarios` y `BasadoEnProductos` precalculan en paralelo un índice con los vecinos
+// más similares de cada usuario o producto (coseno, Pearson o coseno ajustado, con
+// encogimiento opcional) sobre la matriz dispersa `Calificaciones`, y responden con él las
+// predicciones y recomendaciones sin volver a comparar. `CalificacionesImplicitas` guarda en
+// la misma matriz la confianza de vistas, clics y compras. `Factorizacion` aprende sesgos y
+// factores latentes con ALS o SGD en paralelo y agrega usuarios nuevos sin reentrenar.
+package FiltradoColaborativo
+
+import (
+	"math"
+
+	"pc2/ML"
+)
+
+// Definir estructura de los datos
+type Usuario struct {
+	ID             int
+	Calificaciones map[int]float64 // ID del producto -> calificación
+}
+
+// `DatasetAleatorio` crea `numUsuarios` usuarios que califican los `numProductos` productos
+// con valores aleatorios entre 0 y 5.
+func DatasetAleatorio(numUsuarios, numProductos int, semilla int64) []Usuario {
+	rng := ML.NewRand(semilla)
+	dataset := make([]Usuario, numUsuarios)
+	for i := 0; i < numUsuarios; i++ {
+		calificaciones := make(map[int]float64)
+		for j := 0; j < numProductos; j++ {
+			calificaciones[j] = rng.Float64() * 5 // Calificación aleatoria entre 0 y 5
+		}
+		dataset[i] = Usuario{ID: i, Calificaciones: calificaciones}
+	}
+	return dataset
+}
+
+// `SimilitudPearson` calcula la similitud entre dos usuarios con la correlación de Pearson
+// de los productos que ambos calificaron.
+func SimilitudPearson(u1, u2 Usuario) float64 {
+	// Calcular promedios de calificaciones
+	var sum1, sum2, sum1Sq, sum2Sq, pSum float64
+	n := 0
+
+	for prod, cal1 := range u1.Calificaciones {
+		if cal2, ok := u2.Calificaciones[prod]; ok {
+			n++
+			sum1 += cal1
+			sum2 += cal2
+			sum1Sq += cal1 * cal1
+			sum2Sq += cal2 * cal2
+			pSum += cal1 * cal2
+		}
+	}
+
+	if n == 0 {
+		return 0 // No hay calificaciones en común
+	}
+
+	num := pSum - (sum1 * sum2 / float64(n))
+	den := math.Sqrt((sum1Sq - sum1*sum1/float64(n)) * (sum2Sq - sum2*sum2/float64(n)))
+
+	if den == 0 {
+		return 0 // Evitar división por cero
+	}
+
+	return num / den
+}
+
+// `PredecirCalificacion` predice la calificación de `usuario` para `producto` como el
+// promedio de las calificaciones de los demás usuarios, ponderado por su similitud positiva.
+// Compara con todos los usuarios en cada llamada; para muchas consultas conviene el índice
+// de `BasadoEnUsuarios`.
+func PredecirCalificacion(usuario Usuario, producto int, dataset []Usuario) float64 {
+	var sumaSimilitudes, sumaPonderaciones float64
+
+	for _, u := range dataset {
+		if u.ID != usuario.ID {
+			sim := SimilitudPearson(usuario, u)
+			if sim > 0 {
+				if cal, ok := u.Calificaciones[producto]; ok {
+					sumaSimilitudes += sim
+					sumaPonderaciones += sim * cal
+				}
+			}
+		}
+	}
+
+	if sumaSimilitudes == 0 {
+		return 0 // No se puede predecir si no hay similitudes
+	}
+
+	return sumaPonderaciones / sumaSimilitudes
+}
@@ -0,0 +1,295 @@
+package FiltradoColaborativo
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// `Calificaciones` se queda con la última calificación repetida y calcula las medias.
+func TestCalificaciones(t *testing.T) {
+	c, err := NuevasCalificaciones([]Calificacion{{0, 2, 4}, {1, 0, 2}, {0, 2, 5}, {0, 1, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.NumUsuarios != 2 || c.NumProductos != 3 || c.Len() != 3 {
+		t.Fatalf("dimensions %dx%d with %d ratings, expected 2x3 with 3", c.NumUsuarios, c.NumProductos, c.Len())
+	}
+	if v, ok := c.Valor(0, 2); !ok || v != 5 {
+		t.Fatalf("Valor(0, 2) = %v, %v; expected 5, true", v, ok)
+	}
+	if _, ok := c.Valor(1, 2); ok {
+		t.Fatal("Valor(1, 2) should not exist")
+	}
+	if c.MediaUsuario(0) != 3 || c.MediaProducto(0) != 2 || c.Media() != 8.0/3 {
+		t.Fatalf("means %v %v %v", c.MediaUsuario(0), c.MediaProducto(0), c.Media())
+	}
+	if _, err := NuevasCalificaciones([]Calificacion{{-1, 0, 1}}); err == nil {
+		t.Fatal("expected an error for a negative id")
+	}
+
+	datos := CalificacionesAleatorias(50, 40, 0.3, 1)
+	train, test := datos.Split(0.8, 2)
+	if train.Len()+test.Len() != datos.Len() || train.NumProductos != datos.NumProductos {
+		t.Fatalf("split has %d + %d ratings of %d", train.Len(), test.Len(), datos.Len())
+	}
+}
+
+// Similitud de dos filas calculada directamente, para comparar con el índice.
+func similitudDirecta(s *similitudes, i, j int) float64 {
+	var producto, cuadrados1, cuadrados2 float64
+	a, b := s.filas[i], s.filas[j]
+	for n, columna := range a.Indices {
+		m := sort.SearchInts(b.Indices, columna)
+		if m < b.Len() && b.Indices[m] == columna {
+			producto += a.Valores[n] * b.Valores[m]
+			cuadrados1 += a.Valores[n] * a.Valores[n]
+			cuadrados2 += b.Valores[m] * b.Valores[m]
+		}
+	}
+	if s.normas != nil {
+		return producto / (s.normas[i] * s.normas[j])
+	}
+	return producto / math.Sqrt(cuadrados1*cuadrados2)
+}
+
+// El índice tiene los `K` vecinos de mayor similitud que da la comparación de todos los
+// pares, y no depende de la cantidad de workers ni del tamaño de los bloques.
+func TestVecinos(t *testing.T) {
+	datos := CalificacionesAleatorias(60, 30, 0.3, 3)
+	for _, medida := range []string{COSENO, PEARSON, COSENO_AJUSTADO} {
+		secuencial := &BasadoEnUsuarios{Similitud: medida, K: 5, Workers: 1}
+		paralelo := &BasadoEnUsuarios{Similitud: medida, K: 5, Workers: 3, Bloque: 7}
+		if err := secuencial.Fit(datos); err != nil {
+			t.Fatal(err)
+		}
+		if err := paralelo.Fit(datos); err != nil {
+			t.Fatal(err)
+		}
+		s, _ := transformar(medida, datos.porUsuario, datos.porProducto, datos.mediasUsuario, datos.mediasProducto)
+		for u := range secuencial.Vecinos {
+			var esperados []Vecino
+			for v := 0; v < datos.NumUsuarios; v++ {
+				if similitud := similitudDirecta(s, u, v); v != u && similitud > 0 {
+					esperados = append(esperados, Vecino{ID: v, Similitud: similitud})
+				}
+			}
+			sort.Slice(esperados, func(a, b int) bool { return peor(esperados[b], esperados[a]) })
+			esperados = esperados[:min(5, len(esperados))]
+
+			vecinos := secuencial.Vecinos[u]
+			if len(vecinos) != len(esperados) {
+				t.Fatalf("%s: user %d has %d neighbours, expected %d", medida, u, len(vecinos), len(esperados))
+			}
+			for n, v := range vecinos {
+				if v.ID != esperados[n].ID || math.Abs(v.Similitud-esperados[n].Similitud) > 1e-9 {
+					t.Fatalf("%s: user %d neighbour %d is %+v, expected %+v", medida, u, n, v, esperados[n])
+				}
+				if paralelo.Vecinos[u][n] != v {
+					t.Fatalf("%s: user %d differs with 3 workers", medida, u)
+				}
+			}
+		}
+	}
+	if err := (&BasadoEnUsuarios{Similitud: "jaccard"}).Fit(datos); err == nil {
+		t.Fatal("expected an error for an unknown similarity")
+	}
+}
+
+// RMSE de predecir la media de cada usuario, la referencia de los recomendadores.
+func rmseMediaUsuario(train, test *Calificaciones) float64 {
+	suma := 0.0
+	for _, c := range test.Lista() {
+		suma += (train.MediaUsuario(c.Usuario) - c.Valor) * (train.MediaUsuario(c.Usuario) - c.Valor)
+	}
+	return math.Sqrt(suma / float64(test.Len()))
+}
+
+// Con datos de gustos latentes, las predicciones mejoran a la media de cada usuario y las
+// recomendaciones son productos no calificados, ordenados por la calificación predicha.
+func TestBasadoEnUsuarios(t *testing.T) {
+	train, test := CalificacionesAleatorias(400, 60, 0.3, 5).Split(0.8, 5)
+	modelo := &BasadoEnUsuarios{K: 40, Workers: 4}
+	if err := modelo.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	if rmse, base := RMSE(modelo, test), rmseMediaUsuario(train, test); rmse > 0.9*base {
+		t.Fatalf("RMSE %.4f, user mean baseline %.4f", rmse, base)
+	}
+
+	for u := 0; u < 10; u++ {
+		recomendaciones := modelo.RecommendTopN(u, 5)
+		if len(recomendaciones) != 5 {
+			t.Fatalf("user %d got %d recommendations", u, len(recomendaciones))
+		}
+		for n, r := range recomendaciones {
+			if _, ok := train.Valor(u, r.Producto); ok {
+				t.Fatalf("user %d: product %d is already rated", u, r.Producto)
+			}
+			if math.Abs(r.Puntuacion-modelo.PredictRating(u, r.Producto)) > 1e-9 {
+				t.Fatalf("user %d: score %v, PredictRating %v", u, r.Puntuacion, modelo.PredictRating(u, r.Producto))
+			}
+			if n > 0 && r.Puntuacion > recomendaciones[n-1].Puntuacion {
+				t.Fatalf("user %d: recommendations are not sorted", u)
+			}
+		}
+	}
+}
+
+// El filtrado basado en productos mejora la media de cada usuario, el encogimiento reduce las
+// similitudes según las columnas en común, y las recomendaciones del índice inverso coinciden
+// con `PredictRating` y excluyen los productos calificados.
+func TestBasadoEnProductos(t *testing.T) {
+	train, test := CalificacionesAleatorias(400, 60, 0.3, 7).Split(0.8, 7)
+	modelo := &BasadoEnProductos{K: 20, Workers: 4}
+	if err := modelo.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	if rmse, base := RMSE(modelo, test), rmseMediaUsuario(train, test); rmse > 0.9*base {
+		t.Fatalf("RMSE %.4f, user mean baseline %.4f", rmse, base)
+	}
+
+	encogido := &BasadoEnProductos{K: 20, Encogimiento: 50, Workers: 2}
+	if err := encogido.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := transformar(COSENO_AJUSTADO, train.porProducto, train.porUsuario, train.mediasProducto, train.mediasUsuario)
+	for p, vecinos := range encogido.Vecinos {
+		for _, v := range vecinos {
+			comunes := 0
+			for _, u := range train.Producto(p).Indices {
+				if _, ok := train.Valor(u, v.ID); ok {
+					comunes++
+				}
+			}
+			esperada := similitudDirecta(s, p, v.ID) * float64(comunes) / (float64(comunes) + 50)
+			if math.Abs(v.Similitud-esperada) > 1e-9 {
+				t.Fatalf("product %d neighbour %d: similarity %v, expected %v", p, v.ID, v.Similitud, esperada)
+			}
+		}
+	}
+
+	for u := 0; u < 10; u++ {
+		recomendaciones := modelo.RecommendTopN(u, 5)
+		if len(recomendaciones) != 5 {
+			t.Fatalf("user %d got %d recommendations", u, len(recomendaciones))
+		}
+		for _, r := range recomendaciones {
+			if _, ok := train.Valor(u, r.Producto); ok {
+				t.Fatalf("user %d: product %d is already rated", u, r.Producto)
+			}
+			if math.Abs(r.Puntuacion-modelo.PredictRating(u, r.Producto)) > 1e-9 {
+				t.Fatalf("user %d: score %v, PredictRating %v", u, r.Puntuacion, modelo.PredictRating(u, r.Producto))
+			}
+		}
+	}
+}
+
+// Las interacciones se suman en confianzas y, con temas de productos, ambos recomendadores
+// encuentran muchas más interacciones de prueba que una recomendación al azar.
+func TestImplicitas(t *testing.T) {
+	c, err := CalificacionesImplicitas([]Evento{{0, 1, VISTA}, {0, 1, CLIC}, {1, 0, COMPRA}}, PESOS_IMPLICITOS, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Valor(0, 1); !c.Implicitas() || math.Abs(v-(1+2*math.Log(5))) > 1e-12 {
+		t.Fatalf("confidence %v, expected %v", v, 1+2*math.Log(5))
+	}
+	if _, err := CalificacionesImplicitas([]Evento{{0, 0, "me gusta"}}, PESOS_IMPLICITOS, 1); err == nil {
+		t.Fatal("expected an error for an unknown event type")
+	}
+
+	const productos, n = 100, 5
+	datos, err := CalificacionesImplicitas(EventosAleatorios(300, productos, 15, 10, 9), PESOS_IMPLICITOS, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	train, test := datos.Split(0.8, 9)
+	azar := float64(n) / productos
+	for _, modelo := range []Recomendador{&BasadoEnUsuarios{K: 30, Workers: 2}, &BasadoEnProductos{K: 20, Workers: 2}} {
+		if err := modelo.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		if recall := Recall(modelo, test, n); recall < 3*azar {
+			t.Fatalf("%T: recall %.3f, random %.3f", modelo, recall, azar)
+		}
+		for _, r := range modelo.RecommendTopN(0, n) {
+			if _, ok := train.Valor(0, r.Producto); ok {
+				t.Fatalf("%T: product %d is already used", modelo, r.Producto)
+			}
+		}
+	}
+}
+
+// ALS y SGD mejoran a la media de cada usuario en la prueba, y ALS no depende de la cantidad
+// de workers.
+func TestFactorizacion(t *testing.T) {
+	train, test := CalificacionesAleatorias(400, 80, 0.3, 11).Split(0.8, 11)
+	base := rmseMediaUsuario(train, test)
+	for _, solver := range []string{ALS, SGD} {
+		modelo := &Factorizacion{Solver: solver, Factores: 5, Epocas: 20, Workers: 4, Semilla: 1}
+		if solver == SGD {
+			modelo.TasaAprendizaje = 0.02
+		}
+		if err := modelo.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		if rmse := RMSE(modelo, test); rmse > 0.8*base {
+			t.Fatalf("%s: RMSE %.4f, user mean baseline %.4f", solver, rmse, base)
+		}
+		if h := modelo.Historial; h[len(h)-1] >= h[0] {
+			t.Fatalf("%s: training RMSE did not decrease: %v", solver, h)
+		}
+	}
+
+	uno := &Factorizacion{Factores: 5, Epocas: 3, Workers: 1, Semilla: 2}
+	cuatro := &Factorizacion{Factores: 5, Epocas: 3, Workers: 4, Semilla: 2}
+	if err := uno.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	if err := cuatro.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	for u := range uno.Usuarios {
+		for j, v := range uno.Usuarios[u] {
+			if cuatro.Usuarios[u][j] != v {
+				t.Fatalf("user %d factor %d: %v with 1 worker, %v with 4", u, j, v, cuatro.Usuarios[u][j])
+			}
+		}
+	}
+}
+
+// Un usuario agregado sin reentrenar con las calificaciones de uno existente recibe casi las
+// mismas predicciones, y sus recomendaciones excluyen lo que calificó.
+func TestAgregarUsuario(t *testing.T) {
+	train := CalificacionesAleatorias(300, 60, 0.3, 13)
+	modelo := &Factorizacion{Factores: 5, Epocas: 15, Workers: 2, Semilla: 3}
+	if err := modelo.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	calificaciones := make(map[int]float64)
+	fila := train.Usuario(7)
+	for n, p := range fila.Indices {
+		calificaciones[p] = fila.Valores[n]
+	}
+	nuevo, err := modelo.AgregarUsuario(calificaciones)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nuevo != train.NumUsuarios {
+		t.Fatalf("new user id %d, expected %d", nuevo, train.NumUsuarios)
+	}
+	for p := 0; p < train.NumProductos; p++ {
+		if diferencia := math.Abs(modelo.PredictRating(nuevo, p) - modelo.PredictRating(7, p)); diferencia > 0.1 {
+			t.Fatalf("product %d: folded-in prediction differs by %.4f", p, diferencia)
+		}
+	}
+	for _, r := range modelo.RecommendTopN(nuevo, 10) {
+		if _, ok := calificaciones[r.Producto]; ok {
+			t.Fatalf("product %d is already rated by the new user", r.Producto)
+		}
+	}
+	if _, err := modelo.AgregarUsuario(map[int]float64{train.NumProductos: 3}); err == nil {
+		t.Fatal("expected an error for an unknown product")
+	}
+}
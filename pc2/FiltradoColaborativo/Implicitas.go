@@ -0,0 +1,91 @@
+package FiltradoColaborativo
+
+import (
+	"fmt"
+	"math"
+
+	"pc2/ML"
+)
+
+// Tipos de interacción de los datos implícitos
+const (
+	VISTA  = "vista"
+	CLIC   = "clic"
+	COMPRA = "compra"
+)
+
+// `PESOS_IMPLICITOS` es el peso por defecto de cada tipo de interacción.
+var PESOS_IMPLICITOS = map[string]float64{VISTA: 1, CLIC: 3, COMPRA: 10}
+
+// Estructura `Evento`: una interacción de `Tipo` del usuario `Usuario` con `Producto`.
+type Evento struct {
+	Usuario  int
+	Producto int
+	Tipo     string
+}
+
+// `CalificacionesImplicitas` convierte interacciones en confianzas (Hu, Koren y Volinsky,
+// 2008): suma los `pesos` de los eventos de cada par usuario-producto y guarda
+// 1 + `alfa`·log(1 + suma). Los recomendadores tratan los pares sin eventos como confianza 0
+// en lugar de calificaciones desconocidas.
+func CalificacionesImplicitas(eventos []Evento, pesos map[string]float64, alfa float64) (*Calificaciones, error) {
+	if alfa < 0 {
+		return nil, fmt.Errorf("cf: negative confidence scale %v", alfa)
+	}
+	type par struct{ usuario, producto int }
+	sumas := make(map[par]float64)
+	var orden []par
+	for _, e := range eventos {
+		peso, ok := pesos[e.Tipo]
+		if !ok {
+			return nil, fmt.Errorf("cf: unknown event type %q", e.Tipo)
+		}
+		if e.Usuario < 0 || e.Producto < 0 {
+			return nil, fmt.Errorf("cf: negative id in event %+v", e)
+		}
+		clave := par{e.Usuario, e.Producto}
+		if _, visto := sumas[clave]; !visto {
+			orden = append(orden, clave)
+		}
+		sumas[clave] += peso
+	}
+
+	lista := make([]Calificacion, len(orden))
+	for i, clave := range orden {
+		lista[i] = Calificacion{Usuario: clave.usuario, Producto: clave.producto, Valor: 1 + alfa*math.Log1p(sumas[clave])}
+	}
+	c, err := NuevasCalificaciones(lista)
+	if err != nil {
+		return nil, err
+	}
+	c.implicitas = true
+	return c, nil
+}
+
+// `EventosAleatorios` genera `eventosPorUsuario` interacciones por usuario con estructura: los
+// productos se reparten en `grupos` temas y cada usuario elige uno; el 80% de sus eventos es
+// con productos de su tema y el resto con cualquiera. El 70% de los eventos son vistas, el 25%
+// clics y el 5% compras.
+func EventosAleatorios(numUsuarios, numProductos, eventosPorUsuario, grupos int, semilla int64) []Evento {
+	rng := ML.NewRand(semilla)
+	grupos = max(1, min(grupos, numProductos))
+	var eventos []Evento
+	for u := 0; u < numUsuarios; u++ {
+		tema := rng.Intn(grupos)
+		for n := 0; n < eventosPorUsuario; n++ {
+			producto := rng.Intn(numProductos)
+			if rng.Float64() < 0.8 {
+				// Productos del tema: tema, tema + grupos, tema + 2·grupos...
+				producto = tema + grupos*rng.Intn((numProductos-tema+grupos-1)/grupos)
+			}
+			tipo := VISTA
+			if x := rng.Float64(); x >= 0.95 {
+				tipo = COMPRA
+			} else if x >= 0.7 {
+				tipo = CLIC
+			}
+			eventos = append(eventos, Evento{Usuario: u, Producto: producto, Tipo: tipo})
+		}
+	}
+	return eventos
+}
@@ -0,0 +1,95 @@
+package FiltradoColaborativo
+
+import "errors"
+
+// Estructura `BasadoEnProductos`: filtrado colaborativo basado en productos (Sarwar et al.,
+// 2001) con un índice de los `K` productos más similares a cada producto. Como los productos
+// cambian menos que los usuarios, el índice se puede recalcular con menos frecuencia. Usa las
+// mismas `Calificaciones` que `BasadoEnUsuarios`, con calificaciones o datos implícitos.
+type BasadoEnProductos struct {
+	Similitud    string  // COSENO, PEARSON o COSENO_AJUSTADO; COSENO_AJUSTADO (COSENO con datos implícitos) si está vacío
+	K            int     // Vecinos por producto; 50 si es 0
+	Encogimiento float64 // λ: la similitud se multiplica por comunes / (comunes + λ); 0 no encoge
+	Bloque       int     // Productos por tarea del pool de workers; 64 si es 0
+	Workers      int
+
+	Vecinos  [][]Vecino // Vecinos de cada producto, de mayor a menor similitud
+	inversos [][]Vecino // Productos que tienen a cada producto entre sus vecinos
+	totales  []float64  // Suma de las similitudes de los vecinos de cada producto
+	datos    *Calificaciones
+}
+
+// `Fit` calcula el índice de vecinos de los productos de `datos`. El coseno ajustado resta a
+// cada calificación la media del usuario, y Pearson la media del producto.
+func (r *BasadoEnProductos) Fit(datos *Calificaciones) error {
+	if datos == nil || datos.Len() == 0 {
+		return errors.New("cf: empty ratings")
+	}
+	s, err := transformar(medida(r.Similitud, COSENO_AJUSTADO, datos), datos.porProducto, datos.porUsuario, datos.mediasProducto, datos.mediasUsuario)
+	if err != nil {
+		return err
+	}
+	if err := s.configurar(r.K, r.Bloque, r.Workers, r.Encogimiento); err != nil {
+		return err
+	}
+	r.Vecinos = s.vecinos()
+	r.inversos = make([][]Vecino, datos.NumProductos)
+	r.totales = make([]float64, datos.NumProductos)
+	for p, vecinos := range r.Vecinos {
+		for _, v := range vecinos {
+			r.inversos[v.ID] = append(r.inversos[v.ID], Vecino{ID: p, Similitud: v.Similitud})
+			r.totales[p] += v.Similitud
+		}
+	}
+	r.datos = datos
+	return nil
+}
+
+// `PredictRating` predice la calificación de `usuario` para `producto` como la media del
+// producto más el promedio de las desviaciones de los vecinos del producto que el usuario
+// calificó, ponderado por la similitud; sin ninguno devuelve la media del producto. Con
+// datos implícitos devuelve la confianza promedio del usuario en los vecinos, contando 0 los
+// que no usó.
+func (r *BasadoEnProductos) PredictRating(usuario, producto int) float64 {
+	centro := r.datos.centroProducto(producto)
+	if producto < 0 || producto >= len(r.Vecinos) {
+		return r.datos.estimar(centro, 0, 0, 0)
+	}
+	var suma, pesos float64
+	for _, v := range r.Vecinos[producto] {
+		if valor, ok := r.datos.Valor(usuario, v.ID); ok {
+			suma += v.Similitud * (valor - r.datos.centroProducto(v.ID))
+			pesos += v.Similitud
+		}
+	}
+	return r.datos.estimar(centro, suma, pesos, r.totales[producto])
+}
+
+// `RecommendTopN` devuelve los `n` productos que `usuario` no calificó con mayor calificación
+// predicha. Solo recorre los productos que tienen entre sus vecinos alguno calificado por el
+// usuario, con el índice inverso, y la puntuación coincide con `PredictRating`.
+func (r *BasadoEnProductos) RecommendTopN(usuario, n int) []Recomendacion {
+	fila := r.datos.Usuario(usuario)
+	suma := make([]float64, r.datos.NumProductos)
+	pesos := make([]float64, r.datos.NumProductos)
+	var candidatos []int
+	for m, j := range fila.Indices {
+		desviacion := fila.Valores[m] - r.datos.centroProducto(j)
+		for _, v := range r.inversos[j] {
+			if pesos[v.ID] == 0 {
+				candidatos = append(candidatos, v.ID)
+			}
+			suma[v.ID] += v.Similitud * desviacion
+			pesos[v.ID] += v.Similitud
+		}
+	}
+
+	var recomendaciones []Recomendacion
+	for _, p := range candidatos {
+		if _, calificado := r.datos.Valor(usuario, p); !calificado {
+			puntuacion := r.datos.estimar(r.datos.centroProducto(p), suma[p], pesos[p], r.totales[p])
+			recomendaciones = append(recomendaciones, Recomendacion{Producto: p, Puntuacion: puntuacion})
+		}
+	}
+	return mejores(recomendaciones, n)
+}
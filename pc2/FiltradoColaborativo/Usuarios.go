@@ -0,0 +1,108 @@
+package FiltradoColaborativo
+
+import (
+	"errors"
+	"sort"
+)
+
+// Estructura `BasadoEnUsuarios`: filtrado colaborativo basado en usuarios con un índice de
+// los `K` vecinos más similares de cada usuario, calculado una vez en `Fit` en paralelo. Las
+// consultas solo recorren los vecinos del usuario, en lugar de comparar con todos.
+type BasadoEnUsuarios struct {
+	Similitud    string  // COSENO, PEARSON o COSENO_AJUSTADO; PEARSON (COSENO con datos implícitos) si está vacío
+	K            int     // Vecinos por usuario; 50 si es 0
+	Encogimiento float64 // λ: la similitud se multiplica por comunes / (comunes + λ); 0 no encoge
+	Bloque       int     // Usuarios por tarea del pool de workers; 64 si es 0
+	Workers      int
+
+	Vecinos [][]Vecino // Vecinos de cada usuario, de mayor a menor similitud
+	datos   *Calificaciones
+}
+
+// Estructura `Recomendacion`: un producto recomendado y su calificación predicha.
+type Recomendacion struct {
+	Producto   int
+	Puntuacion float64
+}
+
+// `Fit` calcula el índice de vecinos de los usuarios de `datos`.
+func (r *BasadoEnUsuarios) Fit(datos *Calificaciones) error {
+	if datos == nil || datos.Len() == 0 {
+		return errors.New("cf: empty ratings")
+	}
+	s, err := transformar(medida(r.Similitud, PEARSON, datos), datos.porUsuario, datos.porProducto, datos.mediasUsuario, datos.mediasProducto)
+	if err != nil {
+		return err
+	}
+	if err := s.configurar(r.K, r.Bloque, r.Workers, r.Encogimiento); err != nil {
+		return err
+	}
+	r.Vecinos = s.vecinos()
+	r.datos = datos
+	return nil
+}
+
+// `PredictRating` predice la calificación de `usuario` para `producto` como su media más el
+// promedio de las desviaciones de sus vecinos que calificaron el producto, ponderado por la
+// similitud. Sin vecinos que lo hayan calificado devuelve la media del usuario. Con datos
+// implícitos devuelve la confianza promedio de los vecinos, contando 0 si no interactuaron.
+func (r *BasadoEnUsuarios) PredictRating(usuario, producto int) float64 {
+	centro := r.datos.centroUsuario(usuario)
+	if usuario < 0 || usuario >= len(r.Vecinos) {
+		return r.datos.estimar(centro, 0, 0, 0)
+	}
+	var suma, pesos, total float64
+	for _, v := range r.Vecinos[usuario] {
+		total += v.Similitud
+		if valor, ok := r.datos.Valor(v.ID, producto); ok {
+			suma += v.Similitud * (valor - r.datos.centroUsuario(v.ID))
+			pesos += v.Similitud
+		}
+	}
+	return r.datos.estimar(centro, suma, pesos, total)
+}
+
+// `RecommendTopN` devuelve los `n` productos que `usuario` no calificó con mayor calificación
+// predicha, entre los que calificaron sus vecinos.
+func (r *BasadoEnUsuarios) RecommendTopN(usuario, n int) []Recomendacion {
+	if usuario < 0 || usuario >= len(r.Vecinos) {
+		return nil
+	}
+	suma := make([]float64, r.datos.NumProductos)
+	pesos := make([]float64, r.datos.NumProductos)
+	var candidatos []int
+	total := 0.0
+	for _, v := range r.Vecinos[usuario] {
+		total += v.Similitud
+		fila := r.datos.porUsuario[v.ID]
+		centro := r.datos.centroUsuario(v.ID)
+		for m, p := range fila.Indices {
+			if pesos[p] == 0 {
+				candidatos = append(candidatos, p)
+			}
+			suma[p] += v.Similitud * (fila.Valores[m] - centro)
+			pesos[p] += v.Similitud
+		}
+	}
+
+	centro := r.datos.centroUsuario(usuario)
+	var recomendaciones []Recomendacion
+	for _, p := range candidatos {
+		if _, calificado := r.datos.Valor(usuario, p); !calificado {
+			recomendaciones = append(recomendaciones, Recomendacion{Producto: p, Puntuacion: r.datos.estimar(centro, suma[p], pesos[p], total)})
+		}
+	}
+	return mejores(recomendaciones, n)
+}
+
+// Ordena `recomendaciones` de mayor a menor puntuación (los empates, por producto) y deja
+// las `n` primeras.
+func mejores(recomendaciones []Recomendacion, n int) []Recomendacion {
+	sort.Slice(recomendaciones, func(a, b int) bool {
+		if recomendaciones[a].Puntuacion != recomendaciones[b].Puntuacion {
+			return recomendaciones[a].Puntuacion > recomendaciones[b].Puntuacion
+		}
+		return recomendaciones[a].Producto < recomendaciones[b].Producto
+	})
+	return recomendaciones[:max(0, min(n, len(recomendaciones)))]
+}
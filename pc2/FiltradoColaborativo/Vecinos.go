@@ -0,0 +1,224 @@
+package FiltradoColaborativo
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sync"
+
+	"pc2/ML"
+)
+
+// Medidas de similitud del índice de vecinos
+const (
+	COSENO          = "coseno"          // Coseno de las calificaciones, con las normas completas
+	PEARSON         = "pearson"         // Calificaciones menos la media de cada fila, normas de lo común
+	COSENO_AJUSTADO = "coseno_ajustado" // Calificaciones menos la media de la otra dimensión
+)
+
+// Estructura `Vecino`: un usuario (o producto) parecido y su similitud.
+type Vecino struct {
+	ID        int
+	Similitud float64
+}
+
+// Filas a comparar con su matriz transpuesta (el índice invertido), ya transformadas según la
+// medida. `normas` tiene la norma completa de cada fila para el coseno; si es nil se usan las
+// normas de las columnas comunes a cada par.
+type similitudes struct {
+	filas    []ML.Disperso
+	columnas []ML.Disperso
+	normas   []float64
+	encoger  float64
+	k        int
+	bloque   int
+	workers  int
+}
+
+// Medida de similitud `similitud` o, si está vacía, `defecto` con calificaciones y COSENO con
+// datos implícitos, donde todas las confianzas son positivas y centrarlas no tiene sentido.
+func medida(similitud, defecto string, datos *Calificaciones) string {
+	if similitud != "" {
+		return similitud
+	}
+	if datos.Implicitas() {
+		return COSENO
+	}
+	return defecto
+}
+
+// Transforma la matriz para la medida `medida`, con las filas en `filas` y las columnas en
+// `columnas`; `mediasFila` y `mediasColumna` son las medias de cada fila y columna.
+func transformar(medida string, filas, columnas []ML.Disperso, mediasFila, mediasColumna []float64) (*similitudes, error) {
+	centro := func(fila, columna int) float64 { return 0 }
+	switch medida {
+	case COSENO:
+	case PEARSON:
+		centro = func(fila, columna int) float64 { return mediasFila[fila] }
+	case COSENO_AJUSTADO:
+		centro = func(fila, columna int) float64 { return mediasColumna[columna] }
+	default:
+		return nil, fmt.Errorf("cf: unknown similarity %q", medida)
+	}
+
+	s := &similitudes{filas: make([]ML.Disperso, len(filas)), columnas: make([]ML.Disperso, len(columnas))}
+	for i, fila := range filas {
+		s.filas[i] = ML.Disperso{Indices: fila.Indices, Valores: make([]float64, fila.Len())}
+		for n, j := range fila.Indices {
+			s.filas[i].Valores[n] = fila.Valores[n] - centro(i, j)
+		}
+	}
+	for j, columna := range columnas {
+		s.columnas[j] = ML.Disperso{Indices: columna.Indices, Valores: make([]float64, columna.Len())}
+		for n, i := range columna.Indices {
+			s.columnas[j].Valores[n] = columna.Valores[n] - centro(i, j)
+		}
+	}
+	if medida == COSENO {
+		s.normas = make([]float64, len(filas))
+		for i, fila := range s.filas {
+			for _, v := range fila.Valores {
+				s.normas[i] += v * v
+			}
+			s.normas[i] = math.Sqrt(s.normas[i])
+		}
+	}
+	return s, nil
+}
+
+// Fija los parámetros del cálculo: `k` vecinos (50 si es 0), bloques de `bloque` filas (64
+// si es 0), `workers` goroutines y el encogimiento `encoger`.
+func (s *similitudes) configurar(k, bloque, workers int, encoger float64) error {
+	if encoger < 0 {
+		return fmt.Errorf("cf: negative shrinkage %v", encoger)
+	}
+	s.k, s.bloque, s.workers, s.encoger = k, bloque, workers, encoger
+	if s.k <= 0 {
+		s.k = 50
+	}
+	if s.bloque <= 0 {
+		s.bloque = 64
+	}
+	return nil
+}
+
+// Calcula los `k` vecinos de mayor similitud positiva de cada fila, ordenados de mayor a
+// menor (los empates, por ID). Las filas se reparten en bloques de `bloque` entre `workers`
+// goroutines; cada una acumula en sus propios arreglos y escribe solo las filas de sus
+// bloques, así que el resultado no depende de la cantidad de workers.
+func (s *similitudes) vecinos() [][]Vecino {
+	vecinos := make([][]Vecino, len(s.filas))
+	var wg sync.WaitGroup
+	siguiente := make(chan int)
+	for w := 0; w < max(s.workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a := nuevoAcumulador(len(s.filas))
+			for inicio := range siguiente {
+				for i := inicio; i < min(inicio+s.bloque, len(s.filas)); i++ {
+					vecinos[i] = s.fila(i, a)
+				}
+			}
+		}()
+	}
+	for inicio := 0; inicio < len(s.filas); inicio += s.bloque {
+		siguiente <- inicio
+	}
+	close(siguiente)
+	wg.Wait()
+	return vecinos
+}
+
+// Sumas por fila candidata y las filas tocadas desde el último reinicio.
+type acumulador struct {
+	producto, cuadrados1, cuadrados2 []float64
+	comunes                          []int
+	tocadas                          []int
+}
+
+func nuevoAcumulador(n int) *acumulador {
+	return &acumulador{
+		producto:   make([]float64, n),
+		cuadrados1: make([]float64, n),
+		cuadrados2: make([]float64, n),
+		comunes:    make([]int, n),
+	}
+}
+
+// Vecinos de la fila `i`: recorre sus columnas y, por el índice invertido, las demás filas
+// que comparten cada una; el costo es la cantidad de pares con columnas en común.
+func (s *similitudes) fila(i int, a *acumulador) []Vecino {
+	fila := s.filas[i]
+	for n, j := range fila.Indices {
+		x := fila.Valores[n]
+		columna := s.columnas[j]
+		for m, otra := range columna.Indices {
+			if otra == i {
+				continue
+			}
+			y := columna.Valores[m]
+			if a.comunes[otra] == 0 {
+				a.tocadas = append(a.tocadas, otra)
+			}
+			a.producto[otra] += x * y
+			a.cuadrados1[otra] += x * x
+			a.cuadrados2[otra] += y * y
+			a.comunes[otra]++
+		}
+	}
+
+	mejores := &monticulo{}
+	for _, otra := range a.tocadas {
+		denominador := math.Sqrt(a.cuadrados1[otra] * a.cuadrados2[otra])
+		if s.normas != nil {
+			denominador = s.normas[i] * s.normas[otra]
+		}
+		if denominador > 0 {
+			// Encogimiento: las similitudes con pocas columnas en común se acercan a 0.
+			comunes := float64(a.comunes[otra])
+			if similitud := a.producto[otra] / denominador * comunes / (comunes + s.encoger); similitud > 0 {
+				mejores.agregar(Vecino{ID: otra, Similitud: similitud}, s.k)
+			}
+		}
+		a.producto[otra], a.cuadrados1[otra], a.cuadrados2[otra], a.comunes[otra] = 0, 0, 0, 0
+	}
+	a.tocadas = a.tocadas[:0]
+
+	resultado := make([]Vecino, mejores.Len())
+	for n := len(resultado) - 1; n >= 0; n-- {
+		resultado[n] = heap.Pop(mejores).(Vecino)
+	}
+	return resultado
+}
+
+// Montículo de mínimos con los mejores vecinos encontrados: la raíz es el peor.
+type monticulo []Vecino
+
+// Si `a` es peor que `b`: menor similitud o, con la misma, mayor ID.
+func peor(a, b Vecino) bool {
+	if a.Similitud != b.Similitud {
+		return a.Similitud < b.Similitud
+	}
+	return a.ID > b.ID
+}
+
+func (m monticulo) Len() int           { return len(m) }
+func (m monticulo) Less(i, j int) bool { return peor(m[i], m[j]) }
+func (m monticulo) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m *monticulo) Push(x any)        { *m = append(*m, x.(Vecino)) }
+func (m *monticulo) Pop() any {
+	v := (*m)[len(*m)-1]
+	*m = (*m)[:len(*m)-1]
+	return v
+}
+
+// Agrega `v` si hay menos de `k` vecinos o si es mejor que el peor de ellos.
+func (m *monticulo) agregar(v Vecino, k int) {
+	if m.Len() < k {
+		heap.Push(m, v)
+	} else if peor((*m)[0], v) {
+		(*m)[0] = v
+		heap.Fix(m, 0)
+	}
+}
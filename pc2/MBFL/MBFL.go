@@ -0,0 +1,559 @@
+// Package MBFL implementa el modelo basado en factores latentes de MBFL_Secuencial y
+// MBFL_Concurrente: una máquina de factorización de segundo orden (Rendle 2010)
+//
+//	ŷ(x) = w0 + Σi wi·xi + Σi<j ⟨vi, vj⟩·xi·xj
+//
+// para regresión (pérdida cuadrática) o clasificación binaria (pérdida logística). Las
+// interacciones se calculan como ½·Σf [(Σi vif·xi)² - Σi vif²·xi²], que solo recorre las
+// características distintas de cero, así que una predicción cuesta O(k·nnz) y los ejemplos
+// se guardan como vectores dispersos (`ML.Disperso`).
+//
+// El entrenamiento usa SGD (un paso por ejemplo que solo toca las características presentes,
+// o mini-lotes con un `Optimizadores.Optimizador` y varios workers) o ALS, que resuelve cada
+// parámetro en forma cerrada con los demás fijos (solo con pérdida cuadrática).
+package MBFL
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"pc2/ML"
+	"pc2/Optimizadores"
+)
+
+// Pérdidas disponibles (`Objective`).
+const CUADRATICA = "cuadratica" // Regresión; `Y` es el valor objetivo
+const LOGISTICA = "logistica"   // Clasificación binaria; etiquetas de dos clases
+
+// Métodos de entrenamiento (`Solver`).
+const SGD = "sgd"
+const ALS = "als"
+
+// Estructura `FactorizationMachine` con los parámetros de entrenamiento, el sesgo, los pesos
+// lineales y el vector de factores latentes de cada característica. En clasificación la
+// decisión ŷ es positiva para `Classes[1]`.
+type FactorizationMachine struct {
+	NumFactors     int     // k; 8 si es 0
+	LearningRate   float64 // 0.01 si es 0
+	Regularization float64 // λ de la regularización L2 de pesos y factores (no del sesgo)
+	Objective      string  // CUADRATICA si es ""
+	Solver         string  // SGD si es ""
+	InitStdDev     float64 // Desviación de la inicialización normal de los factores; 0.1 si es 0
+	BatchSize      int     // Ejemplos por paso de SGD; 1 si es 0 con un worker, 64 con varios
+	Epochs         int     // 10 si es 0
+	Workers        int     // Goroutines que calculan el gradiente de cada mini-lote; ALS es secuencial
+	Seed           int64   // Semilla de la inicialización y del orden; si es 0 se usa la hora actual
+
+	// Optimizador de los pasos de los mini-lotes; si es nil, SGD con `LearningRate` constante.
+	Optimizer Optimizadores.Optimizador `json:"-"`
+
+	Classes        []float64 `json:",omitempty"`
+	Bias           float64
+	Weights        []float64
+	Factors        [][]float64
+	OptimizerState *Optimizadores.Estado `json:",omitempty"` // Para los puntos de control
+
+	sesgo  []float64   // El sesgo como vector para el optimizador
+	datos  *datos      // Dataset de entrenamiento en forma dispersa
+	origen *ML.Dataset // Dataset del que salen `datos` (nil con `FitDisperso`)
+}
+
+// Ejemplos de entrenamiento en forma dispersa, con el objetivo de la pérdida de cada uno
+// (±1 en clasificación).
+type datos struct {
+	filas    []ML.Disperso
+	y        []float64
+	columnas [][]entrada // Para ALS: los ejemplos en que aparece cada característica
+}
+
+// Valor de una característica en el ejemplo `fila`.
+type entrada struct {
+	fila  int
+	valor float64
+}
+
+// Completa la configuración con los valores por defecto y la valida.
+func (fm *FactorizationMachine) configurar() error {
+	if fm.NumFactors <= 0 {
+		fm.NumFactors = 8
+	}
+	if fm.LearningRate <= 0 {
+		fm.LearningRate = 0.01
+	}
+	if fm.InitStdDev <= 0 {
+		fm.InitStdDev = 0.1
+	}
+	if fm.Workers < 1 {
+		fm.Workers = 1
+	}
+	if fm.BatchSize <= 0 {
+		fm.BatchSize = 1
+		if fm.Workers > 1 {
+			fm.BatchSize = 64
+		}
+	}
+	if fm.Objective == "" {
+		fm.Objective = CUADRATICA
+	}
+	if fm.Solver == "" {
+		fm.Solver = SGD
+	}
+	switch fm.Objective {
+	case CUADRATICA, LOGISTICA:
+	default:
+		return fmt.Errorf("mbfl: unknown objective %q", fm.Objective)
+	}
+	switch fm.Solver {
+	case SGD:
+	case ALS:
+		if fm.Objective != CUADRATICA {
+			return fmt.Errorf("mbfl: %s solver needs the %s objective", ALS, CUADRATICA)
+		}
+	default:
+		return fmt.Errorf("mbfl: unknown solver %q", fm.Solver)
+	}
+	return nil
+}
+
+// Inicializar el modelo: sesgo y pesos en cero y factores normales con desviación `InitStdDev`.
+func (fm *FactorizationMachine) inicializar(numFeatures int) {
+	rng := ML.NewRand(fm.Seed)
+	fm.Bias = 0
+	fm.Weights = make([]float64, numFeatures)
+	fm.Factors = ML.NewMatrix(numFeatures, fm.NumFactors)
+	for i := range fm.Factors {
+		for f := range fm.Factors[i] {
+			fm.Factors[i][f] = rng.NormFloat64() * fm.InitStdDev
+		}
+	}
+}
+
+// Decisión ŷ de `x` en O(k·nnz). Deja en `sumas` (de largo `NumFactors`) Σj vjf·xj de cada
+// factor f, que es lo que necesita el gradiente de los factores. Las características que no
+// existían en el entrenamiento se ignoran.
+func (fm *FactorizationMachine) decision(x ML.Disperso, sumas []float64) float64 {
+	for f := range sumas {
+		sumas[f] = 0
+	}
+	prediccion := fm.Bias
+	cuadrados := 0.0
+	for n, j := range x.Indices {
+		if j >= len(fm.Weights) {
+			continue
+		}
+		v := x.Valores[n]
+		prediccion += fm.Weights[j] * v
+		for f, vf := range fm.Factors[j] {
+			sumas[f] += vf * v
+			cuadrados += vf * vf * v * v
+		}
+	}
+	interaccion := 0.0
+	for _, s := range sumas {
+		interaccion += s * s
+	}
+	return prediccion + (interaccion-cuadrados)/2
+}
+
+// `Decision` devuelve ŷ(`x`).
+func (fm *FactorizationMachine) Decision(x []float64) float64 {
+	return fm.DecisionDisperso(ML.Dispersar(x))
+}
+
+// `DecisionDisperso` devuelve ŷ(`x`) para un ejemplo disperso.
+func (fm *FactorizationMachine) DecisionDisperso(x ML.Disperso) float64 {
+	return fm.decision(x, make([]float64, fm.NumFactors))
+}
+
+// `Predict` devuelve el valor predicho para `entrada` o, en clasificación, la clase según el
+// signo de la decisión.
+func (fm *FactorizationMachine) Predict(entrada []float64) float64 {
+	return fm.clase(fm.Decision(entrada))
+}
+
+// `PredictDisperso` es `Predict` para un ejemplo disperso.
+func (fm *FactorizationMachine) PredictDisperso(x ML.Disperso) float64 {
+	return fm.clase(fm.DecisionDisperso(x))
+}
+
+// Clase de la decisión `f`, o `f` en regresión.
+func (fm *FactorizationMachine) clase(f float64) float64 {
+	if fm.Classes == nil {
+		return f
+	}
+	if f >= 0 {
+		return fm.Classes[1]
+	}
+	return fm.Classes[0]
+}
+
+// Objetivo de la pérdida para la etiqueta `etiqueta`: ±1 en clasificación.
+func (fm *FactorizationMachine) objetivo(etiqueta float64) float64 {
+	if fm.Classes == nil {
+		return etiqueta
+	}
+	if etiqueta == fm.Classes[1] {
+		return 1
+	}
+	return -1
+}
+
+// Pérdida de la decisión `f` para el objetivo `y`: ½(f - y)² o log(1 + e^(-y·f)).
+func (fm *FactorizationMachine) perdida(f, y float64) float64 {
+	if fm.Objective == LOGISTICA {
+		margen := -y * f
+		if margen > 30 {
+			return margen
+		}
+		return math.Log1p(math.Exp(margen))
+	}
+	return (f - y) * (f - y) / 2
+}
+
+// Derivada de la pérdida respecto de la decisión `f`; el gradiente de cada parámetro θ es
+// esta derivada por ∂ŷ/∂θ: 1 para el sesgo, xj para wj y xj·(Σi vif·xi - vjf·xj) para vjf.
+func (fm *FactorizationMachine) derivada(f, y float64) float64 {
+	if fm.Objective == LOGISTICA {
+		return -y / (1 + math.Exp(y*f))
+	}
+	return f - y
+}
+
+// Parámetros del modelo como vectores: el sesgo, los pesos lineales y luego los factores de
+// cada característica.
+func (fm *FactorizationMachine) parametros() [][]float64 {
+	return append([][]float64{fm.sesgo, fm.Weights}, fm.Factors...)
+}
+
+// Prepara los ejemplos de `train` para el entrenamiento.
+func (fm *FactorizationMachine) cargar(train *ML.DatasetDisperso) *datos {
+	d := &datos{filas: train.X, y: make([]float64, train.Len())}
+	for i, etiqueta := range train.Y {
+		d.y[i] = fm.objetivo(etiqueta)
+	}
+	if fm.Solver == ALS {
+		d.columnas = make([][]entrada, train.Features())
+		for i, x := range d.filas {
+			for n, j := range x.Indices {
+				d.columnas[j] = append(d.columnas[j], entrada{fila: i, valor: x.Valores[n]})
+			}
+		}
+	}
+	return d
+}
+
+// `Fit` inicializa el modelo y lo entrena sobre `train` durante `Epochs` épocas (ver
+// `Epoca`); para usar validación, callbacks o puntos de control se entrena con
+// `ML.Entrenar`.
+func (fm *FactorizationMachine) Fit(train *ML.Dataset) error {
+	if fm.Epochs <= 0 {
+		fm.Epochs = 10
+	}
+	_, err := ML.Entrenar(fm, train, ML.Entrenamiento{Epocas: fm.Epochs})
+	return err
+}
+
+// `FitDisperso` inicializa el modelo y lo entrena sobre los ejemplos dispersos de `train`
+// durante `Epochs` épocas.
+func (fm *FactorizationMachine) FitDisperso(train *ML.DatasetDisperso) error {
+	if err := ML.CheckTrainDisperso(train); err != nil {
+		return err
+	}
+	if fm.Epochs <= 0 {
+		fm.Epochs = 10
+	}
+	if err := fm.preparar(train, false); err != nil {
+		return err
+	}
+	for epoca := 0; epoca < fm.Epochs; epoca++ {
+		if _, err := fm.epoca(epoca); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// `Preparar` valida la configuración, obtiene las clases en clasificación, inicializa los
+// parámetros para `train` (salvo al retomar un punto de control) y crea el estado del
+// optimizador de los mini-lotes.
+func (fm *FactorizationMachine) Preparar(train *ML.Dataset, reanudar bool) error {
+	if err := fm.preparar(train.Disperso(), reanudar); err != nil {
+		return err
+	}
+	fm.origen = train
+	return nil
+}
+
+// `Preparar` para un dataset disperso, que queda guardado para las épocas siguientes.
+func (fm *FactorizationMachine) preparar(train *ML.DatasetDisperso, reanudar bool) error {
+	if err := fm.configurar(); err != nil {
+		return err
+	}
+	fm.Classes = nil
+	if fm.Objective == LOGISTICA {
+		vistas := make(map[float64]bool)
+		for _, etiqueta := range train.Y {
+			if !vistas[etiqueta] {
+				vistas[etiqueta] = true
+				fm.Classes = append(fm.Classes, etiqueta)
+			}
+		}
+		if len(fm.Classes) != 2 {
+			return fmt.Errorf("mbfl: %s objective needs exactly two classes, got %d", LOGISTICA, len(fm.Classes))
+		}
+		sort.Float64s(fm.Classes)
+	}
+	fm.datos = fm.cargar(train)
+	fm.origen = nil
+
+	if reanudar {
+		if len(fm.Weights) != train.Features() || len(fm.Factors) != train.Features() {
+			return fmt.Errorf("mbfl: checkpoint has %d weights, dataset has %d features", len(fm.Weights), train.Features())
+		}
+	} else {
+		fm.Seed = ML.FijarSemilla(fm.Seed)
+		fm.inicializar(train.Features())
+		fm.OptimizerState = nil
+	}
+	fm.sesgo = []float64{fm.Bias}
+	if !fm.minilotes() || fm.Optimizer == nil {
+		// Sin `Optimizer` los mini-lotes dan el paso de SGD ellos mismos (ver `descenso`).
+		fm.OptimizerState = nil
+		return nil
+	}
+	if fm.OptimizerState == nil {
+		fm.OptimizerState = Optimizadores.NuevoEstado(fm.Optimizer, fm.parametros())
+	} else {
+		fm.OptimizerState.Asociar(fm.Optimizer, fm.parametros())
+	}
+	return nil
+}
+
+// Indica si SGD se hace por mini-lotes con el optimizador (con un `Optimizer`, más de un
+// ejemplo por paso o varios workers) en lugar de pasos dispersos por ejemplo.
+func (fm *FactorizationMachine) minilotes() bool {
+	return fm.Solver == SGD && (fm.Optimizer != nil || fm.BatchSize > 1 || fm.Workers > 1)
+}
+
+// `Epoca` recorre una vez `train` con el método de `Solver` y devuelve la pérdida media vista
+// durante la época. `train` se convierte a la forma dispersa solo si no es el dataset de la
+// época anterior (o de `Preparar`).
+func (fm *FactorizationMachine) Epoca(train *ML.Dataset, epoca int) (float64, error) {
+	if train != fm.origen {
+		if train.Features() != len(fm.Weights) {
+			return 0, fmt.Errorf("mbfl: model has %d weights, dataset has %d features", len(fm.Weights), train.Features())
+		}
+		for _, etiqueta := range train.Y {
+			if fm.Classes != nil && etiqueta != fm.Classes[0] && etiqueta != fm.Classes[1] {
+				return 0, fmt.Errorf("mbfl: label %v is not one of the classes %v", etiqueta, fm.Classes)
+			}
+		}
+		fm.datos = fm.cargar(train.Disperso())
+		fm.origen = train
+	}
+	return fm.epoca(epoca)
+}
+
+// Una época sobre el dataset cargado en `datos`.
+func (fm *FactorizationMachine) epoca(epoca int) (float64, error) {
+	d := fm.datos
+	var total float64
+	switch {
+	case fm.Solver == ALS:
+		total = fm.als(d)
+	case fm.minilotes():
+		total = fm.descenso(d, epoca)
+	default:
+		total = fm.sgd(d, epoca)
+	}
+	if math.IsNaN(total) || math.IsInf(total, 0) || math.IsNaN(fm.Bias) {
+		return 0, errors.New("mbfl: training diverged, try a smaller learning rate")
+	}
+	return total / float64(len(d.filas)), nil
+}
+
+// Una época de SGD con un paso por ejemplo, en orden aleatorio. Cada paso solo actualiza el
+// sesgo y los parámetros de las características presentes en el ejemplo (también la
+// regularización), así que cuesta O(k·nnz). Devuelve la suma de las pérdidas.
+func (fm *FactorizationMachine) sgd(d *datos, epoca int) float64 {
+	eta, lambda := fm.LearningRate, fm.Regularization
+	sumas := make([]float64, fm.NumFactors)
+	total := 0.0
+	for _, i := range ML.RandEpoca(fm.Seed, epoca).Perm(len(d.filas)) {
+		x := d.filas[i]
+		prediccion := fm.decision(x, sumas)
+		total += fm.perdida(prediccion, d.y[i])
+		g := fm.derivada(prediccion, d.y[i])
+		fm.Bias -= eta * g
+		for n, j := range x.Indices {
+			v := x.Valores[n]
+			fm.Weights[j] -= eta * (g*v + lambda*fm.Weights[j])
+			factores := fm.Factors[j]
+			for f, vf := range factores {
+				factores[f] -= eta * (g*v*(sumas[f]-vf*v) + lambda*vf)
+			}
+		}
+	}
+	return total
+}
+
+// Suma en `g` (con la forma de `parametros`) el gradiente de la pérdida del ejemplo `x` con
+// objetivo `y`, sin la regularización, y devuelve la pérdida. `sumas` es un vector auxiliar
+// de largo `NumFactors`.
+func (fm *FactorizationMachine) gradiente(x ML.Disperso, y float64, sumas []float64, g [][]float64) float64 {
+	prediccion := fm.decision(x, sumas)
+	derivada := fm.derivada(prediccion, y)
+	g[0][0] += derivada
+	for n, j := range x.Indices {
+		v := x.Valores[n]
+		g[1][j] += derivada * v
+		for f, vf := range fm.Factors[j] {
+			g[2+j][f] += derivada * v * (sumas[f] - vf*v)
+		}
+	}
+	return fm.perdida(prediccion, y)
+}
+
+// Una época de SGD por mini-lotes: el gradiente de cada lote se reparte entre los workers
+// con `ML.GradienteParalelo`, que anota los parámetros que toca cada ejemplo; se promedia y
+// solo a esos parámetros se les suma la regularización, igual que en `sgd`. Sin `Optimizer`
+// el paso de SGD también recorre solo los parámetros tocados, así que un lote cuesta
+// O(k·nnz); con `Optimizer` su paso recorre todos los parámetros (O(k·d) por lote).
+// Devuelve la suma de las pérdidas.
+func (fm *FactorizationMachine) descenso(d *datos, epoca int) float64 {
+	sumas := ML.NewMatrix(fm.Workers, fm.NumFactors)
+	parametros := fm.parametros()
+	calculo := ML.NuevoGradienteDisperso(fm.Workers, parametros, func(w int, indices []int, g [][]float64, tocados *ML.Tocados) float64 {
+		perdida := 0.0
+		for _, i := range indices {
+			x := d.filas[i]
+			perdida += fm.gradiente(x, d.y[i], sumas[w], g)
+			tocados.Tocar(0, 0)
+			for _, j := range x.Indices {
+				tocados.Tocar(1, j)
+				for f := range fm.Factors[j] {
+					tocados.Tocar(2+j, f)
+				}
+			}
+		}
+		return perdida
+	})
+	defer calculo.Cerrar()
+
+	total := 0.0
+	for _, lote := range ML.Lotes(ML.RandEpoca(fm.Seed, epoca).Perm(len(d.filas)), fm.BatchSize) {
+		g, tocados, perdida := calculo.CalcularDisperso(lote)
+		total += perdida
+		escala := 1 / float64(len(lote))
+		fm.sesgo[0] = fm.Bias
+		for _, q := range tocados {
+			gradiente := g[q.K][q.J] * escala
+			if q.K > 0 {
+				gradiente += fm.Regularization * parametros[q.K][q.J]
+			}
+			if fm.OptimizerState == nil {
+				parametros[q.K][q.J] -= fm.LearningRate * gradiente
+			} else {
+				g[q.K][q.J] = gradiente
+			}
+		}
+		if fm.OptimizerState != nil {
+			fm.OptimizerState.Paso(g)
+		}
+		fm.Bias = fm.sesgo[0]
+	}
+	return total
+}
+
+// Una pasada de ALS (Rendle et al. 2011) para la pérdida cuadrática: cada parámetro θ (el
+// sesgo, cada peso y luego cada factor) se reemplaza por el que minimiza la pérdida media más
+// λ/2·θ² con los demás fijos. Como ŷ es lineal en θ, con es = ŷs - ys y hs = ∂ŷs/∂θ ese
+// valor es θ* = Σs (θ·hs - es)·hs / (Σs hs² + n·λ). Los errores y las sumas Σj vjf·xsj de
+// cada ejemplo se actualizan después de cada cambio, así que la pasada cuesta O(k·nnz).
+// Devuelve la suma de las pérdidas al terminar.
+func (fm *FactorizationMachine) als(d *datos) float64 {
+	n := len(d.filas)
+	errores := make([]float64, n)
+	sumas := ML.NewMatrix(n, fm.NumFactors)
+	for s, x := range d.filas {
+		errores[s] = fm.decision(x, sumas[s]) - d.y[s]
+	}
+	lambda := float64(n) * fm.Regularization
+
+	// Sesgo, sin regularización: se resta el error medio.
+	media := 0.0
+	for _, e := range errores {
+		media += e
+	}
+	media /= float64(n)
+	fm.Bias -= media
+	for s := range errores {
+		errores[s] -= media
+	}
+
+	// Pesos lineales: hs = xsj.
+	for j, columna := range d.columnas {
+		if len(columna) == 0 {
+			continue
+		}
+		w := fm.Weights[j]
+		numerador, denominador := 0.0, lambda
+		for _, c := range columna {
+			numerador += (w*c.valor - errores[c.fila]) * c.valor
+			denominador += c.valor * c.valor
+		}
+		nuevo := numerador / denominador
+		for _, c := range columna {
+			errores[c.fila] += (nuevo - w) * c.valor
+		}
+		fm.Weights[j] = nuevo
+	}
+
+	// Factores: hs = xsj·(Σi vif·xsi - vjf·xsj), que no depende de vjf.
+	for f := 0; f < fm.NumFactors; f++ {
+		for j, columna := range d.columnas {
+			if len(columna) == 0 {
+				continue
+			}
+			v := fm.Factors[j][f]
+			numerador, denominador := 0.0, lambda
+			for _, c := range columna {
+				h := c.valor * (sumas[c.fila][f] - v*c.valor)
+				numerador += (v*h - errores[c.fila]) * h
+				denominador += h * h
+			}
+			if denominador == 0 {
+				continue
+			}
+			nuevo := numerador / denominador
+			for _, c := range columna {
+				h := c.valor * (sumas[c.fila][f] - v*c.valor)
+				errores[c.fila] += (nuevo - v) * h
+				sumas[c.fila][f] += (nuevo - v) * c.valor
+			}
+			fm.Factors[j][f] = nuevo
+		}
+	}
+
+	total := 0.0
+	for _, e := range errores {
+		total += e * e / 2
+	}
+	return total
+}
+
+// `Loss` devuelve la pérdida media sobre `d`: ½(y - ŷ)² en regresión o la logística en
+// clasificación.
+func (fm *FactorizationMachine) Loss(d *ML.Dataset) float64 {
+	if d.Len() == 0 {
+		return 0
+	}
+	sumas := make([]float64, fm.NumFactors)
+	total := 0.0
+	for i, x := range d.X {
+		total += fm.perdida(fm.decision(ML.Dispersar(x), sumas), fm.objetivo(d.Y[i]))
+	}
+	return total / float64(d.Len())
+}
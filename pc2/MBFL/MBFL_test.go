@@ -0,0 +1,267 @@
+package MBFL
+
+import (
+	"math"
+	"testing"
+
+	"pc2/ML"
+	"pc2/Optimizadores"
+)
+
+// Máquina de factorización con parámetros aleatorios para `features` características.
+func modeloAleatorio(features, k int, objetivo string, seed int64) *FactorizationMachine {
+	fm := &FactorizationMachine{NumFactors: k, InitStdDev: 0.5, Objective: objetivo, Seed: seed}
+	fm.inicializar(features)
+	rng := ML.NewRand(seed + 1)
+	fm.Bias = rng.NormFloat64()
+	for j := range fm.Weights {
+		fm.Weights[j] = rng.NormFloat64()
+	}
+	fm.sesgo = []float64{fm.Bias}
+	return fm
+}
+
+// Dataset generado por una máquina de factorización conocida; cada característica es
+// distinta de cero con probabilidad `densidad`. En clasificación la etiqueta es 1 si la
+// decisión del modelo es positiva y 0 si no.
+func datasetFM(n, features int, densidad float64, objetivo string, seed int64) *ML.Dataset {
+	modelo := modeloAleatorio(features, 3, objetivo, seed)
+	modelo.Bias = 0
+	rng := ML.NewRand(seed + 2)
+	d := &ML.Dataset{X: ML.NewMatrix(n, features), Y: make([]float64, n)}
+	for i := range d.X {
+		for j := range d.X[i] {
+			if rng.Float64() < densidad {
+				d.X[i][j] = rng.Float64()*2 - 1
+			}
+		}
+		d.Y[i] = modelo.Decision(d.X[i])
+		if objetivo == LOGISTICA {
+			d.Y[i] = math.Max(0, math.Copysign(1, d.Y[i]))
+		}
+	}
+	return d
+}
+
+// La decisión en O(k·nnz) coincide con la suma explícita de las interacciones de a pares.
+func TestDecision(t *testing.T) {
+	fm := modeloAleatorio(12, 4, CUADRATICA, 1)
+	x := datasetFM(1, 12, 0.6, CUADRATICA, 2).X[0]
+	esperado := fm.Bias
+	for i := range x {
+		esperado += fm.Weights[i] * x[i]
+		for j := i + 1; j < len(x); j++ {
+			producto := 0.0
+			for f := 0; f < fm.NumFactors; f++ {
+				producto += fm.Factors[i][f] * fm.Factors[j][f]
+			}
+			esperado += producto * x[i] * x[j]
+		}
+	}
+	if got := fm.Decision(x); math.Abs(got-esperado) > 1e-9 {
+		t.Errorf("Decision = %v, want %v", got, esperado)
+	}
+}
+
+// El gradiente coincide con las diferencias finitas de la pérdida en cada parámetro.
+func TestGradienteNumerico(t *testing.T) {
+	for _, objetivo := range []string{CUADRATICA, LOGISTICA} {
+		fm := modeloAleatorio(8, 3, objetivo, 3)
+		x := ML.Dispersar(datasetFM(1, 8, 0.7, CUADRATICA, 4).X[0])
+		y := 0.7
+		if objetivo == LOGISTICA {
+			y = -1
+		}
+		parametros := fm.parametros()
+		g := make([][]float64, len(parametros))
+		for k, p := range parametros {
+			g[k] = make([]float64, len(p))
+		}
+		sumas := make([]float64, fm.NumFactors)
+		fm.gradiente(x, y, sumas, g)
+
+		perdida := func() float64 { return fm.perdida(fm.decision(x, sumas), y) }
+		const h = 1e-6
+		for k, p := range parametros {
+			for j := range p {
+				valor := func(delta float64) {
+					p[j] += delta
+					if k == 0 {
+						fm.Bias = p[0]
+					}
+				}
+				valor(h)
+				mas := perdida()
+				valor(-2 * h)
+				menos := perdida()
+				valor(h)
+				numerico := (mas - menos) / (2 * h)
+				if math.Abs(numerico-g[k][j]) > 1e-6*math.Max(1, math.Abs(numerico)) {
+					t.Errorf("%s: gradient of parameter %d/%d = %v, numerical %v", objetivo, k, j, g[k][j], numerico)
+				}
+			}
+		}
+	}
+}
+
+// Los tres métodos aprenden las interacciones de un dataset generado por una máquina de
+// factorización: el RMSE de prueba baja a menos de la décima parte del de predecir la media.
+func TestRegresion(t *testing.T) {
+	datos := datasetFM(3000, 20, 0.3, CUADRATICA, 5)
+	train, test := datos.Split(0.8, 5)
+	media := 0.0
+	for _, y := range test.Y {
+		media += y
+	}
+	media /= float64(test.Len())
+	base := 0.0
+	for _, y := range test.Y {
+		base += (y - media) * (y - media)
+	}
+	base = math.Sqrt(base / float64(test.Len()))
+
+	casos := []struct {
+		nombre string
+		fm     *FactorizationMachine
+	}{
+		{"sgd", &FactorizationMachine{NumFactors: 6, LearningRate: 0.05, Regularization: 1e-4, Epochs: 40, Seed: 1}},
+		{"als", &FactorizationMachine{NumFactors: 6, Solver: ALS, Regularization: 1e-4, Epochs: 15, Seed: 1}},
+		{"adam", &FactorizationMachine{NumFactors: 6, Optimizer: &Optimizadores.Adam{Tasa: Optimizadores.Constante(0.02)}, BatchSize: 16, Workers: 4, Regularization: 1e-5, Epochs: 60, Seed: 1}},
+	}
+	for _, caso := range casos {
+		if err := caso.fm.Fit(train); err != nil {
+			t.Fatalf("%s: %v", caso.nombre, err)
+		}
+		if rmse := ML.RMSE(caso.fm, test); rmse > base/10 {
+			t.Errorf("%s: RMSE %.4f, baseline %.4f", caso.nombre, rmse, base)
+		}
+	}
+}
+
+// Con pérdida logística la máquina aprende clases definidas por el signo de otra máquina de
+// factorización; ALS no acepta esa pérdida.
+func TestClasificacion(t *testing.T) {
+	datos := datasetFM(3000, 10, 0.5, LOGISTICA, 6)
+	for i := range datos.Y {
+		datos.Y[i] = datos.Y[i]*2 - 1 // Etiquetas -1 y 1
+	}
+	train, test := datos.Split(0.8, 6)
+	fm := &FactorizationMachine{NumFactors: 6, Objective: LOGISTICA, LearningRate: 0.1, Regularization: 1e-4, Epochs: 40, Seed: 2}
+	if err := fm.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	if fm.Classes[0] != -1 || fm.Classes[1] != 1 {
+		t.Errorf("Classes = %v", fm.Classes)
+	}
+	if precision := ML.Accuracy(fm, test); precision < 0.9 {
+		t.Errorf("accuracy %.3f", precision)
+	}
+	if err := (&FactorizationMachine{Objective: LOGISTICA, Solver: ALS}).Fit(train); err == nil {
+		t.Error("ALS accepted the logistic objective")
+	}
+}
+
+// Con varios workers el resultado no depende de la planificación y coincide con el
+// entrenamiento secuencial con el mismo tamaño de lote.
+func TestWorkers(t *testing.T) {
+	datos := datasetFM(500, 15, 0.4, CUADRATICA, 7)
+	entrenar := func(workers int) *FactorizationMachine {
+		fm := &FactorizationMachine{NumFactors: 4, LearningRate: 0.05, BatchSize: 32, Workers: workers, Epochs: 5, Seed: 3}
+		if err := fm.Fit(datos); err != nil {
+			t.Fatal(err)
+		}
+		return fm
+	}
+	secuencial, a, b := entrenar(1), entrenar(4), entrenar(4)
+	for j := range a.Factors {
+		for f := range a.Factors[j] {
+			if a.Factors[j][f] != b.Factors[j][f] {
+				t.Fatalf("factor %d/%d differs between runs: %v, %v", j, f, a.Factors[j][f], b.Factors[j][f])
+			}
+			if math.Abs(a.Factors[j][f]-secuencial.Factors[j][f]) > 1e-9 {
+				t.Fatalf("factor %d/%d = %v, sequential %v", j, f, a.Factors[j][f], secuencial.Factors[j][f])
+			}
+		}
+	}
+}
+
+// `FitDisperso` entrena con ejemplos dispersos de muchas características y da el mismo
+// modelo que `Fit` con las filas densas.
+func TestDisperso(t *testing.T) {
+	train, test := ML.SparseClassification(20000, 1000, 20, 0, 8).Split(0.8, 8)
+	fm := &FactorizationMachine{NumFactors: 4, Objective: LOGISTICA, LearningRate: 0.1, Regularization: 1e-5, Seed: 4}
+	if err := fm.FitDisperso(train); err != nil {
+		t.Fatal(err)
+	}
+	if precision := ML.AccuracyDisperso(fm, test); precision < 0.9 {
+		t.Errorf("sparse accuracy %.3f", precision)
+	}
+
+	pequeño := ML.SparseClassification(300, 40, 6, 0, 9)
+	for _, solver := range []string{SGD, ALS} {
+		disperso := &FactorizationMachine{NumFactors: 3, Solver: solver, Epochs: 3, Seed: 5}
+		denso := &FactorizationMachine{NumFactors: 3, Solver: solver, Epochs: 3, Seed: 5}
+		if err := disperso.FitDisperso(pequeño); err != nil {
+			t.Fatal(err)
+		}
+		if err := denso.Fit(pequeño.Denso()); err != nil {
+			t.Fatal(err)
+		}
+		for j := range denso.Factors {
+			if denso.Weights[j] != disperso.Weights[j] || denso.Factors[j][0] != disperso.Factors[j][0] {
+				t.Fatalf("%s: feature %d differs between dense and sparse training", solver, j)
+			}
+		}
+	}
+}
+
+// `Epoca` entrena con el dataset que recibe aunque no sea el de `Preparar` y rechaza uno con
+// otra cantidad de características.
+func TestEpoca(t *testing.T) {
+	a, b := datasetFM(1000, 8, 0.5, CUADRATICA, 8).Split(0.5, 8)
+	entrenar := func(preparar *ML.Dataset) *FactorizationMachine {
+		fm := &FactorizationMachine{NumFactors: 3, LearningRate: 0.05, Seed: 4}
+		if err := fm.Preparar(preparar, false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fm.Epoca(b, 0); err != nil {
+			t.Fatal(err)
+		}
+		return fm
+	}
+	esperado, fm := entrenar(b), entrenar(a)
+	for j := range fm.Factors {
+		if fm.Weights[j] != esperado.Weights[j] || fm.Factors[j][0] != esperado.Factors[j][0] {
+			t.Fatalf("feature %d: weight %v, expected %v", j, fm.Weights[j], esperado.Weights[j])
+		}
+	}
+	if _, err := fm.Epoca(datasetFM(10, 5, 0.5, CUADRATICA, 9), 1); err == nil {
+		t.Error("Epoca accepted a dataset with another number of features")
+	}
+}
+
+// Los mini-lotes solo regularizan y actualizan los parámetros de las características
+// presentes en el lote: los de una característica que nunca aparece no cambian.
+func TestMiniLotesDispersos(t *testing.T) {
+	datos := datasetFM(400, 6, 0.5, CUADRATICA, 10)
+	for i := range datos.X {
+		datos.X[i][0] = 0
+	}
+	fm := &FactorizationMachine{NumFactors: 3, LearningRate: 0.05, Regularization: 0.1, BatchSize: 16, Workers: 2, Seed: 5}
+	if err := fm.Preparar(datos, false); err != nil {
+		t.Fatal(err)
+	}
+	inicial := append([]float64{fm.Weights[0]}, fm.Factors[0]...)
+	vivo := append([]float64{fm.Weights[1]}, fm.Factors[1]...)
+	if _, err := fm.Epoca(datos, 0); err != nil {
+		t.Fatal(err)
+	}
+	for f, v := range append([]float64{fm.Weights[0]}, fm.Factors[0]...) {
+		if v != inicial[f] {
+			t.Fatalf("parameter %d of an absent feature changed from %v to %v", f, inicial[f], v)
+		}
+	}
+	if fm.Factors[1][0] == vivo[1] {
+		t.Fatal("the factors of a present feature did not change")
+	}
+}
@@ -0,0 +1,102 @@
+package ML
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Columnas del archivo de afiliados del SIS (Afiliados_activos_DM_SIS.csv) que usa tp.go:
+// las columnas 1 a 4 como características y la 7 como etiqueta.
+var SIS_FEATURES = []int{1, 2, 3, 4}
+
+const SIS_LABEL = 7
+
+// `LoadSIS` lee el archivo de afiliados del SIS con las columnas de tp.go.
+func LoadSIS(filename string) (*Dataset, []string, error) {
+	return LoadCSV(filename, SIS_FEATURES, SIS_LABEL)
+}
+
+// `LoadCSV` lee un CSV con cabecera. Las columnas `features` se leen como números; igual que
+// en tp.go, un valor vacío o no numérico cuenta como 0. La columna `label` se trata como
+// clase: devuelve los valores distintos ordenados y guarda en `Y` el índice de cada uno
+// (por ejemplo "No" → 0 y "Si" → 1). Si todas las etiquetas son números, se ordenan por valor.
+func LoadCSV(filename string, features []int, label int) (*Dataset, []string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) < 2 {
+		return nil, nil, fmt.Errorf("%s: no data rows", filename)
+	}
+
+	ultima := label
+	for _, columna := range features {
+		if columna > ultima {
+			ultima = columna
+		}
+	}
+
+	dataset := &Dataset{}
+	etiquetas := []string{}
+	for i, record := range records[1:] { // Saltar la fila de encabezado
+		if len(record) <= ultima {
+			return nil, nil, fmt.Errorf("%s: line %d has %d columns, expected at least %d", filename, i+2, len(record), ultima+1)
+		}
+		fila := make([]float64, len(features))
+		for j, columna := range features {
+			fila[j], _ = strconv.ParseFloat(strings.TrimSpace(record[columna]), 64)
+		}
+		dataset.X = append(dataset.X, fila)
+		etiquetas = append(etiquetas, strings.TrimSpace(record[label]))
+	}
+
+	clases := distinctLabels(etiquetas)
+	indice := make(map[string]float64, len(clases))
+	for k, clase := range clases {
+		indice[clase] = float64(k)
+	}
+	dataset.Y = make([]float64, len(etiquetas))
+	for i, etiqueta := range etiquetas {
+		dataset.Y[i] = indice[etiqueta]
+	}
+	return dataset, clases, nil
+}
+
+// Devuelve las etiquetas distintas, ordenadas por valor si todas son números y
+// alfabéticamente si no.
+func distinctLabels(etiquetas []string) []string {
+	vistas := make(map[string]bool)
+	clases := []string{}
+	numericas := true
+	for _, etiqueta := range etiquetas {
+		if vistas[etiqueta] {
+			continue
+		}
+		vistas[etiqueta] = true
+		clases = append(clases, etiqueta)
+		if _, err := strconv.ParseFloat(etiqueta, 64); err != nil {
+			numericas = false
+		}
+	}
+	sort.Slice(clases, func(a, b int) bool {
+		if numericas {
+			x, _ := strconv.ParseFloat(clases[a], 64)
+			y, _ := strconv.ParseFloat(clases[b], 64)
+			return x < y
+		}
+		return clases[a] < clases[b]
+	})
+	return clases
+}
@@ -0,0 +1,35 @@
+package ML
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// `LoadSIS` lee las columnas de tp.go y codifica las etiquetas como índices de clase.
+func TestLoadSIS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sis.csv")
+	content := "CODIGO,EDAD,CANT_ATENCIONES,VALOR_NETO,DIAS_HOSP,SEXO,DEPARTAMENTO,CON_DX_HIPERTENSION\n" +
+		"a1,64,3,120.5,0,F,LIMA,Si\n" +
+		"a2,51,1,,2,M,CUSCO,No\n" +
+		"a3,70,x,80,1,F,PUNO,Si\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dataset, clases, err := LoadSIS(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clases) != 2 || clases[0] != "No" || clases[1] != "Si" {
+		t.Fatalf("classes %v, expected [No Si]", clases)
+	}
+	if dataset.Len() != 3 || dataset.Features() != 4 {
+		t.Fatalf("dataset is %dx%d, expected 3x4", dataset.Len(), dataset.Features())
+	}
+	if dataset.X[1][2] != 0 || dataset.X[2][1] != 0 || dataset.X[0][2] != 120.5 {
+		t.Fatalf("unexpected features %v", dataset.X)
+	}
+	if dataset.Y[0] != 1 || dataset.Y[1] != 0 || dataset.Y[2] != 1 {
+		t.Fatalf("unexpected labels %v", dataset.Y)
+	}
+}
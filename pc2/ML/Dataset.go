@@ -0,0 +1,216 @@
+// Package ML reúne lo que comparten los algoritmos de la PC2: el tipo `Dataset` y su
+// versión dispersa (`DatasetDisperso`, con lectura y escritura de archivos libsvm), los
+// generadores de datos sintéticos con semilla, la división en entrenamiento y prueba,
+// la interfaz `Model` y las métricas de evaluación.
+package ML
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Tipo `Matrix`: una fila por ejemplo y una columna por característica.
+type Matrix [][]float64
+
+// `NewMatrix` crea una matriz de `rows` filas y `cols` columnas en cero.
+func NewMatrix(rows, cols int) Matrix {
+	matrix := make(Matrix, rows)
+	for i := range matrix {
+		matrix[i] = make([]float64, cols)
+	}
+	return matrix
+}
+
+// `Rows` devuelve la cantidad de filas.
+func (m Matrix) Rows() int {
+	return len(m)
+}
+
+// `Cols` devuelve la cantidad de columnas (0 si la matriz no tiene filas).
+func (m Matrix) Cols() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+// Estructura `Dataset` con las características `X` y la etiqueta `Y` de cada ejemplo. En
+// clasificación `Y` guarda el índice de la clase (0, 1, ...) y en regresión el valor objetivo.
+type Dataset struct {
+	X Matrix
+	Y []float64
+}
+
+// `NewDataset` valida que `x` e `y` tengan la misma cantidad de ejemplos y que todas las
+// filas tengan la misma cantidad de características.
+func NewDataset(x Matrix, y []float64) (*Dataset, error) {
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("dataset: %d rows but %d labels", len(x), len(y))
+	}
+	for i, row := range x {
+		if len(row) != x.Cols() {
+			return nil, fmt.Errorf("dataset: row %d has %d features, expected %d", i, len(row), x.Cols())
+		}
+	}
+	return &Dataset{X: x, Y: y}, nil
+}
+
+// `Len` devuelve la cantidad de ejemplos.
+func (d *Dataset) Len() int {
+	return len(d.X)
+}
+
+// `Features` devuelve la cantidad de características.
+func (d *Dataset) Features() int {
+	return d.X.Cols()
+}
+
+// `Subset` devuelve un dataset con los ejemplos `indices`, en ese orden. Las filas se
+// comparten con `d`, no se copian.
+func (d *Dataset) Subset(indices []int) *Dataset {
+	subset := &Dataset{X: make(Matrix, len(indices)), Y: make([]float64, len(indices))}
+	for i, index := range indices {
+		subset.X[i] = d.X[index]
+		subset.Y[i] = d.Y[index]
+	}
+	return subset
+}
+
+// `Split` mezcla los ejemplos con la semilla `seed` y devuelve la fracción `ratio` para
+// entrenamiento y el resto para prueba (lo que hacía `dividirDataset` en cada programa).
+func (d *Dataset) Split(ratio float64, seed int64) (*Dataset, *Dataset) {
+	indices := NewRand(seed).Perm(d.Len())
+	numEntrenamiento := int(float64(d.Len()) * ratio)
+	return d.Subset(indices[:numEntrenamiento]), d.Subset(indices[numEntrenamiento:])
+}
+
+// `Partition` divide los ejemplos en `parts` bloques consecutivos para entrenar en paralelo;
+// el último bloque incluye los ejemplos restantes. Nunca devuelve bloques vacíos.
+func (d *Dataset) Partition(parts int) []*Dataset {
+	if parts > d.Len() {
+		parts = d.Len()
+	}
+	if parts < 1 {
+		parts = 1
+	}
+	tamañoParte := d.Len() / parts
+	partes := make([]*Dataset, parts)
+	for i := range partes {
+		inicio := i * tamañoParte
+		fin := inicio + tamañoParte
+		if i == parts-1 {
+			fin = d.Len()
+		}
+		partes[i] = &Dataset{X: d.X[inicio:fin], Y: d.Y[inicio:fin]}
+	}
+	return partes
+}
+
+// `NewRand` crea un generador con la semilla `seed`; si es 0 se usa la hora actual.
+func NewRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// `RandomClassification` genera `n` ejemplos con `features` características uniformes
+// entre 0 y `scale` y una etiqueta aleatoria 0 o 1, como el `crearDataset` original. Al no
+// haber relación entre características y etiquetas, sirve para medir tiempos, no precisión.
+func RandomClassification(n, features int, scale float64, seed int64) *Dataset {
+	rng := NewRand(seed)
+	dataset := &Dataset{X: NewMatrix(n, features), Y: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		for j := 0; j < features; j++ {
+			dataset.X[i][j] = rng.Float64() * scale
+		}
+		dataset.Y[i] = float64(rng.Intn(2))
+	}
+	return dataset
+}
+
+// `RandomRegression` genera `n` ejemplos con características uniformes entre 0 y `scale`
+// y un valor objetivo aleatorio entre 0 y `max`.
+func RandomRegression(n, features int, scale, max float64, seed int64) *Dataset {
+	rng := NewRand(seed)
+	dataset := &Dataset{X: NewMatrix(n, features), Y: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		for j := 0; j < features; j++ {
+			dataset.X[i][j] = rng.Float64() * scale
+		}
+		dataset.Y[i] = rng.Float64() * max
+	}
+	return dataset
+}
+
+// `LinearClassification` genera `n` ejemplos con características uniformes entre -1 y 1,
+// etiquetados 0 o 1 según el lado de un hiperplano aleatorio en el que caen. Cada etiqueta
+// se invierte con probabilidad `noise`, de modo que la precisión esperada de un clasificador
+// lineal perfecto es 1 - `noise`.
+func LinearClassification(n, features int, noise float64, seed int64) *Dataset {
+	rng := NewRand(seed)
+	normal := make([]float64, features)
+	for j := range normal {
+		normal[j] = rng.NormFloat64()
+	}
+	sesgo := rng.Float64()*0.5 - 0.25
+
+	dataset := &Dataset{X: NewMatrix(n, features), Y: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		suma := sesgo
+		for j := 0; j < features; j++ {
+			dataset.X[i][j] = rng.Float64()*2 - 1
+			suma += normal[j] * dataset.X[i][j]
+		}
+		if suma >= 0 {
+			dataset.Y[i] = 1
+		}
+		if rng.Float64() < noise {
+			dataset.Y[i] = 1 - dataset.Y[i]
+		}
+	}
+	return dataset
+}
+
+// `Blobs` genera `n` ejemplos repartidos entre `classes` clases: cada clase es una nube
+// gaussiana de desviación `spread` alrededor de un centro con coordenadas uniformes entre
+// -1 y 1. Sirve para probar clasificadores multiclase.
+func Blobs(n, features, classes int, spread float64, seed int64) *Dataset {
+	rng := NewRand(seed)
+	centros := NewMatrix(classes, features)
+	for k := range centros {
+		for j := range centros[k] {
+			centros[k][j] = rng.Float64()*2 - 1
+		}
+	}
+
+	dataset := &Dataset{X: NewMatrix(n, features), Y: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		clase := rng.Intn(classes)
+		for j := 0; j < features; j++ {
+			dataset.X[i][j] = centros[clase][j] + rng.NormFloat64()*spread
+		}
+		dataset.Y[i] = float64(clase)
+	}
+	return dataset
+}
+
+// `Circles` genera `n` ejemplos de dos características uniformes entre -1 y 1, con clase 1
+// si están a menos de 0.6 del origen y 0 si no. Ninguna recta separa las clases, por lo que
+// sirve para probar modelos no lineales. Cada etiqueta se invierte con probabilidad `noise`.
+func Circles(n int, noise float64, seed int64) *Dataset {
+	rng := NewRand(seed)
+	dataset := &Dataset{X: NewMatrix(n, 2), Y: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		x, y := rng.Float64()*2-1, rng.Float64()*2-1
+		dataset.X[i][0], dataset.X[i][1] = x, y
+		if x*x+y*y < 0.36 {
+			dataset.Y[i] = 1
+		}
+		if rng.Float64() < noise {
+			dataset.Y[i] = 1 - dataset.Y[i]
+		}
+	}
+	return dataset
+}
@@ -0,0 +1,37 @@
+package ML
+
+import (
+	"testing"
+)
+
+// La división depende solo de la semilla y reparte todos los ejemplos.
+func TestSplitIsSeeded(t *testing.T) {
+	dataset := RandomClassification(1000, 3, 10, 7)
+	train, test := dataset.Split(0.8, 7)
+	if train.Len() != 800 || test.Len() != 200 {
+		t.Fatalf("split sizes %d/%d, expected 800/200", train.Len(), test.Len())
+	}
+	again, _ := RandomClassification(1000, 3, 10, 7).Split(0.8, 7)
+	for i := range train.X {
+		if train.X[i][0] != again.X[i][0] || train.Y[i] != again.Y[i] {
+			t.Fatalf("row %d differs between runs with the same seed", i)
+		}
+	}
+}
+
+// Los bloques de `Partition` cubren el dataset sin huecos ni bloques vacíos.
+func TestPartitionCoversDataset(t *testing.T) {
+	dataset := RandomRegression(10, 2, 1, 1, 3)
+	for parts := 1; parts <= 12; parts++ {
+		total := 0
+		for _, part := range dataset.Partition(parts) {
+			if part.Len() == 0 {
+				t.Fatalf("Partition(%d) returned an empty part", parts)
+			}
+			total += part.Len()
+		}
+		if total != dataset.Len() {
+			t.Fatalf("Partition(%d) covers %d of %d rows", parts, total, dataset.Len())
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package ML
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Estructura `Disperso` con un vector disperso: solo se guardan las posiciones distintas de
+// cero (`Indices`, en orden creciente) y sus valores.
+type Disperso struct {
+	Indices []int
+	Valores []float64
+}
+
+// `Dispersar` devuelve las posiciones distintas de cero de `x`.
+func Dispersar(x []float64) Disperso {
+	var d Disperso
+	for j, v := range x {
+		if v != 0 {
+			d.Indices = append(d.Indices, j)
+			d.Valores = append(d.Valores, v)
+		}
+	}
+	return d
+}
+
+// `Len` devuelve la cantidad de posiciones distintas de cero.
+func (d Disperso) Len() int {
+	return len(d.Indices)
+}
+
+// `Producto` devuelve el producto escalar con el vector denso `w`; las posiciones fuera de
+// `w` cuentan como 0.
+func (d Disperso) Producto(w []float64) float64 {
+	suma := 0.0
+	for n, j := range d.Indices {
+		if j < len(w) {
+			suma += w[j] * d.Valores[n]
+		}
+	}
+	return suma
+}
+
+// `Denso` devuelve el vector como un slice de `dimension` posiciones (las posteriores se
+// descartan).
+func (d Disperso) Denso(dimension int) []float64 {
+	x := make([]float64, dimension)
+	for n, j := range d.Indices {
+		if j < dimension {
+			x[j] = d.Valores[n]
+		}
+	}
+	return x
+}
+
+// Estructura `DatasetDisperso`: como `Dataset`, pero con cada ejemplo como vector disperso.
+// `Dimension` es la cantidad de características (al menos 1 + el mayor índice usado).
+type DatasetDisperso struct {
+	X         []Disperso
+	Y         []float64
+	Dimension int
+}
+
+// `Len` devuelve la cantidad de ejemplos.
+func (d *DatasetDisperso) Len() int {
+	return len(d.X)
+}
+
+// `Features` devuelve la cantidad de características.
+func (d *DatasetDisperso) Features() int {
+	return d.Dimension
+}
+
+// `Subset` devuelve un dataset con los ejemplos `indices`, en ese orden, compartiendo las
+// filas con `d`.
+func (d *DatasetDisperso) Subset(indices []int) *DatasetDisperso {
+	subset := &DatasetDisperso{X: make([]Disperso, len(indices)), Y: make([]float64, len(indices)), Dimension: d.Dimension}
+	for i, index := range indices {
+		subset.X[i] = d.X[index]
+		subset.Y[i] = d.Y[index]
+	}
+	return subset
+}
+
+// `Split` mezcla los ejemplos con la semilla `seed` y devuelve la fracción `ratio` para
+// entrenamiento y el resto para prueba, igual que `Dataset.Split`.
+func (d *DatasetDisperso) Split(ratio float64, seed int64) (*DatasetDisperso, *DatasetDisperso) {
+	indices := NewRand(seed).Perm(d.Len())
+	numEntrenamiento := int(float64(d.Len()) * ratio)
+	return d.Subset(indices[:numEntrenamiento]), d.Subset(indices[numEntrenamiento:])
+}
+
+// `Denso` convierte el dataset a `Dataset`, con `Dimension` columnas.
+func (d *DatasetDisperso) Denso() *Dataset {
+	denso := &Dataset{X: make(Matrix, d.Len()), Y: d.Y}
+	for i, x := range d.X {
+		denso.X[i] = x.Denso(d.Dimension)
+	}
+	return denso
+}
+
+// `Disperso` convierte el dataset a `DatasetDisperso`; las etiquetas se comparten.
+func (d *Dataset) Disperso() *DatasetDisperso {
+	disperso := &DatasetDisperso{X: make([]Disperso, d.Len()), Y: d.Y, Dimension: d.Features()}
+	for i, x := range d.X {
+		disperso.X[i] = Dispersar(x)
+	}
+	return disperso
+}
+
+// `CheckTrainDisperso` valida el dataset de entrenamiento que recibe `FitDisperso`.
+func CheckTrainDisperso(train *DatasetDisperso) error {
+	if train == nil || train.Len() == 0 {
+		return errEmpty
+	}
+	if train.Dimension == 0 {
+		return errNoFeatures
+	}
+	if len(train.Y) != train.Len() {
+		return errors.New("sparse dataset: labels and rows differ in length")
+	}
+	return nil
+}
+
+// Interfaz `ModeloDisperso` de los modelos que se entrenan y predicen con vectores
+// dispersos (`SGD.Lineal`, `SVM.SVM`, `MBFL.FactorizationMachine`) sin pasar por filas densas.
+type ModeloDisperso interface {
+	FitDisperso(train *DatasetDisperso) error
+	PredictDisperso(x Disperso) float64
+}
+
+// `AccuracyDisperso` es `Accuracy` para un dataset disperso.
+func AccuracyDisperso(model ModeloDisperso, test *DatasetDisperso) float64 {
+	if test.Len() == 0 {
+		return 0
+	}
+	correctos := 0
+	for i, x := range test.X {
+		if model.PredictDisperso(x) == test.Y[i] {
+			correctos++
+		}
+	}
+	return float64(correctos) / float64(test.Len())
+}
+
+// `RMSEDisperso` es `RMSE` para un dataset disperso.
+func RMSEDisperso(model ModeloDisperso, test *DatasetDisperso) float64 {
+	if test.Len() == 0 {
+		return 0
+	}
+	total := 0.0
+	for i, x := range test.X {
+		diferencia := model.PredictDisperso(x) - test.Y[i]
+		total += diferencia * diferencia
+	}
+	return math.Sqrt(total / float64(test.Len()))
+}
+
+// `SparseClassification` genera `n` ejemplos dispersos de `features` características con
+// `nnz` posiciones distintas de cero cada uno (valores uniformes entre 0 y 1, como las
+// frecuencias de palabras de un texto), etiquetados 0 o 1 según el lado de un hiperplano
+// aleatorio que pasa por el origen. Cada etiqueta se invierte con probabilidad `noise`.
+func SparseClassification(n, features, nnz int, noise float64, seed int64) *DatasetDisperso {
+	if nnz > features {
+		nnz = features
+	}
+	rng := NewRand(seed)
+	hiperplano := make([]float64, features)
+	for j := range hiperplano {
+		hiperplano[j] = rng.NormFloat64()
+	}
+	dataset := &DatasetDisperso{X: make([]Disperso, n), Y: make([]float64, n), Dimension: features}
+	for i := range dataset.X {
+		elegidas := make(map[int]bool, nnz)
+		x := Disperso{Indices: make([]int, 0, nnz), Valores: make([]float64, nnz)}
+		for len(x.Indices) < nnz {
+			j := rng.Intn(features)
+			if !elegidas[j] {
+				elegidas[j] = true
+				x.Indices = append(x.Indices, j)
+			}
+		}
+		sort.Ints(x.Indices)
+		for k := range x.Valores {
+			x.Valores[k] = rng.Float64()
+		}
+		dataset.X[i] = x
+		if x.Producto(hiperplano) > 0 {
+			dataset.Y[i] = 1
+		}
+		if rng.Float64() < noise {
+			dataset.Y[i] = 1 - dataset.Y[i]
+		}
+	}
+	return dataset
+}
@@ -0,0 +1,272 @@
+package ML
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// Interfaz `Iterativo` de los modelos que se entrenan por épocas (`DL`, `RedesNeuronales`,
+// `SVM`, `MBFL`). `Entrenar` llama a `Preparar` una vez y luego a `Epoca` por cada época.
+// El estado del modelo (configuración, parámetros y estado del optimizador) debe poder
+// guardarse en JSON para los puntos de control.
+type Iterativo interface {
+	Model
+	// `Preparar` valida la configuración con `train` y, si `reanudar` es false, inicializa
+	// los parámetros; si es true los parámetros vienen de un punto de control.
+	Preparar(train *Dataset, reanudar bool) error
+	// `Epoca` recorre una vez `train` y devuelve la pérdida media de entrenamiento. `epoca`
+	// empieza en 0 y sirve para derivar el orden aleatorio de la época (ver `RandEpoca`).
+	Epoca(train *Dataset, epoca int) (float64, error)
+	// `Loss` devuelve la pérdida media del modelo sobre `d`.
+	Loss(d *Dataset) float64
+}
+
+// Estructura `ResumenEpoca` con los resultados de una época.
+type ResumenEpoca struct {
+	Epoca             int     // Desde 1
+	Perdida           float64 // Pérdida media de entrenamiento
+	PerdidaValidacion float64 // NaN si no hay datos de validación
+	Metrica           float64 // Métrica de validación; NaN si no hay métrica o validación
+	Duracion          time.Duration
+}
+
+// Interfaz `Callback` que `Entrenar` llama al final de cada época; si devuelve true el
+// entrenamiento se detiene.
+type Callback interface {
+	FinEpoca(modelo Iterativo, resumen ResumenEpoca) (bool, error)
+}
+
+// Interfaz opcional de los callbacks que actúan al terminar el entrenamiento.
+type Finalizador interface {
+	FinEntrenamiento(modelo Iterativo) error
+}
+
+// Estructura `Entrenamiento` con la configuración del bucle de entrenamiento.
+type Entrenamiento struct {
+	Epocas     int
+	Validacion *Dataset                      // Datos para la pérdida y la métrica de validación (opcional)
+	Metrica    func(Model, *Dataset) float64 // Por ejemplo `Accuracy` (opcional)
+	Callbacks  []Callback
+
+	// Punto de control desde el que se retoma el entrenamiento: el modelo se carga de ese
+	// archivo y se sigue en la época siguiente a la guardada. Si el archivo no existe se
+	// empieza desde cero, así que el mismo comando sirve para empezar y para retomar.
+	Reanudar string
+}
+
+// Contenido del archivo de un punto de control.
+type puntoDeControl struct {
+	Epoca  int // Épocas completadas
+	Modelo json.RawMessage
+}
+
+// `Entrenar` entrena `modelo` con `train` durante `config.Epocas` épocas y devuelve el
+// resumen de cada una. Después de cada época calcula la pérdida y la métrica de validación
+// y llama a los callbacks en orden.
+func Entrenar(modelo Iterativo, train *Dataset, config Entrenamiento) ([]ResumenEpoca, error) {
+	if err := CheckTrain(train); err != nil {
+		return nil, err
+	}
+	if config.Epocas <= 0 {
+		return nil, errors.New("training needs at least one epoch")
+	}
+	inicio := 0
+	if config.Reanudar != "" {
+		punto := &puntoDeControl{}
+		err := ReadJSON(config.Reanudar, punto)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(punto.Modelo, modelo); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %v", config.Reanudar, err)
+			}
+			inicio = punto.Epoca
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+	if err := modelo.Preparar(train, inicio > 0); err != nil {
+		return nil, err
+	}
+
+	var historial []ResumenEpoca
+	for epoca := inicio; epoca < config.Epocas; epoca++ {
+		comienzo := time.Now()
+		perdida, err := modelo.Epoca(train, epoca)
+		if err != nil {
+			return historial, err
+		}
+		resumen := ResumenEpoca{Epoca: epoca + 1, Perdida: perdida, PerdidaValidacion: math.NaN(), Metrica: math.NaN()}
+		if config.Validacion != nil && config.Validacion.Len() > 0 {
+			resumen.PerdidaValidacion = modelo.Loss(config.Validacion)
+			if config.Metrica != nil {
+				resumen.Metrica = config.Metrica(modelo, config.Validacion)
+			}
+		}
+		resumen.Duracion = time.Since(comienzo)
+		historial = append(historial, resumen)
+
+		detener := false
+		for _, callback := range config.Callbacks {
+			parar, err := callback.FinEpoca(modelo, resumen)
+			if err != nil {
+				return historial, err
+			}
+			detener = detener || parar
+		}
+		if detener {
+			break
+		}
+	}
+
+	for _, callback := range config.Callbacks {
+		if finalizador, ok := callback.(Finalizador); ok {
+			if err := finalizador.FinEntrenamiento(modelo); err != nil {
+				return historial, err
+			}
+		}
+	}
+	return historial, nil
+}
+
+// `RandEpoca` devuelve el generador de la época `epoca` de un entrenamiento con semilla
+// `semilla`. Cada época tiene su propio generador, así que un entrenamiento retomado desde
+// un punto de control recorre los ejemplos en el mismo orden que el original.
+func RandEpoca(semilla int64, epoca int) *rand.Rand {
+	return rand.New(rand.NewSource(semilla*1000003 + int64(epoca) + 1))
+}
+
+// `FijarSemilla` devuelve `semilla` o, si es 0, una derivada de la hora actual. Los modelos
+// iterativos la guardan en su configuración para que los puntos de control la conserven.
+func FijarSemilla(semilla int64) int64 {
+	if semilla == 0 {
+		return time.Now().UnixNano()
+	}
+	return semilla
+}
+
+// Estructura `Registro`: callback que imprime el resumen de cada época.
+type Registro struct {
+	Salida  io.Writer // os.Stdout si es nil
+	Metrica string    // Nombre de la métrica de validación, por ejemplo "precisión"
+	Cada    int       // Imprime cada `Cada` épocas; 1 si es 0
+}
+
+// `FinEpoca` imprime la época, las pérdidas y la métrica.
+func (r *Registro) FinEpoca(modelo Iterativo, resumen ResumenEpoca) (bool, error) {
+	if r.Cada > 1 && resumen.Epoca%r.Cada != 0 {
+		return false, nil
+	}
+	salida := r.Salida
+	if salida == nil {
+		salida = os.Stdout
+	}
+	linea := fmt.Sprintf("Época %d: pérdida %.5f", resumen.Epoca, resumen.Perdida)
+	if !math.IsNaN(resumen.PerdidaValidacion) {
+		linea += fmt.Sprintf(", validación %.5f", resumen.PerdidaValidacion)
+	}
+	if !math.IsNaN(resumen.Metrica) {
+		nombre := r.Metrica
+		if nombre == "" {
+			nombre = "métrica"
+		}
+		linea += fmt.Sprintf(", %s %.4f", nombre, resumen.Metrica)
+	}
+	_, err := fmt.Fprintf(salida, "%s (%s)\n", linea, resumen.Duracion.Round(time.Millisecond))
+	return false, err
+}
+
+// Estructura `ParadaTemprana`: callback que detiene el entrenamiento cuando la pérdida de
+// validación no mejora en `Paciencia` épocas seguidas. Con `Restaurar`, al terminar el
+// modelo vuelve a los parámetros de la mejor época (guardados en memoria como JSON).
+type ParadaTemprana struct {
+	Paciencia int
+	Restaurar bool
+
+	MejorEpoca   int     // Época con la menor pérdida de validación
+	MejorPerdida float64 // Esa pérdida
+	copia        []byte
+}
+
+// `FinEpoca` registra la mejor época y decide si hay que detenerse.
+func (p *ParadaTemprana) FinEpoca(modelo Iterativo, resumen ResumenEpoca) (bool, error) {
+	if math.IsNaN(resumen.PerdidaValidacion) {
+		return false, errors.New("early stopping needs validation data")
+	}
+	if p.MejorEpoca == 0 || resumen.PerdidaValidacion < p.MejorPerdida {
+		p.MejorEpoca = resumen.Epoca
+		p.MejorPerdida = resumen.PerdidaValidacion
+		if p.Restaurar {
+			copia, err := json.Marshal(modelo)
+			if err != nil {
+				return false, err
+			}
+			p.copia = copia
+		}
+		return false, nil
+	}
+	return resumen.Epoca-p.MejorEpoca >= p.Paciencia, nil
+}
+
+// `FinEntrenamiento` restaura el modelo de la mejor época si `Restaurar` es true.
+func (p *ParadaTemprana) FinEntrenamiento(modelo Iterativo) error {
+	if !p.Restaurar || p.copia == nil {
+		return nil
+	}
+	return json.Unmarshal(p.copia, modelo)
+}
+
+// Estructura `PuntoDeControl`: callback que guarda el modelo en `Archivo` cada `Cada` épocas
+// y al terminar el entrenamiento, para retomarlo con `Entrenamiento.Reanudar`. El archivo
+// se escribe primero en uno temporal y luego se renombra, de modo que un corte a mitad de
+// la escritura no deja un punto de control dañado.
+type PuntoDeControl struct {
+	Archivo string
+	Cada    int // 1 si es 0
+
+	ultima   int // Última época terminada
+	guardada int // Última época guardada
+}
+
+// `FinEpoca` guarda el punto de control si corresponde a esta época.
+func (p *PuntoDeControl) FinEpoca(modelo Iterativo, resumen ResumenEpoca) (bool, error) {
+	p.ultima = resumen.Epoca
+	cada := p.Cada
+	if cada <= 0 {
+		cada = 1
+	}
+	if resumen.Epoca%cada != 0 {
+		return false, nil
+	}
+	return false, p.guardar(modelo)
+}
+
+// `FinEntrenamiento` guarda la última época si todavía no se guardó.
+func (p *PuntoDeControl) FinEntrenamiento(modelo Iterativo) error {
+	if p.ultima == 0 || p.ultima == p.guardada {
+		return nil
+	}
+	return p.guardar(modelo)
+}
+
+// Escribe el modelo y la última época terminada en `Archivo`.
+func (p *PuntoDeControl) guardar(modelo Iterativo) error {
+	datos, err := json.Marshal(modelo)
+	if err != nil {
+		return err
+	}
+	temporal := p.Archivo + ".tmp"
+	if err := WriteJSON(temporal, puntoDeControl{Epoca: p.ultima, Modelo: datos}); err != nil {
+		return err
+	}
+	if err := os.Rename(temporal, p.Archivo); err != nil {
+		return err
+	}
+	p.guardada = p.ultima
+	return nil
+}
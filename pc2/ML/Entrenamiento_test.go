@@ -0,0 +1,114 @@
+package ML
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Regresión lineal de una variable con SGD, como modelo iterativo mínimo.
+type regresion struct {
+	W, B    float64
+	Tasa    float64
+	Semilla int64
+}
+
+func (r *regresion) Fit(train *Dataset) error { return nil }
+
+func (r *regresion) Predict(x []float64) float64 { return r.W*x[0] + r.B }
+
+func (r *regresion) Preparar(train *Dataset, reanudar bool) error {
+	if !reanudar {
+		r.W, r.B = 0, 0
+	}
+	return nil
+}
+
+func (r *regresion) Epoca(train *Dataset, epoca int) (float64, error) {
+	for _, i := range RandEpoca(r.Semilla, epoca).Perm(train.Len()) {
+		e := r.Predict(train.X[i]) - train.Y[i]
+		r.W -= r.Tasa * e * train.X[i][0]
+		r.B -= r.Tasa * e
+	}
+	return r.Loss(train), nil
+}
+
+func (r *regresion) Loss(d *Dataset) float64 {
+	total := 0.0
+	for i, x := range d.X {
+		e := r.Predict(x) - d.Y[i]
+		total += e * e
+	}
+	return total / float64(d.Len())
+}
+
+// Datos y = 2x + 1 con ruido.
+func datosRegresion(n int, semilla int64) *Dataset {
+	rng := NewRand(semilla)
+	d := &Dataset{X: NewMatrix(n, 1), Y: make([]float64, n)}
+	for i := range d.X {
+		d.X[i][0] = rng.Float64()*2 - 1
+		d.Y[i] = 2*d.X[i][0] + 1 + rng.NormFloat64()*0.1
+	}
+	return d
+}
+
+// Un entrenamiento cortado y retomado desde el punto de control termina igual que uno sin cortes.
+func TestPuntoDeControl(t *testing.T) {
+	train := datosRegresion(200, 1)
+	archivo := filepath.Join(t.TempDir(), "modelo.json")
+
+	completo := &regresion{Tasa: 0.05, Semilla: 7}
+	if _, err := Entrenar(completo, train, Entrenamiento{Epocas: 6}); err != nil {
+		t.Fatal(err)
+	}
+
+	cortado := &regresion{Tasa: 0.05, Semilla: 7}
+	punto := &PuntoDeControl{Archivo: archivo, Cada: 3}
+	if _, err := Entrenar(cortado, train, Entrenamiento{Epocas: 4, Callbacks: []Callback{punto}}); err != nil {
+		t.Fatal(err)
+	}
+	retomado := &regresion{}
+	historial, err := Entrenar(retomado, train, Entrenamiento{Epocas: 6, Reanudar: archivo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// El último punto de control es el de la época 4 (la última del primer entrenamiento).
+	if len(historial) != 2 || historial[0].Epoca != 5 {
+		t.Fatalf("resumed epochs %+v, expected 5 and 6", historial)
+	}
+	if retomado.W != completo.W || retomado.B != completo.B {
+		t.Fatalf("resumed model %+v, uninterrupted %+v", retomado, completo)
+	}
+}
+
+// La parada temprana se detiene tras `Paciencia` épocas sin mejorar y restaura la mejor época.
+func TestParadaTemprana(t *testing.T) {
+	train := datosRegresion(200, 2)
+	// Con validación de otra recta la pérdida empeora a medida que el modelo aprende.
+	validacion := datosRegresion(50, 3)
+	for i := range validacion.Y {
+		validacion.Y[i] = 0
+	}
+	modelo := &regresion{Tasa: 0.01, Semilla: 2}
+	parada := &ParadaTemprana{Paciencia: 2, Restaurar: true}
+	var salida bytes.Buffer
+	historial, err := Entrenar(modelo, train, Entrenamiento{
+		Epocas:     20,
+		Validacion: validacion,
+		Callbacks:  []Callback{&Registro{Salida: &salida}, parada},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parada.MejorEpoca != 1 || len(historial) != 3 {
+		t.Fatalf("best epoch %d after %d epochs, expected 1 after 3", parada.MejorEpoca, len(historial))
+	}
+	if perdida := modelo.Loss(validacion); perdida != parada.MejorPerdida {
+		t.Fatalf("restored validation loss %v, expected %v", perdida, parada.MejorPerdida)
+	}
+	if lineas := strings.Count(salida.String(), "\n"); lineas != 3 || !strings.Contains(salida.String(), "validación") {
+		t.Fatalf("log:\n%s", salida.String())
+	}
+}
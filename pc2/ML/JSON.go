@@ -0,0 +1,33 @@
+package ML
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// `ReadJSON` carga en `value` el contenido del archivo JSON `fileName`.
+func ReadJSON(fileName string, value interface{}) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := json.NewDecoder(file).Decode(value); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", fileName, err)
+	}
+	return nil
+}
+
+// `WriteJSON` guarda `value` en el archivo JSON `fileName`, reemplazando su contenido.
+func WriteJSON(fileName string, value interface{}) error {
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(file).Encode(value); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode %s: %v", fileName, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,120 @@
+package ML
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Primer índice de las características en los archivos de texto dispersos.
+const LIBSVM = 1 // libsvm y svmlight numeran las características desde 1
+const LIBFM = 0  // libFM las numera desde 0
+
+// `LoadLibSVM` lee el archivo `filename` en formato libsvm/svmlight/libFM (ver `ReadLibSVM`).
+func LoadLibSVM(filename string, base int) (*DatasetDisperso, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dataset, err := ReadLibSVM(file, base)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return dataset, nil
+}
+
+// `ReadLibSVM` lee un ejemplo por línea con el formato "etiqueta índice:valor índice:valor
+// ...", con los índices en orden creciente a partir de `base` (`LIBSVM` o `LIBFM`). Como en
+// svmlight, se ignoran las líneas vacías, los comentarios desde "#" y el campo "qid:". La
+// dimensión del dataset es 1 + el mayor índice leído.
+func ReadLibSVM(r io.Reader, base int) (*DatasetDisperso, error) {
+	dataset := &DatasetDisperso{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for linea := 1; scanner.Scan(); linea++ {
+		texto := scanner.Text()
+		if comentario := strings.IndexByte(texto, '#'); comentario >= 0 {
+			texto = texto[:comentario]
+		}
+		campos := strings.Fields(texto)
+		if len(campos) == 0 {
+			continue
+		}
+		etiqueta, err := strconv.ParseFloat(campos[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid label %q", linea, campos[0])
+		}
+
+		var x Disperso
+		for _, campo := range campos[1:] {
+			dosPuntos := strings.IndexByte(campo, ':')
+			if dosPuntos < 0 {
+				return nil, fmt.Errorf("line %d: expected index:value, got %q", linea, campo)
+			}
+			if campo[:dosPuntos] == "qid" {
+				continue
+			}
+			indice, err := strconv.Atoi(campo[:dosPuntos])
+			if err != nil || indice < base {
+				return nil, fmt.Errorf("line %d: invalid index %q", linea, campo[:dosPuntos])
+			}
+			indice -= base
+			if x.Len() > 0 && indice <= x.Indices[x.Len()-1] {
+				return nil, fmt.Errorf("line %d: indices are not increasing at %q", linea, campo)
+			}
+			valor, err := strconv.ParseFloat(campo[dosPuntos+1:], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid value in %q", linea, campo)
+			}
+			if valor == 0 {
+				continue
+			}
+			x.Indices = append(x.Indices, indice)
+			x.Valores = append(x.Valores, valor)
+			if indice >= dataset.Dimension {
+				dataset.Dimension = indice + 1
+			}
+		}
+		dataset.X = append(dataset.X, x)
+		dataset.Y = append(dataset.Y, etiqueta)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
+
+// `SaveLibSVM` guarda `d` en el archivo `filename` en formato libsvm (ver `WriteLibSVM`).
+func SaveLibSVM(filename string, d *DatasetDisperso, base int) error {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := WriteLibSVM(file, d, base); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// `WriteLibSVM` escribe un ejemplo por línea con las posiciones distintas de cero numeradas
+// desde `base`. Los números se escriben con la menor cantidad de dígitos que los representa
+// exactamente, así que leer el archivo con `ReadLibSVM` devuelve los mismos valores.
+func WriteLibSVM(w io.Writer, d *DatasetDisperso, base int) error {
+	salida := bufio.NewWriter(w)
+	for i, x := range d.X {
+		salida.WriteString(strconv.FormatFloat(d.Y[i], 'g', -1, 64))
+		for n, j := range x.Indices {
+			salida.WriteByte(' ')
+			salida.WriteString(strconv.Itoa(j + base))
+			salida.WriteByte(':')
+			salida.WriteString(strconv.FormatFloat(x.Valores[n], 'g', -1, 64))
+		}
+		salida.WriteByte('\n')
+	}
+	return salida.Flush()
+}
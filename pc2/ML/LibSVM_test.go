@@ -0,0 +1,87 @@
+package ML
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// `ReadLibSVM` acepta el formato de svmlight: comentarios, líneas vacías, "qid:" y ceros.
+func TestReadLibSVM(t *testing.T) {
+	contenido := "# ejemplo de svmlight\n" +
+		"+1 qid:3 1:0.5 4:-2 # comentario\n" +
+		"\n" +
+		"-1 2:1e-3 3:0 7:4\n" +
+		"0\n"
+	d, err := ReadLibSVM(strings.NewReader(contenido), LIBSVM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Len() != 3 || d.Dimension != 7 {
+		t.Fatalf("dataset has %d rows and dimension %d, expected 3 and 7", d.Len(), d.Dimension)
+	}
+	if d.Y[0] != 1 || d.Y[1] != -1 || d.Y[2] != 0 {
+		t.Fatalf("labels %v", d.Y)
+	}
+	if got := d.X[0]; len(got.Indices) != 2 || got.Indices[0] != 0 || got.Indices[1] != 3 || got.Valores[1] != -2 {
+		t.Fatalf("row 0 is %+v", got)
+	}
+	if got := d.X[1]; len(got.Indices) != 2 || got.Indices[1] != 6 {
+		t.Fatalf("row 1 is %+v (zeros should be dropped)", got)
+	}
+	if d.X[2].Len() != 0 {
+		t.Fatalf("row 2 is %+v, expected empty", d.X[2])
+	}
+
+	for _, invalido := range []string{"1 3:1 2:1\n", "1 0:1\n", "x 1:1\n", "1 1:y\n", "1 11\n"} {
+		if _, err := ReadLibSVM(strings.NewReader(invalido), LIBSVM); err == nil {
+			t.Errorf("%q: expected an error", invalido)
+		}
+	}
+}
+
+// Escribir y volver a leer (libsvm y libFM) devuelve el mismo dataset, y la conversión
+// entre `Dataset` y `DatasetDisperso` conserva los valores.
+func TestLibSVMIdaYVuelta(t *testing.T) {
+	denso := RandomRegression(50, 6, 1, 10, 3)
+	for i := range denso.X {
+		denso.X[i][i%6] = 0
+	}
+	disperso := denso.Disperso()
+	for _, base := range []int{LIBSVM, LIBFM} {
+		archivo := filepath.Join(t.TempDir(), "datos.txt")
+		if err := SaveLibSVM(archivo, disperso, base); err != nil {
+			t.Fatal(err)
+		}
+		leido, err := LoadLibSVM(archivo, base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var a, b bytes.Buffer
+		WriteLibSVM(&a, disperso, LIBFM)
+		WriteLibSVM(&b, leido, LIBFM)
+		if a.String() != b.String() {
+			t.Fatalf("base %d: round trip changed the data", base)
+		}
+	}
+
+	vuelta := disperso.Denso()
+	for i := range denso.X {
+		for j, v := range denso.X[i] {
+			if vuelta.X[i][j] != v {
+				t.Fatalf("X[%d][%d] = %v, expected %v", i, j, vuelta.X[i][j], v)
+			}
+		}
+		if disperso.X[i].Len() != 5 {
+			t.Fatalf("row %d has %d non-zeros, expected 5", i, disperso.X[i].Len())
+		}
+		producto := 0.0
+		for _, v := range denso.X[i] {
+			producto += v
+		}
+		if got := disperso.X[i].Producto([]float64{1, 1, 1, 1, 1, 1}); got != producto {
+			t.Fatalf("Producto = %v, expected %v", got, producto)
+		}
+	}
+}
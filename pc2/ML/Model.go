@@ -0,0 +1,71 @@
+package ML
+
+import (
+	"errors"
+	"math"
+)
+
+// Errores de `CheckTrain`.
+var errEmpty = errors.New("training dataset is empty")
+var errNoFeatures = errors.New("training dataset has no features")
+
+// Interfaz `Model` que implementan los algoritmos: `Fit` entrena con un dataset y
+// `Predict` devuelve la clase (0, 1, ...) o el valor predicho para un ejemplo.
+type Model interface {
+	Fit(train *Dataset) error
+	Predict(x []float64) float64
+}
+
+// `Accuracy` devuelve la fracción de ejemplos de `test` cuya clase predicha es la etiqueta
+// (lo que hacía `evaluarPrecision` en los clasificadores).
+func Accuracy(model Model, test *Dataset) float64 {
+	if test.Len() == 0 {
+		return 0
+	}
+	correctos := 0
+	for i, x := range test.X {
+		if model.Predict(x) == test.Y[i] {
+			correctos++
+		}
+	}
+	return float64(correctos) / float64(test.Len())
+}
+
+// `WithinTolerance` devuelve la fracción de ejemplos cuya predicción está a menos de
+// `tolerance` del valor objetivo (la precisión usada para los modelos de regresión).
+func WithinTolerance(model Model, test *Dataset, tolerance float64) float64 {
+	if test.Len() == 0 {
+		return 0
+	}
+	correctos := 0
+	for i, x := range test.X {
+		if math.Abs(model.Predict(x)-test.Y[i]) < tolerance {
+			correctos++
+		}
+	}
+	return float64(correctos) / float64(test.Len())
+}
+
+// `RMSE` devuelve la raíz del error cuadrático medio de las predicciones sobre `test`.
+func RMSE(model Model, test *Dataset) float64 {
+	if test.Len() == 0 {
+		return 0
+	}
+	total := 0.0
+	for i, x := range test.X {
+		diferencia := model.Predict(x) - test.Y[i]
+		total += diferencia * diferencia
+	}
+	return math.Sqrt(total / float64(test.Len()))
+}
+
+// `CheckTrain` valida el dataset de entrenamiento que recibe `Fit`.
+func CheckTrain(train *Dataset) error {
+	if train == nil || train.Len() == 0 {
+		return errEmpty
+	}
+	if train.Features() == 0 {
+		return errNoFeatures
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package ML
+
+// `FuncionGradiente` suma en `g` (con la forma de los parámetros del modelo) el gradiente de
+// la pérdida de los ejemplos `indices` y devuelve la suma de sus pérdidas. `worker` identifica
+// a la goroutine que la llama, para que cada una use sus propios vectores auxiliares; la
+// función solo debe leer los parámetros.
+type FuncionGradiente func(worker int, indices []int, g [][]float64) float64
+
+// `FuncionGradienteDisperso` es como `FuncionGradiente`, pero además anota en `tocados` cada
+// posición de `g` en la que suma algo, para que solo esas posiciones se pongan en cero y se
+// reduzcan (ver `NuevoGradienteDisperso`).
+type FuncionGradienteDisperso func(worker int, indices []int, g [][]float64, tocados *Tocados) float64
+
+// Posición `J` del vector `K` de los parámetros.
+type Posicion struct {
+	K, J int
+}
+
+// Estructura `Tocados` con las posiciones de un búfer de gradiente en las que se sumó algo,
+// sin repetir, en el orden en que se tocaron por primera vez.
+type Tocados struct {
+	Posiciones []Posicion
+	marcas     [][]bool
+}
+
+// `Tocar` anota la posición (`k`, `j`) si todavía no estaba anotada.
+func (t *Tocados) Tocar(k, j int) {
+	if !t.marcas[k][j] {
+		t.marcas[k][j] = true
+		t.Posiciones = append(t.Posiciones, Posicion{k, j})
+	}
+}
+
+// Pone en cero las posiciones tocadas de `g` y vacía la lista.
+func (t *Tocados) limpiar(g [][]float64) {
+	for _, q := range t.Posiciones {
+		g[q.K][q.J] = 0
+		t.marcas[q.K][q.J] = false
+	}
+	t.Posiciones = t.Posiciones[:0]
+}
+
+// Gradiente parcial que un worker entrega a su padre en la reducción.
+type parcial struct {
+	g       [][]float64
+	tocados *Tocados // Posiciones no nulas de `g`; nil si el gradiente es denso
+	perdida float64
+}
+
+// Estructura `GradienteParalelo` para el entrenamiento síncrono con paralelismo de datos:
+// cada mini-lote se reparte en tramos consecutivos, uno por worker, y cada worker acumula
+// el gradiente de su tramo en un búfer propio. Los búferes se suman con una reducción en
+// árbol por canales (en el paso s, el worker w recibe el de w+s si w es múltiplo de 2s), de
+// modo que el gradiente total queda en el búfer del worker 0 tras log2(workers) pasos. El
+// orden de las sumas es siempre el mismo, así que el resultado no depende de qué goroutine
+// termina antes, y los workers nunca escriben memoria compartida.
+//
+// Creado con `NuevoGradienteDisperso`, cada worker anota las posiciones que toca y tanto la
+// puesta en cero como la reducción recorren solo esas posiciones, así que un lote cuesta
+// O(posiciones tocadas) en lugar de O(parámetros).
+type GradienteParalelo struct {
+	workers   int
+	gradiente FuncionGradiente
+	disperso  FuncionGradienteDisperso
+	buferes   [][][]float64
+	tocados   []*Tocados // Uno por worker; nil si el gradiente es denso
+	tramos    []chan []int
+	subida    []chan parcial
+	resultado chan parcial
+}
+
+// `NuevoGradienteParalelo` crea los búferes (con la forma de `parametros`) y lanza las
+// goroutines de `workers` workers; con 1 o menos el gradiente se calcula sin goroutines.
+// Hay que llamar a `Cerrar` al terminar el entrenamiento.
+func NuevoGradienteParalelo(workers int, parametros [][]float64, gradiente FuncionGradiente) *GradienteParalelo {
+	p := nuevoGradiente(workers, parametros)
+	p.gradiente = gradiente
+	p.lanzar()
+	return p
+}
+
+// `NuevoGradienteDisperso` es como `NuevoGradienteParalelo` para un gradiente que anota las
+// posiciones que toca; el resultado se obtiene con `CalcularDisperso`.
+func NuevoGradienteDisperso(workers int, parametros [][]float64, gradiente FuncionGradienteDisperso) *GradienteParalelo {
+	p := nuevoGradiente(workers, parametros)
+	p.disperso = gradiente
+	p.tocados = make([]*Tocados, p.workers)
+	for w := range p.tocados {
+		p.tocados[w] = &Tocados{marcas: make([][]bool, len(parametros))}
+		for k, v := range parametros {
+			p.tocados[w].marcas[k] = make([]bool, len(v))
+		}
+	}
+	p.lanzar()
+	return p
+}
+
+// Crea los búferes de `workers` workers con la forma de `parametros`.
+func nuevoGradiente(workers int, parametros [][]float64) *GradienteParalelo {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &GradienteParalelo{workers: workers, buferes: make([][][]float64, workers)}
+	for w := range p.buferes {
+		p.buferes[w] = make([][]float64, len(parametros))
+		for k, v := range parametros {
+			p.buferes[w][k] = make([]float64, len(v))
+		}
+	}
+	return p
+}
+
+// Lanza las goroutines de los workers, salvo que haya uno solo.
+func (p *GradienteParalelo) lanzar() {
+	if p.workers == 1 {
+		return
+	}
+	p.tramos = make([]chan []int, p.workers)
+	p.subida = make([]chan parcial, p.workers)
+	p.resultado = make(chan parcial)
+	for w := range p.tramos {
+		p.tramos[w] = make(chan []int)
+		p.subida[w] = make(chan parcial)
+	}
+	for w := range p.tramos {
+		go p.trabajar(w)
+	}
+}
+
+// Bucle de un worker: calcula el gradiente de cada tramo que recibe y participa de la
+// reducción, recibiendo los gradientes de sus hijos y entregando la suma a su padre (el
+// worker 0 la entrega a `Calcular`). El búfer entregado no se vuelve a escribir hasta el
+// lote siguiente, que solo se reparte cuando la reducción terminó.
+func (p *GradienteParalelo) trabajar(w int) {
+	for tramo := range p.tramos[w] {
+		suma := p.calcular(w, tramo)
+		enviado := false
+		for paso := 1; paso < p.workers; paso *= 2 {
+			if w%(2*paso) != 0 {
+				p.subida[w] <- suma
+				enviado = true
+				break
+			}
+			if w+paso < p.workers {
+				suma.sumar(<-p.subida[w+paso])
+			}
+		}
+		if !enviado {
+			p.resultado <- suma
+		}
+	}
+}
+
+// Pone en cero el búfer del worker `w` y suma en él el gradiente de `tramo`.
+func (p *GradienteParalelo) calcular(w int, tramo []int) parcial {
+	g := p.buferes[w]
+	if p.tocados == nil {
+		cero(g)
+		return parcial{g: g, perdida: p.gradiente(w, tramo, g)}
+	}
+	tocados := p.tocados[w]
+	tocados.limpiar(g)
+	return parcial{g: g, tocados: tocados, perdida: p.disperso(w, tramo, g, tocados)}
+}
+
+// Suma a `suma` el gradiente parcial `hijo`; si es disperso, solo sus posiciones tocadas.
+func (suma *parcial) sumar(hijo parcial) {
+	suma.perdida += hijo.perdida
+	if hijo.tocados == nil {
+		for k := range suma.g {
+			for j, v := range hijo.g[k] {
+				suma.g[k][j] += v
+			}
+		}
+		return
+	}
+	for _, q := range hijo.tocados.Posiciones {
+		suma.g[q.K][q.J] += hijo.g[q.K][q.J]
+		suma.tocados.Tocar(q.K, q.J)
+	}
+}
+
+// Reparte `indices` entre los workers y devuelve la suma de sus gradientes parciales.
+func (p *GradienteParalelo) lote(indices []int) parcial {
+	if p.workers == 1 {
+		return p.calcular(0, indices)
+	}
+	for w, tramo := range Repartir(indices, p.workers) {
+		p.tramos[w] <- tramo
+	}
+	return <-p.resultado
+}
+
+// `Calcular` devuelve la suma de los gradientes de los ejemplos `indices` (con la forma de
+// los parámetros) y la suma de sus pérdidas. El gradiente es un búfer interno que se
+// sobrescribe en la llamada siguiente.
+func (p *GradienteParalelo) Calcular(indices []int) ([][]float64, float64) {
+	suma := p.lote(indices)
+	return suma.g, suma.perdida
+}
+
+// `CalcularDisperso` es como `Calcular` para un gradiente creado con `NuevoGradienteDisperso`:
+// además devuelve las posiciones tocadas, fuera de las cuales el gradiente es cero. La lista
+// también es interna y se sobrescribe en la llamada siguiente.
+func (p *GradienteParalelo) CalcularDisperso(indices []int) ([][]float64, []Posicion, float64) {
+	suma := p.lote(indices)
+	return suma.g, suma.tocados.Posiciones, suma.perdida
+}
+
+// `Cerrar` detiene las goroutines de los workers.
+func (p *GradienteParalelo) Cerrar() {
+	for _, tramo := range p.tramos {
+		close(tramo)
+	}
+	p.tramos = nil
+}
+
+// Pone en cero los búferes `g`.
+func cero(g [][]float64) {
+	for _, v := range g {
+		for j := range v {
+			v[j] = 0
+		}
+	}
+}
+
+// `Repartir` divide `indices` en `partes` tramos consecutivos de tamaños parecidos; si hay
+// menos índices que partes, algunos tramos quedan vacíos.
+func Repartir(indices []int, partes int) [][]int {
+	tramos := make([][]int, partes)
+	for w := range tramos {
+		tramos[w] = indices[w*len(indices)/partes : (w+1)*len(indices)/partes]
+	}
+	return tramos
+}
+
+// `Lotes` divide `orden` en mini-lotes consecutivos de `tamaño` ejemplos (el último con los
+// restantes).
+func Lotes(orden []int, tamaño int) [][]int {
+	if tamaño <= 0 {
+		tamaño = len(orden)
+	}
+	var lotes [][]int
+	for inicio := 0; inicio < len(orden); inicio += tamaño {
+		fin := inicio + tamaño
+		if fin > len(orden) {
+			fin = len(orden)
+		}
+		lotes = append(lotes, orden[inicio:fin])
+	}
+	return lotes
+}
+
+// `PasoGradiente` aplica un paso de descenso de gradiente: parámetro -= `tasa`·gradiente.
+func PasoGradiente(parametros, g [][]float64, tasa float64) {
+	for k, v := range parametros {
+		for j := range v {
+			v[j] -= tasa * g[k][j]
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ML
+
+import (
+	"math"
+	"testing"
+)
+
+// La reducción en árbol suma los gradientes de todos los tramos, con cualquier cantidad de
+// workers (potencia de dos o no) y con lotes más chicos que la cantidad de workers.
+func TestGradienteParalelo(t *testing.T) {
+	d := RandomRegression(100, 3, 1, 1, 1)
+	parametros := [][]float64{make([]float64, 3), make([]float64, 1)}
+	gradiente := func(w int, indices []int, g [][]float64) float64 {
+		perdida := 0.0
+		for _, i := range indices {
+			for j, v := range d.X[i] {
+				g[0][j] += v * d.Y[i]
+			}
+			g[1][0] += d.Y[i]
+			perdida += d.Y[i] * d.Y[i]
+		}
+		return perdida
+	}
+
+	for _, tamaño := range []int{100, 5, 1} {
+		indices := make([]int, tamaño)
+		for i := range indices {
+			indices[i] = i * (d.Len() / tamaño)
+		}
+		secuencial := NuevoGradienteParalelo(1, parametros, gradiente)
+		esperado, perdidaEsperada := secuencial.Calcular(indices)
+		for workers := 2; workers <= 7; workers++ {
+			paralelo := NuevoGradienteParalelo(workers, parametros, gradiente)
+			for repeticion := 0; repeticion < 3; repeticion++ {
+				g, perdida := paralelo.Calcular(indices)
+				if math.Abs(perdida-perdidaEsperada) > 1e-9 {
+					t.Fatalf("%d workers, batch %d: loss %v, expected %v", workers, tamaño, perdida, perdidaEsperada)
+				}
+				for k := range g {
+					for j := range g[k] {
+						if math.Abs(g[k][j]-esperado[k][j]) > 1e-9 {
+							t.Fatalf("%d workers, batch %d: gradient %v, expected %v", workers, tamaño, g, esperado)
+						}
+					}
+				}
+			}
+			paralelo.Cerrar()
+		}
+	}
+}
+
+// El gradiente disperso coincide con el denso, devuelve exactamente las posiciones tocadas y
+// pone en cero las del lote anterior aunque el lote siguiente no las toque.
+func TestGradienteDisperso(t *testing.T) {
+	// El ejemplo i solo usa las características i%7 e (i*3)%11, de 20.
+	parametros := [][]float64{make([]float64, 20), make([]float64, 1)}
+	columnas := func(i int) []int { return []int{i % 7, (i * 3) % 11} }
+	denso := func(w int, indices []int, g [][]float64) float64 {
+		for _, i := range indices {
+			for _, j := range columnas(i) {
+				g[0][j] += float64(i + 1)
+			}
+			g[1][0] += 1
+		}
+		return float64(len(indices))
+	}
+	disperso := func(w int, indices []int, g [][]float64, tocados *Tocados) float64 {
+		for _, i := range indices {
+			for _, j := range columnas(i) {
+				g[0][j] += float64(i + 1)
+				tocados.Tocar(0, j)
+			}
+			g[1][0] += 1
+			tocados.Tocar(1, 0)
+		}
+		return float64(len(indices))
+	}
+
+	lotes := [][]int{{0, 1, 2, 3, 4, 5, 6, 7, 8}, {7, 14}, {3}}
+	secuencial := NuevoGradienteParalelo(1, parametros, denso)
+	for workers := 1; workers <= 5; workers++ {
+		paralelo := NuevoGradienteDisperso(workers, parametros, disperso)
+		for _, lote := range lotes {
+			esperado, perdidaEsperada := secuencial.Calcular(lote)
+			g, tocados, perdida := paralelo.CalcularDisperso(lote)
+			if perdida != perdidaEsperada {
+				t.Fatalf("%d workers, batch %v: loss %v, expected %v", workers, lote, perdida, perdidaEsperada)
+			}
+			marcadas := make(map[Posicion]bool)
+			for _, q := range tocados {
+				if marcadas[q] {
+					t.Fatalf("%d workers, batch %v: position %v listed twice", workers, lote, q)
+				}
+				marcadas[q] = true
+			}
+			for k := range g {
+				for j := range g[k] {
+					if math.Abs(g[k][j]-esperado[k][j]) > 1e-9 {
+						t.Fatalf("%d workers, batch %v: gradient %v, expected %v", workers, lote, g, esperado)
+					}
+					if marcadas[Posicion{k, j}] != (esperado[k][j] != 0) {
+						t.Fatalf("%d workers, batch %v: touched %v, gradient %v", workers, lote, tocados, esperado)
+					}
+				}
+			}
+		}
+		paralelo.Cerrar()
+	}
+}
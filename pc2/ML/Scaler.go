@@ -0,0 +1,57 @@
+package ML
+
+import (
+	"math"
+)
+
+// Estructura `Scaler` que estandariza cada característica (media 0 y desviación 1) con las
+// estadísticas del conjunto de entrenamiento. Los modelos lineales la necesitan cuando las
+// columnas tienen escalas muy distintas, como los montos y las edades del SIS.
+type Scaler struct {
+	Mean []float64
+	Std  []float64
+}
+
+// `FitScaler` calcula la media y la desviación de cada característica de `d`. Las columnas
+// constantes quedan con desviación 1 para no dividir por cero.
+func FitScaler(d *Dataset) *Scaler {
+	scaler := &Scaler{Mean: make([]float64, d.Features()), Std: make([]float64, d.Features())}
+	for _, x := range d.X {
+		for j, v := range x {
+			scaler.Mean[j] += v
+		}
+	}
+	for j := range scaler.Mean {
+		scaler.Mean[j] /= float64(d.Len())
+	}
+	for _, x := range d.X {
+		for j, v := range x {
+			scaler.Std[j] += (v - scaler.Mean[j]) * (v - scaler.Mean[j])
+		}
+	}
+	for j := range scaler.Std {
+		scaler.Std[j] = math.Sqrt(scaler.Std[j] / float64(d.Len()))
+		if scaler.Std[j] == 0 {
+			scaler.Std[j] = 1
+		}
+	}
+	return scaler
+}
+
+// `TransformRow` devuelve una copia estandarizada de `x`.
+func (s *Scaler) TransformRow(x []float64) []float64 {
+	fila := make([]float64, len(x))
+	for j, v := range x {
+		fila[j] = (v - s.Mean[j]) / s.Std[j]
+	}
+	return fila
+}
+
+// `Transform` devuelve un dataset con las filas estandarizadas y las mismas etiquetas.
+func (s *Scaler) Transform(d *Dataset) *Dataset {
+	transformado := &Dataset{X: make(Matrix, d.Len()), Y: d.Y}
+	for i, x := range d.X {
+		transformado.X[i] = s.TransformRow(x)
+	}
+	return transformado
+}
@@ -0,0 +1,186 @@
+// Package Optimizadores implementa los métodos de descenso de gradiente que comparten los
+// modelos entrenados por gradiente (`DL.MLP`, `SVM.SVM`, `MBFL.FactorizationMachine`): SGD
+// con momento o Nesterov, AdaGrad, RMSProp y Adam, con decaimiento de pesos y una tasa de
+// aprendizaje que sigue un `Programa` (constante, escalonado, exponencial, coseno o con
+// calentamiento).
+//
+// Los parámetros de un modelo se ven como una lista de vectores ([][]float64) y el gradiente
+// tiene la misma forma. Un `Optimizador` es solo la configuración; el estado (momentos y
+// cantidad de pasos) vive en un `Estado` creado con `NuevoEstado`, de modo que varios
+// entrenamientos en paralelo pueden usar la misma configuración. El decaimiento de pesos es
+// desacoplado: cada paso resta tasa·`DecaimientoPesos`·parámetro, aparte del gradiente
+// (con SGD sin momento equivale a una regularización L2).
+package Optimizadores
+
+import "math"
+
+// Interfaz `Optimizador`: `Actualizar` aplica a `estado.Parametros` un paso con
+// `gradientes`, usando y actualizando los momentos de `estado`.
+type Optimizador interface {
+	Actualizar(estado *Estado, gradientes [][]float64)
+}
+
+// Estructura `Estado` de un optimizador para un conjunto de parámetros. Los momentos se
+// crean en el primer paso; se exportan para poder guardar y retomar un entrenamiento.
+type Estado struct {
+	Parametros [][]float64 `json:"-"`
+	Momentos   [][]float64 `json:",omitempty"` // Velocidad (SGD), suma de cuadrados (AdaGrad) o primer momento (Adam)
+	Segundos   [][]float64 `json:",omitempty"` // Media de los cuadrados (RMSProp) o segundo momento (Adam)
+	Pasos      int
+
+	optimizador Optimizador
+}
+
+// `NuevoEstado` crea el estado de `optimizador` para `parametros`.
+func NuevoEstado(optimizador Optimizador, parametros [][]float64) *Estado {
+	e := &Estado{}
+	e.Asociar(optimizador, parametros)
+	return e
+}
+
+// `Asociar` vincula el estado con `optimizador` y `parametros`, que no se guardan en JSON;
+// sirve para seguir usando un estado cargado de un punto de control.
+func (e *Estado) Asociar(optimizador Optimizador, parametros [][]float64) {
+	e.optimizador = optimizador
+	e.Parametros = parametros
+}
+
+// `Paso` aplica un paso del optimizador con `gradientes` (con la forma de los parámetros).
+func (e *Estado) Paso(gradientes [][]float64) {
+	e.optimizador.Actualizar(e, gradientes)
+	e.Pasos++
+}
+
+// Crea vectores en cero con la forma de los parámetros si `v` todavía no existe.
+func (e *Estado) reservar(v *[][]float64) [][]float64 {
+	if *v == nil {
+		*v = make([][]float64, len(e.Parametros))
+		for k, p := range e.Parametros {
+			(*v)[k] = make([]float64, len(p))
+		}
+	}
+	return *v
+}
+
+// Tasa del paso actual según `programa`, o `defecto` si no hay programa.
+func tasa(programa Programa, defecto float64, pasos int) float64 {
+	if programa == nil {
+		return defecto
+	}
+	return programa.Tasa(pasos)
+}
+
+// Valor de `v`, o `defecto` si es 0.
+func valor(v, defecto float64) float64 {
+	if v == 0 {
+		return defecto
+	}
+	return v
+}
+
+// Estructura `SGD`: descenso de gradiente con momento (v = μv + g) y, opcionalmente,
+// momento de Nesterov (el paso usa g + μv).
+type SGD struct {
+	Tasa             Programa // 0.01 constante si es nil
+	Momento          float64  // μ; sin momento si es 0
+	Nesterov         bool
+	DecaimientoPesos float64
+}
+
+// `Actualizar` aplica un paso de SGD.
+func (o *SGD) Actualizar(e *Estado, gradientes [][]float64) {
+	eta := tasa(o.Tasa, 0.01, e.Pasos)
+	var velocidades [][]float64
+	if o.Momento != 0 {
+		velocidades = e.reservar(&e.Momentos)
+	}
+	for k, p := range e.Parametros {
+		for j, g := range gradientes[k] {
+			paso := g
+			if velocidades != nil {
+				v := o.Momento*velocidades[k][j] + g
+				velocidades[k][j] = v
+				paso = v
+				if o.Nesterov {
+					paso = g + o.Momento*v
+				}
+			}
+			p[j] -= eta * (paso + o.DecaimientoPesos*p[j])
+		}
+	}
+}
+
+// Estructura `AdaGrad`: cada parámetro usa la tasa dividida por la raíz de la suma de sus
+// gradientes al cuadrado, lo que favorece a las características poco frecuentes.
+type AdaGrad struct {
+	Tasa             Programa // 0.01 constante si es nil
+	Epsilon          float64  // 1e-8 si es 0
+	DecaimientoPesos float64
+}
+
+// `Actualizar` aplica un paso de AdaGrad.
+func (o *AdaGrad) Actualizar(e *Estado, gradientes [][]float64) {
+	eta := tasa(o.Tasa, 0.01, e.Pasos)
+	epsilon := valor(o.Epsilon, 1e-8)
+	suma := e.reservar(&e.Momentos)
+	for k, p := range e.Parametros {
+		for j, g := range gradientes[k] {
+			suma[k][j] += g * g
+			p[j] -= eta * (g/(math.Sqrt(suma[k][j])+epsilon) + o.DecaimientoPesos*p[j])
+		}
+	}
+}
+
+// Estructura `RMSProp`: como AdaGrad, pero con una media móvil exponencial de los cuadrados
+// en lugar de la suma, para que la tasa no se anule con el tiempo.
+type RMSProp struct {
+	Tasa             Programa // 0.001 constante si es nil
+	Rho              float64  // Peso de la media anterior; 0.9 si es 0
+	Epsilon          float64  // 1e-8 si es 0
+	DecaimientoPesos float64
+}
+
+// `Actualizar` aplica un paso de RMSProp.
+func (o *RMSProp) Actualizar(e *Estado, gradientes [][]float64) {
+	eta := tasa(o.Tasa, 0.001, e.Pasos)
+	rho := valor(o.Rho, 0.9)
+	epsilon := valor(o.Epsilon, 1e-8)
+	media := e.reservar(&e.Segundos)
+	for k, p := range e.Parametros {
+		for j, g := range gradientes[k] {
+			media[k][j] = rho*media[k][j] + (1-rho)*g*g
+			p[j] -= eta * (g/(math.Sqrt(media[k][j])+epsilon) + o.DecaimientoPesos*p[j])
+		}
+	}
+}
+
+// Estructura `Adam` (Kingma y Ba 2015): medias móviles del gradiente y de su cuadrado, con
+// corrección del sesgo inicial. Con `DecaimientoPesos` es AdamW.
+type Adam struct {
+	Tasa             Programa // 0.001 constante si es nil
+	Beta1            float64  // 0.9 si es 0
+	Beta2            float64  // 0.999 si es 0
+	Epsilon          float64  // 1e-8 si es 0
+	DecaimientoPesos float64
+}
+
+// `Actualizar` aplica un paso de Adam.
+func (o *Adam) Actualizar(e *Estado, gradientes [][]float64) {
+	eta := tasa(o.Tasa, 0.001, e.Pasos)
+	beta1 := valor(o.Beta1, 0.9)
+	beta2 := valor(o.Beta2, 0.999)
+	epsilon := valor(o.Epsilon, 1e-8)
+	m := e.reservar(&e.Momentos)
+	v := e.reservar(&e.Segundos)
+	correccion1 := 1 - math.Pow(beta1, float64(e.Pasos+1))
+	correccion2 := 1 - math.Pow(beta2, float64(e.Pasos+1))
+	for k, p := range e.Parametros {
+		for j, g := range gradientes[k] {
+			m[k][j] = beta1*m[k][j] + (1-beta1)*g
+			v[k][j] = beta2*v[k][j] + (1-beta2)*g*g
+			mediaCorregida := m[k][j] / correccion1
+			cuadradoCorregido := v[k][j] / correccion2
+			p[j] -= eta * (mediaCorregida/(math.Sqrt(cuadradoCorregido)+epsilon) + o.DecaimientoPesos*p[j])
+		}
+	}
+}
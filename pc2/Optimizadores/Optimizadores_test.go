@@ -0,0 +1,82 @@
+package Optimizadores
+
+import (
+	"math"
+	"testing"
+)
+
+// Cada optimizador minimiza una función cuadrática mal condicionada
+// f(w) = ½ Σ a_j (w_j - c_j)², repartida en dos vectores de parámetros.
+func TestMinimizaCuadratica(t *testing.T) {
+	a := [][]float64{{1, 10}, {0.1}}
+	c := [][]float64{{3, -2}, {1}}
+	casos := []struct {
+		nombre      string
+		optimizador Optimizador
+		pasos       int
+	}{
+		{"sgd", &SGD{Tasa: Constante(0.15)}, 1000},
+		{"momentum", &SGD{Tasa: Constante(0.05), Momento: 0.9}, 1000},
+		{"nesterov", &SGD{Tasa: Constante(0.05), Momento: 0.9, Nesterov: true}, 1000},
+		{"adagrad", &AdaGrad{Tasa: Constante(1)}, 2000},
+		{"rmsprop", &RMSProp{Tasa: Exponencial{Inicial: 0.05, Decaimiento: 0.995}}, 2000},
+		{"adam", &Adam{Tasa: Coseno{Inicial: 0.1, Minima: 1e-4, Pasos: 2000}}, 2000},
+		{"adam warmup", &Adam{Tasa: Calentamiento{Pasos: 100, Programa: Constante(0.05)}}, 3000},
+	}
+	for _, caso := range casos {
+		w := [][]float64{{0, 0}, {0}}
+		estado := NuevoEstado(caso.optimizador, w)
+		g := [][]float64{{0, 0}, {0}}
+		for paso := 0; paso < caso.pasos; paso++ {
+			for k := range w {
+				for j := range w[k] {
+					g[k][j] = a[k][j] * (w[k][j] - c[k][j])
+				}
+			}
+			estado.Paso(g)
+		}
+		for k := range w {
+			for j := range w[k] {
+				if math.Abs(w[k][j]-c[k][j]) > 1e-2 {
+					t.Errorf("%s: w = %v, expected %v", caso.nombre, w, c)
+				}
+			}
+		}
+		if estado.Pasos != caso.pasos {
+			t.Errorf("%s: %d steps, expected %d", caso.nombre, estado.Pasos, caso.pasos)
+		}
+	}
+}
+
+// Con gradiente cero el decaimiento de pesos encoge los parámetros hacia 0.
+func TestDecaimientoPesos(t *testing.T) {
+	w := [][]float64{{1}}
+	estado := NuevoEstado(&SGD{Tasa: Constante(0.1), DecaimientoPesos: 0.5}, w)
+	estado.Paso([][]float64{{0}})
+	if math.Abs(w[0][0]-0.95) > 1e-12 {
+		t.Fatalf("w = %v after one step, expected 0.95", w[0][0])
+	}
+}
+
+func TestProgramas(t *testing.T) {
+	casos := []struct {
+		nombre   string
+		programa Programa
+		paso     int
+		esperado float64
+	}{
+		{"constante", Constante(0.1), 50, 0.1},
+		{"escalonada", Escalonada{Inicial: 1, Factor: 0.5, Cada: 10}, 25, 0.25},
+		{"exponencial", Exponencial{Inicial: 1, Decaimiento: 0.9}, 2, 0.81},
+		{"coseno inicio", Coseno{Inicial: 1, Minima: 0, Pasos: 100}, 0, 1},
+		{"coseno mitad", Coseno{Inicial: 1, Minima: 0, Pasos: 100}, 50, 0.5},
+		{"coseno final", Coseno{Inicial: 1, Minima: 0.1, Pasos: 100}, 200, 0.1},
+		{"calentamiento", Calentamiento{Pasos: 4, Programa: Constante(1)}, 1, 0.5},
+		{"después del calentamiento", Calentamiento{Pasos: 4, Programa: Escalonada{Inicial: 1, Factor: 0.5, Cada: 2}}, 6, 0.5},
+	}
+	for _, caso := range casos {
+		if tasa := caso.programa.Tasa(caso.paso); math.Abs(tasa-caso.esperado) > 1e-12 {
+			t.Errorf("%s: rate %v at step %d, expected %v", caso.nombre, tasa, caso.paso, caso.esperado)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package Optimizadores
+
+import "math"
+
+// Interfaz `Programa` de la tasa de aprendizaje: `Tasa` devuelve la tasa del paso `paso`
+// (0 es el primer paso del optimizador).
+type Programa interface {
+	Tasa(paso int) float64
+}
+
+// Tipo `Constante`: la misma tasa en todos los pasos.
+type Constante float64
+
+// `Tasa` devuelve la tasa constante.
+func (c Constante) Tasa(paso int) float64 {
+	return float64(c)
+}
+
+// Estructura `Escalonada`: la tasa se multiplica por `Factor` cada `Cada` pasos.
+type Escalonada struct {
+	Inicial float64
+	Factor  float64 // Por ejemplo 0.5 para dividir la tasa a la mitad
+	Cada    int
+}
+
+// `Tasa` devuelve `Inicial`·`Factor`^⌊paso/`Cada`⌋.
+func (s Escalonada) Tasa(paso int) float64 {
+	if s.Cada <= 0 {
+		return s.Inicial
+	}
+	return s.Inicial * math.Pow(s.Factor, float64(paso/s.Cada))
+}
+
+// Estructura `Exponencial`: la tasa se multiplica por `Decaimiento` en cada paso.
+type Exponencial struct {
+	Inicial     float64
+	Decaimiento float64 // Por ejemplo 0.999
+}
+
+// `Tasa` devuelve `Inicial`·`Decaimiento`^paso.
+func (s Exponencial) Tasa(paso int) float64 {
+	return s.Inicial * math.Pow(s.Decaimiento, float64(paso))
+}
+
+// Estructura `Coseno` (Loshchilov y Hutter 2017): la tasa baja de `Inicial` a `Minima` en
+// `Pasos` pasos siguiendo medio período de un coseno, y luego queda en `Minima`.
+type Coseno struct {
+	Inicial float64
+	Minima  float64
+	Pasos   int
+}
+
+// `Tasa` devuelve `Minima` + (`Inicial` - `Minima`)·(1 + cos(π·paso/`Pasos`))/2.
+func (s Coseno) Tasa(paso int) float64 {
+	if paso >= s.Pasos {
+		return s.Minima
+	}
+	return s.Minima + (s.Inicial-s.Minima)*(1+math.Cos(math.Pi*float64(paso)/float64(s.Pasos)))/2
+}
+
+// Estructura `Calentamiento`: la tasa crece linealmente durante los primeros `Pasos` pasos
+// hasta la tasa inicial de `Programa`, que luego se sigue desde su paso 0.
+type Calentamiento struct {
+	Pasos    int
+	Programa Programa
+}
+
+// `Tasa` devuelve la tasa del calentamiento o la de `Programa` desplazada `Pasos` pasos.
+func (s Calentamiento) Tasa(paso int) float64 {
+	if paso < s.Pasos {
+		return s.Programa.Tasa(0) * float64(paso+1) / float64(s.Pasos)
+	}
+	return s.Programa.Tasa(paso - s.Pasos)
+}
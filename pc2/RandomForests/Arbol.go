@@ -0,0 +1,195 @@
+// Package RandomForests implementa árboles de decisión (índice de Gini sobre
+// características numéricas) y bosques aleatorios. Reemplaza a los marcadores de posición
+// de RandomForests_* y a la simulación de Arbol_*: el árbol busca en paralelo la mejor
+// división de cada característica y el bosque entrena sus árboles en paralelo.
+package RandomForests
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	"pc2/ML"
+)
+
+// Estructura para un nodo del árbol de decisión: los nodos internos envían a `Izquierda` los
+// ejemplos con `x[Caracteristica] <= Valor`; las hojas tienen `Clasificacion`.
+type Nodo struct {
+	Caracteristica int
+	Valor          float64
+	Izquierda      *Nodo
+	Derecha        *Nodo
+	Clasificacion  int
+}
+
+// Estructura para un árbol de decisión, con sus parámetros de entrenamiento.
+type Arbol struct {
+	Profundidad     int   // Profundidad máxima
+	Caracteristicas int   // Características evaluadas en cada nodo; todas si es 0
+	Workers         int   // Goroutines para evaluar las características de cada nodo
+	Semilla         int64 // Semilla para elegir características; si es 0 se usa la hora actual
+
+	Raiz *Nodo
+}
+
+// Mejor división encontrada para una característica.
+type division struct {
+	ganancia float64
+	valor    float64
+}
+
+// `Fit` construye el árbol con las clases 0, 1, ... de `train`.
+func (arbol *Arbol) Fit(train *ML.Dataset) error {
+	if err := ML.CheckTrain(train); err != nil {
+		return err
+	}
+	clases := 0
+	for _, y := range train.Y {
+		if int(y)+1 > clases {
+			clases = int(y) + 1
+		}
+	}
+	indices := make([]int, train.Len())
+	for i := range indices {
+		indices[i] = i
+	}
+	constructor := &constructor{arbol: arbol, train: train, clases: clases, rng: ML.NewRand(arbol.Semilla)}
+	arbol.Raiz = constructor.construir(indices, 0)
+	return nil
+}
+
+// `Predict` devuelve la clase de la hoja a la que llega `x`.
+func (arbol *Arbol) Predict(x []float64) float64 {
+	nodo := arbol.Raiz
+	for nodo.Izquierda != nil {
+		if x[nodo.Caracteristica] <= nodo.Valor {
+			nodo = nodo.Izquierda
+		} else {
+			nodo = nodo.Derecha
+		}
+	}
+	return float64(nodo.Clasificacion)
+}
+
+// Estado compartido mientras se construye un árbol.
+type constructor struct {
+	arbol  *Arbol
+	train  *ML.Dataset
+	clases int
+	rng    *rand.Rand
+}
+
+// Construye el subárbol de los ejemplos `indices`.
+func (c *constructor) construir(indices []int, profundidad int) *Nodo {
+	conteos := c.contar(indices)
+	hoja := &Nodo{}
+	for clase, conteo := range conteos {
+		if conteo > conteos[hoja.Clasificacion] {
+			hoja.Clasificacion = clase
+		}
+	}
+	if profundidad >= c.arbol.Profundidad || conteos[hoja.Clasificacion] == len(indices) {
+		return hoja
+	}
+
+	caracteristicas := c.rng.Perm(c.train.Features())
+	if c.arbol.Caracteristicas > 0 && c.arbol.Caracteristicas < len(caracteristicas) {
+		caracteristicas = caracteristicas[:c.arbol.Caracteristicas]
+	}
+	sort.Ints(caracteristicas)
+
+	// Se evalúa cada característica (en paralelo si hay varios workers) y gana la de mayor
+	// ganancia; en caso de empate, la de menor índice.
+	divisiones := make([]division, len(caracteristicas))
+	workers := c.arbol.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	siguiente := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range siguiente {
+				divisiones[k] = c.mejorDivision(indices, caracteristicas[k], conteos)
+			}
+		}()
+	}
+	for k := range caracteristicas {
+		siguiente <- k
+	}
+	close(siguiente)
+	wg.Wait()
+
+	mejor := -1
+	for k, d := range divisiones {
+		if d.ganancia > 0 && (mejor < 0 || d.ganancia > divisiones[mejor].ganancia) {
+			mejor = k
+		}
+	}
+	if mejor < 0 {
+		return hoja
+	}
+
+	nodo := &Nodo{Caracteristica: caracteristicas[mejor], Valor: divisiones[mejor].valor}
+	var izquierda, derecha []int
+	for _, i := range indices {
+		if c.train.X[i][nodo.Caracteristica] <= nodo.Valor {
+			izquierda = append(izquierda, i)
+		} else {
+			derecha = append(derecha, i)
+		}
+	}
+	nodo.Izquierda = c.construir(izquierda, profundidad+1)
+	nodo.Derecha = c.construir(derecha, profundidad+1)
+	return nodo
+}
+
+// Cuenta los ejemplos de cada clase.
+func (c *constructor) contar(indices []int) []int {
+	conteos := make([]int, c.clases)
+	for _, i := range indices {
+		conteos[int(c.train.Y[i])]++
+	}
+	return conteos
+}
+
+// Impureza de Gini por la cantidad de ejemplos: n - suma(conteo^2) / n.
+func giniPonderado(conteos []int, n int) float64 {
+	if n == 0 {
+		return 0
+	}
+	suma := 0.0
+	for _, conteo := range conteos {
+		suma += float64(conteo * conteo)
+	}
+	return float64(n) - suma/float64(n)
+}
+
+// Mejor umbral de la característica `f`: ordena los ejemplos por su valor y recorre los
+// cortes entre valores distintos acumulando los conteos de la rama izquierda.
+func (c *constructor) mejorDivision(indices []int, f int, conteos []int) division {
+	x := c.train.X
+	orden := append([]int(nil), indices...)
+	sort.Slice(orden, func(a, b int) bool { return x[orden[a]][f] < x[orden[b]][f] })
+
+	total := giniPonderado(conteos, len(indices))
+	izquierda := make([]int, c.clases)
+	derecha := append([]int(nil), conteos...)
+	mejor := division{}
+	for k := 0; k < len(orden)-1; k++ {
+		clase := int(c.train.Y[orden[k]])
+		izquierda[clase]++
+		derecha[clase]--
+		actual, proximo := x[orden[k]][f], x[orden[k+1]][f]
+		if actual == proximo {
+			continue
+		}
+		ganancia := total - giniPonderado(izquierda, k+1) - giniPonderado(derecha, len(orden)-k-1)
+		if ganancia > mejor.ganancia {
+			mejor = division{ganancia: ganancia, valor: (actual + proximo) / 2}
+		}
+	}
+	return mejor
+}
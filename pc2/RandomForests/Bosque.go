@@ -0,0 +1,92 @@
+package RandomForests
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"pc2/ML"
+)
+
+// Estructura `Bosque` de árboles de decisión entrenados sobre muestras bootstrap.
+type Bosque struct {
+	Arboles         int   // Cantidad de árboles
+	Profundidad     int   // Profundidad máxima de cada árbol
+	Caracteristicas int   // Características evaluadas en cada nodo; la raíz cuadrada del total si es 0
+	Workers         int   // Goroutines que entrenan árboles; con 1 o menos se entrenan secuencialmente
+	Semilla         int64 // Semilla del bosque; si es 0 se usa la hora actual
+
+	Modelos []*Arbol
+}
+
+// `Fit` entrena `Arboles` árboles, cada uno sobre una muestra bootstrap de `train` y con su
+// propio generador derivado de la semilla, por lo que el resultado no depende de `Workers`.
+func (bosque *Bosque) Fit(train *ML.Dataset) error {
+	if err := ML.CheckTrain(train); err != nil {
+		return err
+	}
+	semilla := bosque.Semilla
+	if semilla == 0 {
+		semilla = time.Now().UnixNano()
+	}
+	caracteristicas := bosque.Caracteristicas
+	if caracteristicas <= 0 {
+		caracteristicas = int(math.Ceil(math.Sqrt(float64(train.Features()))))
+	}
+	workers := bosque.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	bosque.Modelos = make([]*Arbol, bosque.Arboles)
+	errores := make([]error, bosque.Arboles)
+	var wg sync.WaitGroup
+	siguiente := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range siguiente {
+				rng := ML.NewRand(semilla + int64(a) + 1)
+				muestra := make([]int, train.Len())
+				for i := range muestra {
+					muestra[i] = rng.Intn(train.Len())
+				}
+				arbol := &Arbol{
+					Profundidad:     bosque.Profundidad,
+					Caracteristicas: caracteristicas,
+					Workers:         1,
+					Semilla:         rng.Int63() + 1,
+				}
+				errores[a] = arbol.Fit(train.Subset(muestra))
+				bosque.Modelos[a] = arbol
+			}
+		}()
+	}
+	for a := 0; a < bosque.Arboles; a++ {
+		siguiente <- a
+	}
+	close(siguiente)
+	wg.Wait()
+
+	for _, err := range errores {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// `Predict` devuelve la clase más votada por los árboles; en caso de empate, la menor.
+func (bosque *Bosque) Predict(x []float64) float64 {
+	votos := make(map[float64]int)
+	mejor, mejorVotos := 0.0, 0
+	for _, arbol := range bosque.Modelos {
+		clase := arbol.Predict(x)
+		votos[clase]++
+		if votos[clase] > mejorVotos || votos[clase] == mejorVotos && clase < mejor {
+			mejor, mejorVotos = clase, votos[clase]
+		}
+	}
+	return mejor
+}
@@ -0,0 +1,27 @@
+package RandomForests
+
+import (
+	"testing"
+
+	"pc2/ML"
+)
+
+// El bosque aprende datos separables y su resultado no depende de la cantidad de workers.
+func TestBosqueWorkers(t *testing.T) {
+	train, test := ML.LinearClassification(2000, 4, 0, 9).Split(0.8, 9)
+	var precisiones []float64
+	for _, workers := range []int{1, 4} {
+		bosque := &Bosque{Arboles: 10, Profundidad: 8, Workers: workers, Semilla: 9}
+		if err := bosque.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		precisiones = append(precisiones, ML.Accuracy(bosque, test))
+	}
+	t.Logf("accuracy: sequential %.4f, concurrent %.4f", precisiones[0], precisiones[1])
+	if precisiones[0] != precisiones[1] {
+		t.Fatalf("sequential and concurrent forests differ: %.4f vs %.4f", precisiones[0], precisiones[1])
+	}
+	if precisiones[0] < 0.85 {
+		t.Fatalf("accuracy %.4f on separable data, expected at least 0.85", precisiones[0])
+	}
+}
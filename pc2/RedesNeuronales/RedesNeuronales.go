@@ -0,0 +1,168 @@
+// Package RedesNeuronales implementa la red de RedesNeuronales_Secuencial y
+// RedesNeuronales_Concurrente: una capa oculta sigmoide cuyos pesos y los de la salida se
+// guardan en una sola matriz, con entrenamiento por mini-lotes secuencial o con paralelismo
+// de datos según `Workers`.
+package RedesNeuronales
+
+import (
+	"fmt"
+	"math"
+
+	"pc2/ML"
+)
+
+// Estructura `Red` con los parámetros de entrenamiento y los pesos aprendidos: las filas
+// 0..`Ocultas`-1 de `Pesos` son las neuronas ocultas y la última fila es la salida.
+type Red struct {
+	Ocultas         int
+	Epocas          int // 10 si es 0
+	TasaAprendizaje float64
+	TamañoLote      int   // Ejemplos por paso de gradiente; 32 si es 0
+	Workers         int   // Goroutines que calculan el gradiente de cada mini-lote; con 1 o menos se entrena secuencialmente
+	Semilla         int64 // Semilla de los pesos iniciales; si es 0 se usa la hora actual
+
+	Pesos [][]float64
+}
+
+// Función de activación sigmoide
+func sigmoide(x float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-x))
+}
+
+// Derivada de la función sigmoide, a partir de su valor ya activado
+func derivadaSigmoide(x float64) float64 {
+	return x * (1.0 - x)
+}
+
+// Inicializar los pesos de las capas de la red neuronal
+func (red *Red) inicializarPesos(numFeatures int) {
+	rng := ML.NewRand(red.Semilla)
+	red.Pesos = make([][]float64, red.Ocultas+1) // Capas: oculta + salida
+	for i := range red.Pesos {
+		if i < red.Ocultas {
+			red.Pesos[i] = make([]float64, numFeatures) // Pesos de la capa oculta
+		} else {
+			red.Pesos[i] = make([]float64, red.Ocultas) // Pesos de la capa de salida
+		}
+		for j := range red.Pesos[i] {
+			red.Pesos[i][j] = rng.Float64() // Inicialización aleatoria de pesos
+		}
+	}
+}
+
+// Forward pass: devuelve la capa oculta y la salida de la red
+func (red *Red) propagar(features []float64) ([]float64, float64) {
+	capaOculta := make([]float64, red.Ocultas)
+	for i := range capaOculta {
+		for j, peso := range red.Pesos[i] {
+			capaOculta[i] += features[j] * peso
+		}
+		capaOculta[i] = sigmoide(capaOculta[i])
+	}
+
+	// Cálculo de la predicción final
+	salida := 0.0
+	for i := range capaOculta {
+		salida += capaOculta[i] * red.Pesos[red.Ocultas][i]
+	}
+	return capaOculta, sigmoide(salida)
+}
+
+// Retropropagación: suma en `g` (con la forma de `Pesos`) el gradiente del error cuadrático
+// ½(salida - y)² del ejemplo y devuelve ese error. Solo lee los pesos.
+func (red *Red) retropropagar(features []float64, y float64, g [][]float64) float64 {
+	capaOculta, salida := red.propagar(features)
+	salidaPesos := red.Pesos[red.Ocultas]
+
+	errorSalida := salida - y
+	ajusteSalida := errorSalida * derivadaSigmoide(salida)
+
+	// Gradiente de los pesos de la capa de salida
+	for i := range capaOculta {
+		g[red.Ocultas][i] += ajusteSalida * capaOculta[i]
+	}
+
+	// Gradiente de los pesos de la capa oculta
+	for i := range capaOculta {
+		ajusteOculta := ajusteSalida * salidaPesos[i] * derivadaSigmoide(capaOculta[i])
+		for j := range features {
+			g[i][j] += ajusteOculta * features[j]
+		}
+	}
+	return errorSalida * errorSalida / 2
+}
+
+// `Fit` inicializa los pesos y entrena la red con las etiquetas 0 o 1 de `train` durante
+// `Epocas` épocas (ver `Epoca`); para usar validación, callbacks o puntos de control se
+// entrena con `ML.Entrenar`.
+func (red *Red) Fit(train *ML.Dataset) error {
+	if red.Epocas <= 0 {
+		red.Epocas = 10
+	}
+	_, err := ML.Entrenar(red, train, ML.Entrenamiento{Epocas: red.Epocas})
+	return err
+}
+
+// `Preparar` inicializa los pesos para `train`, salvo al retomar un punto de control.
+func (red *Red) Preparar(train *ML.Dataset, reanudar bool) error {
+	if red.TamañoLote <= 0 {
+		red.TamañoLote = 32
+	}
+	if reanudar {
+		if len(red.Pesos) != red.Ocultas+1 || len(red.Pesos[0]) != train.Features() {
+			return fmt.Errorf("redes: checkpoint does not match %d features", train.Features())
+		}
+		return nil
+	}
+	red.inicializarPesos(train.Features())
+	return nil
+}
+
+// `Epoca` recorre el dataset en mini-lotes, en orden. Con varios `Workers`, cada goroutine
+// calcula el gradiente de su tramo de cada lote en un búfer propio, los búferes se reducen en
+// árbol (ver `ML.GradienteParalelo`) y los pesos se actualizan una vez por lote, en lugar de
+// serializar cada ejemplo detrás de un mutex. El paso usa la suma de los gradientes del lote,
+// por lo que la tasa equivale a la del entrenamiento ejemplo por ejemplo del programa original.
+func (red *Red) Epoca(train *ML.Dataset, epoca int) (float64, error) {
+	paralelo := ML.NuevoGradienteParalelo(red.Workers, red.Pesos, func(w int, indices []int, g [][]float64) float64 {
+		perdida := 0.0
+		for _, i := range indices {
+			perdida += red.retropropagar(train.X[i], train.Y[i], g)
+		}
+		return perdida
+	})
+	defer paralelo.Cerrar()
+
+	orden := make([]int, train.Len())
+	for i := range orden {
+		orden[i] = i
+	}
+	total := 0.0
+	for _, lote := range ML.Lotes(orden, red.TamañoLote) {
+		g, perdida := paralelo.Calcular(lote)
+		total += perdida
+		ML.PasoGradiente(red.Pesos, g, red.TasaAprendizaje)
+	}
+	return total / float64(train.Len()), nil
+}
+
+// `Loss` devuelve el error cuadrático medio ½(salida - y)² de la red sobre `d`.
+func (red *Red) Loss(d *ML.Dataset) float64 {
+	if d.Len() == 0 {
+		return 0
+	}
+	total := 0.0
+	for i, x := range d.X {
+		_, salida := red.propagar(x)
+		total += (salida - d.Y[i]) * (salida - d.Y[i]) / 2
+	}
+	return total / float64(d.Len())
+}
+
+// `Predict` devuelve 1 si la salida de la red supera 0.5, 0 si no.
+func (red *Red) Predict(x []float64) float64 {
+	if _, salida := red.propagar(x); salida > 0.5 {
+		return 1
+	}
+	return 0
+}
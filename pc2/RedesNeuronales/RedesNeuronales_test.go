@@ -0,0 +1,38 @@
+package RedesNeuronales
+
+import (
+	"math"
+	"testing"
+
+	"pc2/ML"
+)
+
+// Con paralelismo de datos el entrenamiento es reproducible y coincide con el secuencial
+// salvo por el redondeo del orden de las sumas (ejecutar también con -race).
+func TestRedWorkers(t *testing.T) {
+	train := ML.Blobs(600, 4, 2, 0.5, 3)
+	entrenar := func(workers int) *Red {
+		red := &Red{Ocultas: 5, Epocas: 5, TasaAprendizaje: 0.01, TamañoLote: 50, Workers: workers, Semilla: 3}
+		if err := red.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		return red
+	}
+	secuencial := entrenar(1)
+	paralela := entrenar(4)
+	otra := entrenar(4)
+
+	for i, fila := range paralela.Pesos {
+		for j, v := range fila {
+			if v != otra.Pesos[i][j] {
+				t.Fatalf("weight %d/%d differs between runs with 4 workers", i, j)
+			}
+			if math.Abs(v-secuencial.Pesos[i][j]) > 1e-9 {
+				t.Fatalf("weight %d/%d: %v with 4 workers, %v sequentially", i, j, v, secuencial.Pesos[i][j])
+			}
+		}
+	}
+	if a, b := ML.Accuracy(secuencial, train), ML.Accuracy(paralela, train); a != b {
+		t.Fatalf("accuracy %v sequentially, %v with 4 workers", a, b)
+	}
+}
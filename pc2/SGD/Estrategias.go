@@ -0,0 +1,241 @@
+package SGD
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"pc2/ML"
+)
+
+// Reparte `orden` en `workers` bloques consecutivos (el último con los restantes).
+func repartir(orden []int, workers int) [][]int {
+	if workers > len(orden) {
+		workers = len(orden)
+	}
+	tamaño := len(orden) / workers
+	partes := make([][]int, workers)
+	for w := range partes {
+		fin := (w + 1) * tamaño
+		if w == workers-1 {
+			fin = len(orden)
+		}
+		partes[w] = orden[w*tamaño : fin]
+	}
+	return partes
+}
+
+// Producto de los parámetros (pesos y, al final, el sesgo) con `x`.
+func decision(parametros []float64, x ML.Disperso) float64 {
+	suma := parametros[len(parametros)-1]
+	for n, j := range x.Indices {
+		suma += parametros[j] * x.Valores[n]
+	}
+	return suma
+}
+
+// Aplica un paso de SGD con el ejemplo (`x`, `y`) y la tasa `tasa` sobre `parametros`. Solo
+// cambian el sesgo y los pesos de las características presentes en `x`, también en la
+// regularización.
+func (modelo *Lineal) paso(parametros []float64, x ML.Disperso, y, tasa float64) {
+	g := modelo.derivada(decision(parametros, x), y)
+	for n, j := range x.Indices {
+		parametros[j] -= tasa * (g*x.Valores[n] + modelo.Lambda*parametros[j])
+	}
+	parametros[len(parametros)-1] -= tasa * g
+}
+
+// MUTEX: los workers comparten los parámetros y un mutex protege cada paso completo
+// (lectura y actualización), por lo que en la práctica se ejecutan de a uno.
+func (modelo *Lineal) entrenarMutex(d *ML.DatasetDisperso) []float64 {
+	rng := ML.NewRand(modelo.Semilla)
+	parametros := make([]float64, d.Features()+1)
+	var mutex sync.Mutex
+	t := 0
+	for epoch := 0; epoch < modelo.Epocas; epoch++ {
+		var wg sync.WaitGroup
+		for _, parte := range repartir(rng.Perm(d.Len()), modelo.Workers) {
+			wg.Add(1)
+			go func(parte []int) {
+				defer wg.Done()
+				for _, i := range parte {
+					mutex.Lock()
+					modelo.paso(parametros, d.X[i], d.Y[i], modelo.tasa(t))
+					t++
+					mutex.Unlock()
+				}
+			}(parte)
+		}
+		wg.Wait()
+	}
+	return parametros
+}
+
+// Suma `delta` al float64 guardado en `bits` con compare-and-swap.
+func sumarAtomico(bits *uint64, delta float64) {
+	for {
+		anterior := atomic.LoadUint64(bits)
+		nuevo := math.Float64bits(math.Float64frombits(anterior) + delta)
+		if atomic.CompareAndSwapUint64(bits, anterior, nuevo) {
+			return
+		}
+	}
+}
+
+// HOGWILD: los parámetros compartidos se guardan como bits de float64; cada worker los lee
+// y les suma su paso con operaciones atómicas, sin bloquear a los demás. Un worker puede
+// calcular su gradiente con pesos que otro está modificando; con datos poco correlacionados
+// eso apenas afecta la convergencia.
+func (modelo *Lineal) entrenarHogwild(d *ML.DatasetDisperso) []float64 {
+	rng := ML.NewRand(modelo.Semilla)
+	compartidos := make([]uint64, d.Features()+1)
+	var pasos int64
+	for epoch := 0; epoch < modelo.Epocas; epoch++ {
+		var wg sync.WaitGroup
+		for _, parte := range repartir(rng.Perm(d.Len()), modelo.Workers) {
+			wg.Add(1)
+			go func(parte []int) {
+				defer wg.Done()
+				local := make([]float64, len(compartidos))
+				sesgo := len(local) - 1
+				for _, i := range parte {
+					// Solo se leen los parámetros que usa el ejemplo.
+					x := d.X[i]
+					for _, j := range x.Indices {
+						local[j] = math.Float64frombits(atomic.LoadUint64(&compartidos[j]))
+					}
+					local[sesgo] = math.Float64frombits(atomic.LoadUint64(&compartidos[sesgo]))
+					tasa := modelo.tasa(int(atomic.AddInt64(&pasos, 1) - 1))
+					g := modelo.derivada(decision(local, x), d.Y[i])
+					for n, j := range x.Indices {
+						if delta := -tasa * (g*x.Valores[n] + modelo.Lambda*local[j]); delta != 0 {
+							sumarAtomico(&compartidos[j], delta)
+						}
+					}
+					if g != 0 {
+						sumarAtomico(&compartidos[sesgo], -tasa*g)
+					}
+				}
+			}(parte)
+		}
+		wg.Wait()
+	}
+
+	parametros := make([]float64, len(compartidos))
+	for j := range parametros {
+		parametros[j] = math.Float64frombits(compartidos[j])
+	}
+	return parametros
+}
+
+// PROMEDIO: en cada época cada worker recorre su parte del orden con una copia local de los
+// parámetros; cada `Sincronizar` pasos los workers se detienen, las copias se promedian y
+// todas continúan desde el promedio. La tasa usa los pasos de cada worker.
+func (modelo *Lineal) entrenarPromedio(d *ML.DatasetDisperso) []float64 {
+	rng := ML.NewRand(modelo.Semilla)
+	parametros := make([]float64, d.Features()+1)
+	for epoch := 0; epoch < modelo.Epocas; epoch++ {
+		partes := repartir(rng.Perm(d.Len()), modelo.Workers)
+		locales := make([][]float64, len(partes))
+		// El último bloque es el más largo: marca la cantidad de rondas.
+		for inicio := 0; inicio < len(partes[len(partes)-1]); inicio += modelo.Sincronizar {
+			var wg sync.WaitGroup
+			activos := make([][]float64, 0, len(partes))
+			for w, parte := range partes {
+				fin := inicio + modelo.Sincronizar
+				if fin > len(parte) {
+					fin = len(parte)
+				}
+				if inicio >= fin {
+					continue
+				}
+				locales[w] = append(locales[w][:0], parametros...)
+				activos = append(activos, locales[w])
+				wg.Add(1)
+				go func(local []float64, tramo []int, t int) {
+					defer wg.Done()
+					for _, i := range tramo {
+						modelo.paso(local, d.X[i], d.Y[i], modelo.tasa(t))
+						t++
+					}
+				}(locales[w], parte[inicio:fin], epoch*len(parte)+inicio)
+			}
+			wg.Wait()
+
+			for j := range parametros {
+				suma := 0.0
+				for _, local := range activos {
+					suma += local[j]
+				}
+				parametros[j] = suma / float64(len(activos))
+			}
+		}
+	}
+	return parametros
+}
+
+// MINIBATCH: cada lote se reparte entre los workers con `ML.GradienteParalelo`; cada uno
+// acumula en su propio búfer el gradiente de su tramo, anotando los pesos que toca, y los
+// búferes se suman con la reducción en árbol. El paso con el gradiente medio del lote solo
+// recorre los pesos tocados y el sesgo, así que cuesta O(características distintas de cero
+// del lote) y no O(d). Los parámetros solo se modifican cuando ningún worker los está leyendo.
+func (modelo *Lineal) entrenarMiniBatch(d *ML.DatasetDisperso) []float64 {
+	rng := ML.NewRand(modelo.Semilla)
+	parametros := make([]float64, d.Features()+1)
+	sesgo := d.Features()
+	workers := modelo.Workers
+	if workers > modelo.TamañoLote {
+		workers = modelo.TamañoLote
+	}
+	calculo := ML.NuevoGradienteDisperso(workers, [][]float64{parametros}, func(w int, indices []int, g [][]float64, tocados *ML.Tocados) float64 {
+		for _, i := range indices {
+			x := d.X[i]
+			derivada := modelo.derivada(decision(parametros, x), d.Y[i])
+			for n, j := range x.Indices {
+				g[0][j] += derivada * x.Valores[n]
+				tocados.Tocar(0, j)
+			}
+			g[0][sesgo] += derivada
+			tocados.Tocar(0, sesgo)
+		}
+		return 0
+	})
+	defer calculo.Cerrar()
+
+	// Regularización L2 perezosa: en un lote que no lo toca, un peso solo se multiplica por
+	// (1 - tasa·λ). `decaimiento` acumula el logaritmo de esos factores y `aplicado[j]` el
+	// valor hasta el que el peso j está al día; los pesos de un lote se ponen al día antes de
+	// calcular su gradiente, y todos al terminar el entrenamiento.
+	decaimiento := 0.0
+	aplicado := make([]float64, sesgo)
+	t := 0
+	for epoch := 0; epoch < modelo.Epocas; epoch++ {
+		for _, lote := range ML.Lotes(rng.Perm(d.Len()), modelo.TamañoLote) {
+			for _, i := range lote {
+				for _, j := range d.X[i].Indices {
+					parametros[j] *= math.Exp(decaimiento - aplicado[j])
+					aplicado[j] = decaimiento
+				}
+			}
+			g, tocados, _ := calculo.CalcularDisperso(lote)
+			tasa := modelo.tasa(t)
+			t++
+			escala := 1 / float64(len(lote))
+			factor := math.Log(1 - tasa*modelo.Lambda)
+			for _, q := range tocados {
+				j := q.J
+				if j == sesgo {
+					parametros[j] -= tasa * g[0][j] * escala
+					continue
+				}
+				parametros[j] -= tasa * (g[0][j]*escala + modelo.Lambda*parametros[j])
+				aplicado[j] = decaimiento + factor
+			}
+			decaimiento += factor
+		}
+	}
+	for j := range aplicado {
+		parametros[j] *= math.Exp(decaimiento - aplicado[j])
+	}
+	return parametros
+}
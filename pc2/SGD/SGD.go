@@ -0,0 +1,206 @@
+// Package SGD entrena modelos lineales (SVM con pérdida hinge, regresión logística y
+// regresión lineal) con descenso de gradiente estocástico en paralelo. La estrategia de
+// paralelismo se elige en `Config.Estrategia`:
+//
+//   - MUTEX: como SVM_Concurrente, un mutex global protege cada paso (referencia).
+//   - HOGWILD: los workers actualizan los pesos compartidos sin bloqueos, con sumas
+//     atómicas sobre los bits de cada float64 (Niu et al. 2011).
+//   - PROMEDIO: cada worker entrena una copia local sobre su parte del dataset y cada
+//     `Sincronizar` pasos las copias se promedian.
+//   - MINIBATCH: cada lote se reparte entre los workers, que acumulan su gradiente en un
+//     búfer propio; los búferes se suman con `ML.GradienteParalelo` y el paso se aplica una
+//     sola vez, solo sobre los pesos presentes en el lote (la regularización es perezosa).
+//
+// El orden de los ejemplos depende solo de la semilla. PROMEDIO y MINIBATCH dan el mismo
+// resultado en cada ejecución con la misma semilla y cantidad de workers; en MUTEX y
+// HOGWILD el intercalado de los pasos depende del planificador (con un worker también son
+// deterministas).
+//
+// Los ejemplos se recorren como vectores dispersos (`ML.Disperso`): cada paso solo toca el
+// sesgo y los pesos de las características presentes, y `FitDisperso` entrena directamente
+// con datos dispersos, por ejemplo leídos con `ML.LoadLibSVM`.
+package SGD
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"pc2/ML"
+)
+
+// Pérdidas disponibles.
+const HINGE = "hinge"           // SVM lineal; etiquetas de dos clases
+const LOGISTICA = "logistica"   // Regresión logística; etiquetas de dos clases
+const CUADRATICA = "cuadratica" // Regresión lineal; `Y` es el valor objetivo
+
+// Estrategias de entrenamiento en paralelo.
+const MUTEX = "mutex"
+const HOGWILD = "hogwild"
+const PROMEDIO = "promedio"
+const MINIBATCH = "minibatch"
+
+// Estructura `Config` con los parámetros de entrenamiento.
+type Config struct {
+	Perdida         string
+	Estrategia      string
+	Workers         int     // Goroutines de entrenamiento; 1 si es 0
+	Epocas          int     // Pasadas sobre el dataset; 10 si es 0
+	TasaAprendizaje float64 // Tasa inicial η0; 0.01 si es 0
+	Lambda          float64 // Regularización L2 de los pesos (no del sesgo)
+	TamañoLote      int     // Ejemplos por lote en MINIBATCH; 64 si es 0
+	Sincronizar     int     // Pasos locales entre promedios en PROMEDIO; 1000 si es 0
+	Semilla         int64   // Semilla del orden de los ejemplos; si es 0 se usa la hora actual
+}
+
+// Estructura `Lineal` con un modelo lineal y su configuración. En clasificación la decisión
+// `Pesos`·x + `Sesgo` es positiva para `Clases[1]`.
+type Lineal struct {
+	Config
+	Clases []float64 `json:",omitempty"`
+	Pesos  []float64
+	Sesgo  float64
+}
+
+// `Fit` entrena el modelo con la estrategia configurada.
+func (modelo *Lineal) Fit(train *ML.Dataset) error {
+	if err := ML.CheckTrain(train); err != nil {
+		return err
+	}
+	return modelo.FitDisperso(train.Disperso())
+}
+
+// `FitDisperso` entrena el modelo con ejemplos dispersos; cada paso solo recorre las
+// características presentes en el ejemplo.
+func (modelo *Lineal) FitDisperso(train *ML.DatasetDisperso) error {
+	if err := ML.CheckTrainDisperso(train); err != nil {
+		return err
+	}
+	if modelo.Workers < 1 {
+		modelo.Workers = 1
+	}
+	if modelo.Epocas <= 0 {
+		modelo.Epocas = 10
+	}
+	if modelo.TasaAprendizaje <= 0 {
+		modelo.TasaAprendizaje = 0.01
+	}
+	if modelo.TamañoLote <= 0 {
+		modelo.TamañoLote = 64
+	}
+	if modelo.Sincronizar <= 0 {
+		modelo.Sincronizar = 1000
+	}
+	if modelo.Perdida == "" {
+		modelo.Perdida = HINGE
+	}
+	if modelo.Estrategia == "" {
+		modelo.Estrategia = HOGWILD
+	}
+
+	// Las etiquetas de clasificación se convierten a -1/+1.
+	y := train.Y
+	modelo.Clases = nil
+	switch modelo.Perdida {
+	case HINGE, LOGISTICA:
+		vistas := make(map[float64]bool)
+		for _, etiqueta := range train.Y {
+			if !vistas[etiqueta] {
+				vistas[etiqueta] = true
+				modelo.Clases = append(modelo.Clases, etiqueta)
+			}
+		}
+		if len(modelo.Clases) != 2 {
+			return fmt.Errorf("sgd: %s loss needs exactly two classes, got %d", modelo.Perdida, len(modelo.Clases))
+		}
+		sort.Float64s(modelo.Clases)
+		y = make([]float64, train.Len())
+		for i, etiqueta := range train.Y {
+			y[i] = -1
+			if etiqueta == modelo.Clases[1] {
+				y[i] = 1
+			}
+		}
+	case CUADRATICA:
+	default:
+		return fmt.Errorf("sgd: unknown loss %q", modelo.Perdida)
+	}
+
+	entrenamiento := &ML.DatasetDisperso{X: train.X, Y: y, Dimension: train.Dimension}
+	var parametros []float64
+	switch modelo.Estrategia {
+	case MUTEX:
+		parametros = modelo.entrenarMutex(entrenamiento)
+	case HOGWILD:
+		parametros = modelo.entrenarHogwild(entrenamiento)
+	case PROMEDIO:
+		parametros = modelo.entrenarPromedio(entrenamiento)
+	case MINIBATCH:
+		parametros = modelo.entrenarMiniBatch(entrenamiento)
+	default:
+		return fmt.Errorf("sgd: unknown strategy %q", modelo.Estrategia)
+	}
+	modelo.Pesos = parametros[:train.Features()]
+	modelo.Sesgo = parametros[train.Features()]
+	if math.IsNaN(modelo.Sesgo) {
+		return errors.New("sgd: training diverged, try a smaller learning rate")
+	}
+	return nil
+}
+
+// `Decision` devuelve `Pesos`·x + `Sesgo`.
+func (modelo *Lineal) Decision(x []float64) float64 {
+	suma := modelo.Sesgo
+	for j, v := range x {
+		suma += modelo.Pesos[j] * v
+	}
+	return suma
+}
+
+// `DecisionDisperso` devuelve `Pesos`·x + `Sesgo` para un ejemplo disperso.
+func (modelo *Lineal) DecisionDisperso(x ML.Disperso) float64 {
+	return x.Producto(modelo.Pesos) + modelo.Sesgo
+}
+
+// `Predict` devuelve la clase según el signo de la decisión o, en regresión, la decisión.
+func (modelo *Lineal) Predict(x []float64) float64 {
+	return modelo.clase(modelo.Decision(x))
+}
+
+// `PredictDisperso` es `Predict` para un ejemplo disperso.
+func (modelo *Lineal) PredictDisperso(x ML.Disperso) float64 {
+	return modelo.clase(modelo.DecisionDisperso(x))
+}
+
+// Clase de la decisión `decision`, o la decisión en regresión.
+func (modelo *Lineal) clase(decision float64) float64 {
+	if modelo.Clases == nil {
+		return decision
+	}
+	if decision >= 0 {
+		return modelo.Clases[1]
+	}
+	return modelo.Clases[0]
+}
+
+// Derivada de la pérdida respecto de la decisión `f` para la etiqueta `y`; el gradiente de
+// los pesos es esta derivada por x (y por 1 para el sesgo).
+func (modelo *Lineal) derivada(f, y float64) float64 {
+	switch modelo.Perdida {
+	case HINGE:
+		if y*f < 1 {
+			return -y
+		}
+		return 0
+	case LOGISTICA:
+		return -y / (1 + math.Exp(y*f))
+	}
+	return f - y
+}
+
+// Tasa del paso `t` (contado desde 0): η0 / (1 + η0·λ·t), la que sugiere Bottou para SGD
+// con regularización L2.
+func (modelo *Lineal) tasa(t int) float64 {
+	return modelo.TasaAprendizaje / (1 + modelo.TasaAprendizaje*modelo.Lambda*float64(t))
+}
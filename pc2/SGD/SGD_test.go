@@ -0,0 +1,186 @@
+package SGD
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"pc2/ML"
+)
+
+var estrategias = []string{MUTEX, HOGWILD, PROMEDIO, MINIBATCH}
+
+// Configuración de prueba: MINIBATCH usa el gradiente medio del lote y necesita una tasa mayor.
+func configurar(estrategia string, workers int) Config {
+	config := Config{Perdida: HINGE, Estrategia: estrategia, Workers: workers, Epocas: 5, TasaAprendizaje: 0.01, Lambda: 1e-4, Sincronizar: 500, Semilla: 5}
+	if estrategia == MINIBATCH {
+		config.TasaAprendizaje = 1
+	}
+	return config
+}
+
+func TestEstrategias(t *testing.T) {
+	train, test := ML.LinearClassification(20000, 10, 0.05, 5).Split(0.8, 5)
+	for _, estrategia := range estrategias {
+		modelo := &Lineal{Config: configurar(estrategia, 4)}
+		if err := modelo.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		precision := ML.Accuracy(modelo, test)
+		t.Logf("%-9s accuracy %.4f", estrategia, precision)
+		if precision < 0.92 {
+			t.Errorf("%s: accuracy %.4f, expected at least 0.92", estrategia, precision)
+		}
+	}
+}
+
+// PROMEDIO y MINIBATCH son deterministas con varios workers; MUTEX y HOGWILD, con uno.
+func TestReproducible(t *testing.T) {
+	train := ML.LinearClassification(5000, 8, 0.05, 6)
+	for _, estrategia := range estrategias {
+		workers := 4
+		if estrategia == MUTEX || estrategia == HOGWILD {
+			workers = 1
+		}
+		var pesos [2][]float64
+		for k := range pesos {
+			modelo := &Lineal{Config: configurar(estrategia, workers)}
+			if err := modelo.Fit(train); err != nil {
+				t.Fatal(err)
+			}
+			pesos[k] = append(modelo.Pesos, modelo.Sesgo)
+		}
+		if fmt.Sprint(pesos[0]) != fmt.Sprint(pesos[1]) {
+			t.Errorf("%s with %d workers is not reproducible:\n%v\n%v", estrategia, workers, pesos[0], pesos[1])
+		}
+	}
+}
+
+func TestPerdidas(t *testing.T) {
+	train, test := ML.LinearClassification(10000, 5, 0, 7).Split(0.8, 7)
+	logistica := &Lineal{Config: Config{Perdida: LOGISTICA, Estrategia: HOGWILD, Workers: 2, TasaAprendizaje: 0.1, Semilla: 7}}
+	if err := logistica.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	if precision := ML.Accuracy(logistica, test); precision < 0.95 {
+		t.Errorf("logistic accuracy %.4f, expected at least 0.95", precision)
+	}
+
+	// y = 2·x0 - x1 + 0.5
+	regresion := &ML.Dataset{X: train.X, Y: make([]float64, train.Len())}
+	for i, x := range train.X {
+		regresion.Y[i] = 2*x[0] - x[1] + 0.5
+	}
+	cuadratica := &Lineal{Config: Config{Perdida: CUADRATICA, Estrategia: PROMEDIO, Workers: 4, Semilla: 7}}
+	if err := cuadratica.Fit(regresion); err != nil {
+		t.Fatal(err)
+	}
+	if rmse := ML.RMSE(cuadratica, regresion); rmse > 0.05 {
+		t.Errorf("squared loss RMSE %.4f, expected at most 0.05 (weights %v, bias %.3f)", rmse, cuadratica.Pesos, cuadratica.Sesgo)
+	}
+}
+
+// Con ejemplos dispersos de muchas características cada paso solo toca las presentes, y
+// `Fit` con filas densas da el mismo modelo que `FitDisperso`.
+func TestDisperso(t *testing.T) {
+	train, test := ML.SparseClassification(20000, 1000, 20, 0, 9).Split(0.8, 9)
+	for _, estrategia := range []string{HOGWILD, MINIBATCH} {
+		modelo := &Lineal{Config: configurar(estrategia, 4)}
+		modelo.Perdida = LOGISTICA
+		modelo.Epocas = 10
+		if err := modelo.FitDisperso(train); err != nil {
+			t.Fatal(err)
+		}
+		precision := ML.AccuracyDisperso(modelo, test)
+		t.Logf("%-9s accuracy %.4f", estrategia, precision)
+		if precision < 0.9 {
+			t.Errorf("%s: sparse accuracy %.4f, expected at least 0.9", estrategia, precision)
+		}
+	}
+
+	pequeño := ML.SparseClassification(500, 50, 5, 0, 10)
+	disperso := &Lineal{Config: configurar(PROMEDIO, 2)}
+	denso := &Lineal{Config: configurar(PROMEDIO, 2)}
+	if err := disperso.FitDisperso(pequeño); err != nil {
+		t.Fatal(err)
+	}
+	if err := denso.Fit(pequeño.Denso()); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(disperso.Pesos, disperso.Sesgo) != fmt.Sprint(denso.Pesos, denso.Sesgo) {
+		t.Errorf("dense and sparse training differ")
+	}
+}
+
+// La regularización perezosa de MINIBATCH da los mismos parámetros que regularizar todos los
+// pesos en cada lote.
+func TestMiniBatchRegularizacion(t *testing.T) {
+	train := ML.SparseClassification(2000, 200, 5, 0, 11)
+	modelo := &Lineal{Config: configurar(MINIBATCH, 3)}
+	modelo.Perdida = LOGISTICA
+	modelo.Lambda = 0.01
+	modelo.TamañoLote = 16
+	if err := modelo.FitDisperso(train); err != nil {
+		t.Fatal(err)
+	}
+
+	// Referencia densa con el mismo orden de lotes.
+	y := make([]float64, train.Len())
+	for i, etiqueta := range train.Y {
+		y[i] = -1
+		if etiqueta == modelo.Clases[1] {
+			y[i] = 1
+		}
+	}
+	rng := ML.NewRand(modelo.Semilla)
+	parametros := make([]float64, train.Features()+1)
+	paso := 0
+	for epoch := 0; epoch < modelo.Epocas; epoch++ {
+		for _, lote := range ML.Lotes(rng.Perm(train.Len()), modelo.TamañoLote) {
+			g := make([]float64, len(parametros))
+			for _, i := range lote {
+				derivada := modelo.derivada(decision(parametros, train.X[i]), y[i])
+				for n, j := range train.X[i].Indices {
+					g[j] += derivada * train.X[i].Valores[n]
+				}
+				g[len(g)-1] += derivada
+			}
+			tasa := modelo.tasa(paso)
+			paso++
+			for j := range parametros {
+				regularizacion := modelo.Lambda * parametros[j]
+				if j == len(parametros)-1 {
+					regularizacion = 0
+				}
+				parametros[j] -= tasa * (g[j]/float64(len(lote)) + regularizacion)
+			}
+		}
+	}
+	for j, esperado := range parametros {
+		obtenido := modelo.Sesgo
+		if j < len(modelo.Pesos) {
+			obtenido = modelo.Pesos[j]
+		}
+		if math.Abs(obtenido-esperado) > 1e-9 {
+			t.Fatalf("parameter %d: %v, expected %v", j, obtenido, esperado)
+		}
+	}
+}
+
+// Compara las estrategias con 4 workers sobre el mismo dataset:
+//
+//	go test ./SGD -bench . -benchtime 5x
+func BenchmarkEstrategias(b *testing.B) {
+	train := ML.LinearClassification(100000, 20, 0.05, 8)
+	for _, estrategia := range estrategias {
+		b.Run(estrategia, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				modelo := &Lineal{Config: configurar(estrategia, 4)}
+				modelo.Epocas = 1
+				if err := modelo.Fit(train); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package SVM
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"sync"
+
+	"pc2/ML"
+)
+
+// Tipos de kernel.
+const LINEAL = "lineal"         // x·z
+const RBF = "rbf"               // exp(-γ‖x - z‖²)
+const POLINOMICO = "polinomico" // (γ x·z + Coef0)^Grado
+
+// Estructura `Kernel` con el tipo y sus parámetros.
+type Kernel struct {
+	Tipo  string
+	Gamma float64 `json:",omitempty"` // RBF y polinómico; 1/características si es 0
+	Grado int     `json:",omitempty"` // Polinómico; 3 si es 0
+	Coef0 float64 `json:",omitempty"` // Polinómico
+}
+
+// Completa los parámetros por defecto y valida el tipo.
+func (k *Kernel) preparar(features int) error {
+	if k.Tipo == "" {
+		k.Tipo = RBF
+	}
+	if k.Gamma <= 0 {
+		k.Gamma = 1 / float64(features)
+	}
+	if k.Grado <= 0 {
+		k.Grado = 3
+	}
+	switch k.Tipo {
+	case LINEAL, RBF, POLINOMICO:
+		return nil
+	}
+	return fmt.Errorf("svm: unknown kernel %q", k.Tipo)
+}
+
+// `Evaluar` calcula el kernel entre `x` y `z`.
+func (k *Kernel) Evaluar(x, z []float64) float64 {
+	switch k.Tipo {
+	case RBF:
+		distancia := 0.0
+		for j := range x {
+			d := x[j] - z[j]
+			distancia += d * d
+		}
+		return math.Exp(-k.Gamma * distancia)
+	case POLINOMICO:
+		return math.Pow(k.Gamma*producto(x, z)+k.Coef0, float64(k.Grado))
+	}
+	return producto(x, z)
+}
+
+// Producto escalar.
+func producto(x, z []float64) float64 {
+	suma := 0.0
+	for j := range x {
+		suma += x[j] * z[j]
+	}
+	return suma
+}
+
+// Caché LRU de filas de la matriz de kernel: SMO pide en cada iteración las filas de los
+// dos ejemplos elegidos y suele volver a pedir las de los mismos vectores de soporte.
+// Las filas se calculan repartiendo las columnas entre `workers` goroutines.
+type cacheKernel struct {
+	kernel    *Kernel
+	x         ML.Matrix
+	capacidad int
+	workers   int
+	filas     map[int]*list.Element
+	orden     *list.List // Frente: la fila usada más recientemente
+	aciertos  int
+	fallos    int
+}
+
+// Fila guardada en la caché.
+type filaKernel struct {
+	indice  int
+	valores []float64
+}
+
+func newCacheKernel(kernel *Kernel, x ML.Matrix, capacidad, workers int) *cacheKernel {
+	if capacidad < 2 {
+		capacidad = 2 // SMO necesita dos filas a la vez
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return &cacheKernel{
+		kernel:    kernel,
+		x:         x,
+		capacidad: capacidad,
+		workers:   workers,
+		filas:     make(map[int]*list.Element),
+		orden:     list.New(),
+	}
+}
+
+// Devuelve la fila `i` de la matriz de kernel, calculándola si no está en la caché. El
+// slice devuelto sigue siendo válido hasta que se pidan `capacidad` filas más.
+func (c *cacheKernel) fila(i int) []float64 {
+	if elemento, ok := c.filas[i]; ok {
+		c.aciertos++
+		c.orden.MoveToFront(elemento)
+		return elemento.Value.(*filaKernel).valores
+	}
+	c.fallos++
+
+	var valores []float64
+	if c.orden.Len() >= c.capacidad {
+		// Se reutiliza la memoria de la fila menos usada.
+		ultimo := c.orden.Back()
+		c.orden.Remove(ultimo)
+		viejo := ultimo.Value.(*filaKernel)
+		delete(c.filas, viejo.indice)
+		valores = viejo.valores
+	} else {
+		valores = make([]float64, len(c.x))
+	}
+	c.calcular(i, valores)
+	c.filas[i] = c.orden.PushFront(&filaKernel{indice: i, valores: valores})
+	return valores
+}
+
+// Calcula K(x_i, x_t) para todo t, repartiendo los t en bloques entre las goroutines.
+func (c *cacheKernel) calcular(i int, valores []float64) {
+	n := len(c.x)
+	// Con pocas columnas no conviene lanzar goroutines.
+	if c.workers == 1 || n < 256 {
+		for t := range valores {
+			valores[t] = c.kernel.Evaluar(c.x[i], c.x[t])
+		}
+		return
+	}
+	tamaño := (n + c.workers - 1) / c.workers
+	var wg sync.WaitGroup
+	for inicio := 0; inicio < n; inicio += tamaño {
+		fin := inicio + tamaño
+		if fin > n {
+			fin = n
+		}
+		wg.Add(1)
+		go func(inicio, fin int) {
+			defer wg.Done()
+			for t := inicio; t < fin; t++ {
+				valores[t] = c.kernel.Evaluar(c.x[i], c.x[t])
+			}
+		}(inicio, fin)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,125 @@
+package SVM
+
+import (
+	"math"
+
+	"pc2/ML"
+)
+
+// Pliegues de la validación cruzada con la que se obtienen las decisiones para Platt.
+const pliegues = 5
+
+// Ajusta el escalado de Platt: calcula la decisión de cada ejemplo con un modelo que no lo
+// vio (validación cruzada de 5 pliegues, como LIBSVM) y ajusta la sigmoide sobre ellas.
+func (svm *KernelSVM) ajustarPlatt(train *ML.Dataset) error {
+	decisiones := make([]float64, train.Len())
+	positivos := make([]bool, train.Len())
+	orden := ML.NewRand(svm.Semilla).Perm(train.Len())
+	for k := 0; k < pliegues; k++ {
+		inicio := k * len(orden) / pliegues
+		fin := (k + 1) * len(orden) / pliegues
+		resto := append(append([]int(nil), orden[:inicio]...), orden[fin:]...)
+		submodelo := &KernelSVM{
+			Kernel:         svm.Kernel,
+			C:              svm.C,
+			Tolerancia:     svm.Tolerancia,
+			MaxIteraciones: svm.MaxIteraciones,
+			TamañoCache:    svm.TamañoCache,
+			Workers:        svm.Workers,
+		}
+		if err := submodelo.Fit(train.Subset(resto)); err != nil {
+			// El pliegue quedó con una sola clase: esos ejemplos no aportan información.
+			continue
+		}
+		for _, i := range orden[inicio:fin] {
+			decisiones[i] = submodelo.Decision(train.X[i])
+		}
+	}
+	for i, y := range train.Y {
+		positivos[i] = y == svm.Clases[1]
+	}
+	svm.PlattA, svm.PlattB = ajustarSigmoide(decisiones, positivos)
+	return nil
+}
+
+// Probabilidad 1 / (1 + exp(A·f + B)), calculada sin desbordes.
+func sigmoidPlatt(f, A, B float64) float64 {
+	fApB := f*A + B
+	if fApB >= 0 {
+		return math.Exp(-fApB) / (1 + math.Exp(-fApB))
+	}
+	return 1 / (1 + math.Exp(fApB))
+}
+
+// Ajusta A y B por máxima verosimilitud con el método de Newton con búsqueda lineal de
+// Lin, Lin y Weng (2007), con los objetivos suavizados de Platt para no sobreajustar.
+func ajustarSigmoide(decisiones []float64, positivos []bool) (float64, float64) {
+	prior1, prior0 := 0.0, 0.0
+	for _, positivo := range positivos {
+		if positivo {
+			prior1++
+		} else {
+			prior0++
+		}
+	}
+	objetivos := make([]float64, len(decisiones))
+	for i, positivo := range positivos {
+		objetivos[i] = 1 / (prior0 + 2)
+		if positivo {
+			objetivos[i] = (prior1 + 1) / (prior1 + 2)
+		}
+	}
+
+	// Valor de la verosimilitud negativa para A y B.
+	valor := func(A, B float64) float64 {
+		total := 0.0
+		for i, f := range decisiones {
+			fApB := f*A + B
+			if fApB >= 0 {
+				total += objetivos[i]*fApB + math.Log(1+math.Exp(-fApB))
+			} else {
+				total += (objetivos[i]-1)*fApB + math.Log(1+math.Exp(fApB))
+			}
+		}
+		return total
+	}
+
+	A, B := 0.0, math.Log((prior0+1)/(prior1+1))
+	actual := valor(A, B)
+	for iteracion := 0; iteracion < 100; iteracion++ {
+		// Gradiente y hessiana (con un término de Levenberg-Marquardt en la diagonal).
+		h11, h22, h21, g1, g2 := 1e-12, 1e-12, 0.0, 0.0, 0.0
+		for i, f := range decisiones {
+			p := sigmoidPlatt(f, A, B)
+			q := 1 - p
+			d2 := p * q
+			h11 += f * f * d2
+			h22 += d2
+			h21 += f * d2
+			d1 := objetivos[i] - p
+			g1 += f * d1
+			g2 += d1
+		}
+		if math.Abs(g1) < 1e-5 && math.Abs(g2) < 1e-5 {
+			break
+		}
+
+		determinante := h11*h22 - h21*h21
+		dA := -(h22*g1 - h21*g2) / determinante
+		dB := -(-h21*g1 + h11*g2) / determinante
+		descenso := g1*dA + g2*dB
+
+		paso := 1.0
+		for ; paso >= 1e-10; paso /= 2 {
+			nuevoA, nuevoB := A+paso*dA, B+paso*dB
+			if nuevo := valor(nuevoA, nuevoB); nuevo < actual+1e-4*paso*descenso {
+				A, B, actual = nuevoA, nuevoB, nuevo
+				break
+			}
+		}
+		if paso < 1e-10 {
+			break
+		}
+	}
+	return A, B
+}
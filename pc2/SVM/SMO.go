@@ -0,0 +1,274 @@
+package SVM
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"pc2/ML"
+)
+
+// Valor mínimo de la curvatura al actualizar un par, como `TAU` en LIBSVM.
+const tau = 1e-12
+
+// Estructura `KernelSVM` con una SVM binaria no lineal entrenada con SMO (Sequential Minimal
+// Optimization) sobre el problema dual. Solo guarda los vectores de soporte: la decisión es
+// Σ `Coeficientes`[i]·K(`Vectores`[i], x) + `Sesgo`, positiva para `Clases[1]`.
+type KernelSVM struct {
+	Kernel         Kernel
+	C              float64 // Penalización de las violaciones del margen; 1 si es 0
+	Tolerancia     float64 // Criterio de parada de SMO; 1e-3 si es 0
+	MaxIteraciones int     // Iteraciones máximas de SMO; 100 por ejemplo (al menos 100000) si es 0
+	TamañoCache    int     // Filas de la matriz de kernel en la caché LRU; 1000 si es 0
+	Workers        int     // Goroutines para calcular las filas de la matriz de kernel
+	Probabilidades bool    // Si es true ajusta el escalado de Platt con validación cruzada
+	Semilla        int64   // Semilla de los pliegues de Platt; si es 0 se usa la hora actual
+
+	Clases       []float64
+	Vectores     ML.Matrix
+	Coeficientes []float64 // α_i·y_i de cada vector de soporte
+	Sesgo        float64
+	PlattA       float64 `json:",omitempty"`
+	PlattB       float64 `json:",omitempty"`
+
+	Iteraciones int `json:"-"` // Iteraciones de SMO del último entrenamiento
+	Aciertos    int `json:"-"` // Filas de kernel servidas por la caché
+	Fallos      int `json:"-"` // Filas de kernel calculadas
+}
+
+// `Fit` entrena la SVM con las dos clases de `train`.
+func (svm *KernelSVM) Fit(train *ML.Dataset) error {
+	if err := ML.CheckTrain(train); err != nil {
+		return err
+	}
+	if err := svm.Kernel.preparar(train.Features()); err != nil {
+		return err
+	}
+	if svm.C <= 0 {
+		svm.C = 1
+	}
+	if svm.Tolerancia <= 0 {
+		svm.Tolerancia = 1e-3
+	}
+	if svm.TamañoCache <= 0 {
+		svm.TamañoCache = 1000
+	}
+
+	vistas := make(map[float64]bool)
+	svm.Clases = nil
+	for _, y := range train.Y {
+		if !vistas[y] {
+			vistas[y] = true
+			svm.Clases = append(svm.Clases, y)
+		}
+	}
+	if len(svm.Clases) != 2 {
+		return fmt.Errorf("svm: kernel SVM needs exactly two classes, got %d", len(svm.Clases))
+	}
+	sort.Float64s(svm.Clases)
+	y := make([]float64, train.Len())
+	for i, etiqueta := range train.Y {
+		y[i] = -1
+		if etiqueta == svm.Clases[1] {
+			y[i] = 1
+		}
+	}
+
+	alfa, rho := svm.smo(train.X, y)
+	svm.Vectores = nil
+	svm.Coeficientes = nil
+	for i, a := range alfa {
+		if a > 0 {
+			svm.Vectores = append(svm.Vectores, append([]float64(nil), train.X[i]...))
+			svm.Coeficientes = append(svm.Coeficientes, a*y[i])
+		}
+	}
+	svm.Sesgo = -rho
+
+	svm.PlattA, svm.PlattB = 0, 0
+	if svm.Probabilidades {
+		return svm.ajustarPlatt(train)
+	}
+	return nil
+}
+
+// Resuelve el dual min ½ αᵀQα - Σα con 0 <= α <= C y yᵀα = 0, donde Q_ij = y_i y_j K_ij,
+// con el SMO de LIBSVM (Fan, Chen y Lin 2005): en cada iteración elige el par que más
+// viola las condiciones KKT usando información de segundo orden, lo optimiza de forma
+// analítica y actualiza el gradiente G = Qα - 1 con las dos filas del kernel.
+// Devuelve α y rho (la decisión es Σ α_i y_i K(x_i, x) - rho).
+func (svm *KernelSVM) smo(x ML.Matrix, y []float64) ([]float64, float64) {
+	n := len(x)
+	cache := newCacheKernel(&svm.Kernel, x, svm.TamañoCache, svm.Workers)
+	diagonal := make([]float64, n)
+	for t := range diagonal {
+		diagonal[t] = svm.Kernel.Evaluar(x[t], x[t])
+	}
+	alfa := make([]float64, n)
+	gradiente := make([]float64, n)
+	for t := range gradiente {
+		gradiente[t] = -1
+	}
+	C := svm.C
+
+	maxIteraciones := svm.MaxIteraciones
+	if maxIteraciones <= 0 {
+		maxIteraciones = int(math.Max(100000, 100*float64(n)))
+	}
+	svm.Iteraciones = 0
+	for ; svm.Iteraciones < maxIteraciones; svm.Iteraciones++ {
+		// i: el índice de I_up con mayor -y_t G_t.
+		gmax, i := math.Inf(-1), -1
+		for t := 0; t < n; t++ {
+			if y[t] > 0 && alfa[t] < C && -gradiente[t] >= gmax {
+				gmax, i = -gradiente[t], t
+			} else if y[t] < 0 && alfa[t] > 0 && gradiente[t] >= gmax {
+				gmax, i = gradiente[t], t
+			}
+		}
+		if i < 0 {
+			break
+		}
+		filaI := cache.fila(i)
+
+		// j: el índice de I_low que más reduce el objetivo con el paso de segundo orden.
+		gmax2, j := math.Inf(-1), -1
+		objetivoMin := math.Inf(1)
+		for t := 0; t < n; t++ {
+			var diferencia float64
+			if y[t] > 0 && alfa[t] > 0 {
+				diferencia = gmax + gradiente[t]
+				gmax2 = math.Max(gmax2, gradiente[t])
+			} else if y[t] < 0 && alfa[t] < C {
+				diferencia = gmax - gradiente[t]
+				gmax2 = math.Max(gmax2, -gradiente[t])
+			} else {
+				continue
+			}
+			if diferencia > 0 {
+				curvatura := diagonal[i] + diagonal[t] - 2*filaI[t]
+				if curvatura <= 0 {
+					curvatura = tau
+				}
+				if objetivo := -diferencia * diferencia / curvatura; objetivo <= objetivoMin {
+					objetivoMin, j = objetivo, t
+				}
+			}
+		}
+		if j < 0 || gmax+gmax2 < svm.Tolerancia {
+			break
+		}
+		filaJ := cache.fila(j) // No desaloja a la fila i: es la usada más recientemente
+
+		// Optimización analítica del par, recortada a la caja [0, C].
+		qij := y[i] * y[j] * filaI[j]
+		viejoI, viejoJ := alfa[i], alfa[j]
+		if y[i] != y[j] {
+			curvatura := math.Max(diagonal[i]+diagonal[j]+2*qij, tau)
+			delta := (-gradiente[i] - gradiente[j]) / curvatura
+			diferencia := alfa[i] - alfa[j]
+			alfa[i] += delta
+			alfa[j] += delta
+			if diferencia > 0 && alfa[j] < 0 {
+				alfa[j], alfa[i] = 0, diferencia
+			} else if diferencia <= 0 && alfa[i] < 0 {
+				alfa[i], alfa[j] = 0, -diferencia
+			}
+			if diferencia > 0 && alfa[i] > C {
+				alfa[i], alfa[j] = C, C-diferencia
+			} else if diferencia <= 0 && alfa[j] > C {
+				alfa[j], alfa[i] = C, C+diferencia
+			}
+		} else {
+			curvatura := math.Max(diagonal[i]+diagonal[j]-2*qij, tau)
+			delta := (gradiente[i] - gradiente[j]) / curvatura
+			suma := alfa[i] + alfa[j]
+			alfa[i] -= delta
+			alfa[j] += delta
+			if suma > C && alfa[i] > C {
+				alfa[i], alfa[j] = C, suma-C
+			} else if suma <= C && alfa[j] < 0 {
+				alfa[j], alfa[i] = 0, suma
+			}
+			if suma > C && alfa[j] > C {
+				alfa[j], alfa[i] = C, suma-C
+			} else if suma <= C && alfa[i] < 0 {
+				alfa[i], alfa[j] = 0, suma
+			}
+		}
+
+		deltaI := (alfa[i] - viejoI) * y[i]
+		deltaJ := (alfa[j] - viejoJ) * y[j]
+		for t := 0; t < n; t++ {
+			gradiente[t] += y[t] * (filaI[t]*deltaI + filaJ[t]*deltaJ)
+		}
+	}
+	svm.Aciertos, svm.Fallos = cache.aciertos, cache.fallos
+
+	// rho: promedio de y_t G_t en los α libres o, si no hay, el punto medio de sus cotas.
+	superior, inferior := math.Inf(1), math.Inf(-1)
+	suma, libres := 0.0, 0
+	for t := 0; t < n; t++ {
+		yg := y[t] * gradiente[t]
+		switch {
+		case alfa[t] >= C && y[t] < 0, alfa[t] <= 0 && y[t] > 0:
+			superior = math.Min(superior, yg)
+		case alfa[t] >= C && y[t] > 0, alfa[t] <= 0 && y[t] < 0:
+			inferior = math.Max(inferior, yg)
+		default:
+			suma += yg
+			libres++
+		}
+	}
+	if libres > 0 {
+		return alfa, suma / float64(libres)
+	}
+	return alfa, (superior + inferior) / 2
+}
+
+// `Decision` devuelve el valor de la función de decisión para `x`.
+func (svm *KernelSVM) Decision(x []float64) float64 {
+	suma := svm.Sesgo
+	for i, vector := range svm.Vectores {
+		suma += svm.Coeficientes[i] * svm.Kernel.Evaluar(vector, x)
+	}
+	return suma
+}
+
+// `Predict` devuelve `Clases[1]` si la decisión es positiva o cero y `Clases[0]` si no.
+func (svm *KernelSVM) Predict(x []float64) float64 {
+	if svm.Decision(x) >= 0 {
+		return svm.Clases[1]
+	}
+	return svm.Clases[0]
+}
+
+// `Probability` devuelve la probabilidad de que `x` sea de `Clases[1]` según el escalado de
+// Platt: 1 / (1 + exp(A·f(x) + B)). Requiere entrenar con `Probabilidades`.
+func (svm *KernelSVM) Probability(x []float64) (float64, error) {
+	if svm.PlattA == 0 && svm.PlattB == 0 {
+		return 0, errors.New("svm: model was trained without probabilities")
+	}
+	return sigmoidPlatt(svm.Decision(x), svm.PlattA, svm.PlattB), nil
+}
+
+// `DumpKernelSVM` guarda el modelo en un archivo JSON.
+func DumpKernelSVM(model *KernelSVM, fileName string) error {
+	return ML.WriteJSON(fileName, model)
+}
+
+// `ReadKernelSVM` carga un modelo guardado con `DumpKernelSVM`.
+func ReadKernelSVM(fileName string) (*KernelSVM, error) {
+	model := &KernelSVM{}
+	if err := ML.ReadJSON(fileName, model); err != nil {
+		return nil, err
+	}
+	if err := model.Kernel.preparar(1); err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	if len(model.Clases) != 2 || len(model.Vectores) != len(model.Coeficientes) {
+		return nil, fmt.Errorf("%s: malformed kernel SVM", fileName)
+	}
+	return model, nil
+}
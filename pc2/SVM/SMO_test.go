@@ -0,0 +1,110 @@
+package SVM
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"pc2/ML"
+)
+
+// Un círculo no es separable por una recta: el kernel RBF lo aprende y el lineal no.
+func TestKernelCircles(t *testing.T) {
+	train, test := ML.Circles(1500, 0, 4).Split(0.8, 4)
+	precisiones := make(map[string]float64)
+	for _, tipo := range []string{LINEAL, POLINOMICO, RBF} {
+		svm := &KernelSVM{Kernel: Kernel{Tipo: tipo, Gamma: 2, Grado: 2, Coef0: 1}, C: 10}
+		if err := svm.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		precisiones[tipo] = ML.Accuracy(svm, test)
+		t.Logf("%-10s accuracy %.4f, %d support vectors, %d iterations, cache %d hits / %d misses",
+			tipo, precisiones[tipo], len(svm.Vectores), svm.Iteraciones, svm.Aciertos, svm.Fallos)
+	}
+	if precisiones[RBF] < 0.95 || precisiones[POLINOMICO] < 0.95 {
+		t.Errorf("non-linear kernels should learn the circle: %v", precisiones)
+	}
+	if precisiones[LINEAL] > 0.8 {
+		t.Errorf("a linear kernel should not separate a circle: %.4f", precisiones[LINEAL])
+	}
+}
+
+// El kernel lineal con SMO y Pegasos resuelven el mismo problema.
+func TestKernelLinealComoPegasos(t *testing.T) {
+	train, test := ML.LinearClassification(1000, 4, 0.02, 5).Split(0.8, 5)
+	svm := &KernelSVM{Kernel: Kernel{Tipo: LINEAL}, C: 1}
+	if err := svm.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	pegasos := &SVM{Semilla: 5}
+	if err := pegasos.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	smo, lineal := ML.Accuracy(svm, test), ML.Accuracy(pegasos, test)
+	t.Logf("accuracy: SMO %.4f, Pegasos %.4f", smo, lineal)
+	if smo < 0.95 || smo < lineal-0.02 {
+		t.Errorf("SMO accuracy %.4f, Pegasos %.4f", smo, lineal)
+	}
+}
+
+// Ni el tamaño de la caché ni la cantidad de workers cambian la solución.
+func TestKernelCacheYWorkers(t *testing.T) {
+	train := ML.Circles(800, 0.05, 6)
+	var resultados []string
+	for _, config := range [][2]int{{1000, 1}, {2, 1}, {16, 4}} {
+		svm := &KernelSVM{Kernel: Kernel{Tipo: RBF, Gamma: 2}, TamañoCache: config[0], Workers: config[1]}
+		if err := svm.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+		resultados = append(resultados, fmt.Sprint(svm.Coeficientes, svm.Sesgo))
+		if config[0] == 2 && svm.Aciertos > svm.Fallos {
+			t.Errorf("a 2-row cache should mostly miss: %d hits, %d misses", svm.Aciertos, svm.Fallos)
+		}
+	}
+	if resultados[0] != resultados[1] || resultados[0] != resultados[2] {
+		t.Error("cache size or workers changed the solution")
+	}
+}
+
+// Las probabilidades de Platt crecen con la decisión y el modelo se recupera del JSON.
+func TestKernelPlattYPersistencia(t *testing.T) {
+	train, test := ML.Circles(600, 0.05, 7).Split(0.8, 7)
+	svm := &KernelSVM{Kernel: Kernel{Tipo: RBF, Gamma: 2}, C: 10, Probabilidades: true, Semilla: 7}
+	if err := svm.Fit(train); err != nil {
+		t.Fatal(err)
+	}
+	if svm.PlattA >= 0 {
+		t.Fatalf("Platt A = %.4f, expected negative", svm.PlattA)
+	}
+	// La probabilidad media de cada clase debe favorecer a la clase correcta.
+	suma := [2]float64{}
+	cantidad := [2]float64{}
+	for i, x := range test.X {
+		p, err := svm.Probability(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		suma[int(test.Y[i])] += p
+		cantidad[int(test.Y[i])]++
+	}
+	t.Logf("mean probability: class 0 %.3f, class 1 %.3f", suma[0]/cantidad[0], suma[1]/cantidad[1])
+	if suma[1]/cantidad[1] < 0.7 || suma[0]/cantidad[0] > 0.3 {
+		t.Error("Platt probabilities do not separate the classes")
+	}
+
+	path := filepath.Join(t.TempDir(), "svm.json")
+	if err := DumpKernelSVM(svm, path); err != nil {
+		t.Fatal(err)
+	}
+	cargado, err := ReadKernelSVM(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range test.X {
+		p1, _ := svm.Probability(x)
+		p2, _ := cargado.Probability(x)
+		if svm.Decision(x) != cargado.Decision(x) || p1 != p2 {
+			t.Fatal("loaded model predicts differently")
+		}
+	}
+}
@@ -0,0 +1,381 @@
+// Package SVM implementa una máquina de vectores de soporte lineal: pérdida hinge con
+// regularización L2 y sesgo, entrenada con Pegasos (Shalev-Shwartz et al. 2007) o con
+// cualquier optimizador del paquete `Optimizadores`. Con más de dos clases entrena un
+// clasificador uno contra el resto por clase, en paralelo según `Workers`. Los ejemplos se
+// recorren como vectores dispersos, así que `FitDisperso` entrena con datos dispersos (por
+// ejemplo de `ML.LoadLibSVM`) en O(nnz) por ejemplo.
+package SVM
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"pc2/ML"
+	"pc2/Optimizadores"
+)
+
+// Estructura `Lineal` con un clasificador binario: la función de decisión es `Pesos`·x + `Sesgo`.
+type Lineal struct {
+	Pesos []float64
+	Sesgo float64
+}
+
+// `Decision` devuelve el valor de la función de decisión para `x`; es positivo para la clase
+// positiva y su valor absoluto es la distancia al hiperplano en unidades del margen.
+func (l *Lineal) Decision(x []float64) float64 {
+	suma := l.Sesgo
+	for j, v := range x {
+		suma += l.Pesos[j] * v
+	}
+	return suma
+}
+
+// `DecisionDisperso` es `Decision` para un ejemplo disperso.
+func (l *Lineal) DecisionDisperso(x ML.Disperso) float64 {
+	return x.Producto(l.Pesos) + l.Sesgo
+}
+
+// Estructura `SVM` con los parámetros de entrenamiento y los clasificadores aprendidos.
+type SVM struct {
+	Lambda  float64 // Regularización L2; 1e-3 si es 0
+	Epocas  int     // Pasadas sobre el dataset; 10 si es 0
+	Workers int     // Goroutines que entrenan los clasificadores uno contra el resto
+	Semilla int64   // Semilla para el orden de los ejemplos; si es 0 se usa la hora actual
+
+	// Optimizador con el que se entrena cada clasificador por descenso de subgradiente en
+	// mini-lotes de `TamañoLote` ejemplos; si es nil se usa Pegasos. Cada clasificador tiene
+	// su propio estado, por lo que el mismo optimizador sirve para los de uno contra el resto.
+	Optimizador Optimizadores.Optimizador `json:"-"`
+	TamañoLote  int                       // Ejemplos por paso con `Optimizador`; 1 si es 0
+
+	// Valores distintos de `Y` vistos en el entrenamiento, ordenados. Con dos clases (por
+	// ejemplo 0/1 o -1/+1) hay un clasificador cuya clase positiva es `Clases[1]`; con más,
+	// `Clasificadores[k]` separa `Clases[k]` del resto.
+	Clases             []float64
+	Clasificadores     []*Lineal
+	EstadosOptimizador []*Optimizadores.Estado `json:",omitempty"` // Uno por clasificador, con `Optimizador`
+
+	datos     *ML.DatasetDisperso // Dataset de entrenamiento en forma dispersa
+	origen    *ML.Dataset         // Dataset del que sale `datos` (nil con `FitDisperso`)
+	etiquetas [][]float64         // Etiquetas -1/+1 de cada clasificador para el dataset de entrenamiento
+}
+
+// `Fit` entrena la SVM con las etiquetas de `train`, que pueden ser índices de clase (0, 1,
+// ...) o -1/+1, durante `Epocas` épocas (ver `Epoca`); para usar validación, callbacks o
+// puntos de control se entrena con `ML.Entrenar`.
+func (svm *SVM) Fit(train *ML.Dataset) error {
+	if svm.Epocas <= 0 {
+		svm.Epocas = 10
+	}
+	_, err := ML.Entrenar(svm, train, ML.Entrenamiento{Epocas: svm.Epocas})
+	return err
+}
+
+// `FitDisperso` entrena la SVM con ejemplos dispersos durante `Epocas` épocas.
+func (svm *SVM) FitDisperso(train *ML.DatasetDisperso) error {
+	if err := ML.CheckTrainDisperso(train); err != nil {
+		return err
+	}
+	if err := svm.preparar(train, false); err != nil {
+		return err
+	}
+	for epoca := 0; epoca < svm.Epocas; epoca++ {
+		svm.epoca(epoca)
+	}
+	return nil
+}
+
+// `Preparar` calcula las clases de `train` y, salvo al retomar un punto de control, crea los
+// clasificadores con pesos en cero.
+func (svm *SVM) Preparar(train *ML.Dataset, reanudar bool) error {
+	if err := svm.preparar(train.Disperso(), reanudar); err != nil {
+		return err
+	}
+	svm.origen = train
+	return nil
+}
+
+// `Preparar` para un dataset disperso, que queda guardado para las épocas siguientes.
+func (svm *SVM) preparar(train *ML.DatasetDisperso, reanudar bool) error {
+	if svm.Lambda <= 0 {
+		svm.Lambda = 1e-3
+	}
+	if svm.Epocas <= 0 {
+		svm.Epocas = 10
+	}
+	if svm.TamañoLote <= 0 {
+		svm.TamañoLote = 1
+	}
+
+	vistas := make(map[float64]bool)
+	var clases []float64
+	for _, y := range train.Y {
+		if !vistas[y] {
+			vistas[y] = true
+			clases = append(clases, y)
+		}
+	}
+	sort.Float64s(clases)
+	if len(clases) < 2 {
+		return errors.New("svm: training labels have a single class")
+	}
+	positivas := clases
+	if len(clases) == 2 {
+		positivas = clases[1:]
+	}
+
+	if reanudar {
+		if len(svm.Clasificadores) != len(positivas) || len(svm.Clasificadores[0].Pesos) != train.Features() {
+			return errors.New("svm: checkpoint does not match the training data")
+		}
+	} else {
+		svm.Semilla = ML.FijarSemilla(svm.Semilla)
+		svm.Clasificadores = make([]*Lineal, len(positivas))
+		for k := range svm.Clasificadores {
+			svm.Clasificadores[k] = &Lineal{Pesos: make([]float64, train.Features())}
+		}
+		svm.EstadosOptimizador = nil
+	}
+	svm.Clases = clases
+	svm.cargar(train)
+	svm.origen = nil
+	if svm.Optimizador != nil && svm.EstadosOptimizador == nil {
+		svm.EstadosOptimizador = make([]*Optimizadores.Estado, len(positivas))
+		for k := range svm.EstadosOptimizador {
+			svm.EstadosOptimizador[k] = Optimizadores.NuevoEstado(svm.Optimizador, nil)
+		}
+	}
+	return nil
+}
+
+// Guarda `train` y las etiquetas -1/+1 de cada clasificador para las épocas siguientes.
+func (svm *SVM) cargar(train *ML.DatasetDisperso) {
+	positivas := svm.Clases
+	if len(svm.Clases) == 2 {
+		positivas = svm.Clases[1:]
+	}
+	svm.datos = train
+	svm.etiquetas = make([][]float64, len(positivas))
+	for k, positiva := range positivas {
+		svm.etiquetas[k] = make([]float64, train.Len())
+		for i, etiqueta := range train.Y {
+			svm.etiquetas[k][i] = -1
+			if etiqueta == positiva {
+				svm.etiquetas[k][i] = 1
+			}
+		}
+	}
+}
+
+// `Epoca` da una pasada sobre `train` con cada clasificador binario (Pegasos o
+// `Optimizador`); los clasificadores uno contra el resto se entrenan en paralelo con
+// `Workers` goroutines. `train` se convierte a la forma dispersa solo si no es el dataset
+// de la época anterior (o de `Preparar`). Devuelve la pérdida de `Loss` sobre `train`.
+func (svm *SVM) Epoca(train *ML.Dataset, epoca int) (float64, error) {
+	if train != svm.origen {
+		if train.Features() != len(svm.Clasificadores[0].Pesos) {
+			return 0, fmt.Errorf("svm: model has %d weights, dataset has %d features", len(svm.Clasificadores[0].Pesos), train.Features())
+		}
+		for _, etiqueta := range train.Y {
+			if i := sort.SearchFloat64s(svm.Clases, etiqueta); i == len(svm.Clases) || svm.Clases[i] != etiqueta {
+				return 0, fmt.Errorf("svm: label %v is not one of the classes %v", etiqueta, svm.Clases)
+			}
+		}
+		svm.cargar(train.Disperso())
+		svm.origen = train
+	}
+	svm.epoca(epoca)
+	return svm.LossDisperso(svm.datos), nil
+}
+
+// Una época de todos los clasificadores sobre el dataset cargado en `datos`.
+func (svm *SVM) epoca(epoca int) {
+	x := svm.datos.X
+	workers := svm.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	siguiente := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range siguiente {
+				rng := ML.RandEpoca(svm.Semilla+int64(k), epoca)
+				if svm.Optimizador != nil {
+					svm.descenso(k, x, rng)
+				} else {
+					svm.pegasos(svm.Clasificadores[k], x, svm.etiquetas[k], epoca, rng)
+				}
+			}
+		}()
+	}
+	for k := range svm.Clasificadores {
+		siguiente <- k
+	}
+	close(siguiente)
+	wg.Wait()
+}
+
+// Una época de Pegasos sobre las etiquetas -1/+1 de `y`. En el paso t (contando las épocas
+// anteriores) la tasa es 1/(λt): los pesos se encogen por (1 - 1/t) y, si el ejemplo viola
+// el margen (y·f(x) < 1), se suma (1/(λt))·y·x. El sesgo es un peso más con entrada
+// constante 1, de modo que se estima igual que el resto (queda levemente regularizado).
+// Durante la época los pesos se guardan como escala·v para que el encogimiento cueste O(1)
+// en lugar de O(d).
+func (svm *SVM) pegasos(clasificador *Lineal, x []ML.Disperso, y []float64, epoca int, rng *rand.Rand) {
+	v := append(append([]float64(nil), clasificador.Pesos...), clasificador.Sesgo) // El último es el sesgo
+	sesgo := len(v) - 1
+	escala := 1.0
+	t := epoca * len(x)
+	for _, i := range rng.Perm(len(x)) {
+		t++
+		tasa := 1 / (svm.Lambda * float64(t))
+
+		margen := v[sesgo] + x[i].Producto(v[:sesgo])
+		margen *= escala * y[i]
+
+		if t == 1 {
+			// (1 - 1/t) = 0: los pesos vuelven a cero.
+			for j := range v {
+				v[j] = 0
+			}
+			escala = 1
+		} else {
+			escala *= 1 - 1/float64(t)
+		}
+		if margen < 1 {
+			paso := tasa * y[i] / escala
+			for n, j := range x[i].Indices {
+				v[j] += paso * x[i].Valores[n]
+			}
+			v[sesgo] += paso
+		}
+		// Reescalar antes de perder precisión.
+		if escala < 1e-9 {
+			for j := range v {
+				v[j] *= escala
+			}
+			escala = 1
+		}
+	}
+
+	for j := range clasificador.Pesos {
+		clasificador.Pesos[j] = escala * v[j]
+	}
+	clasificador.Sesgo = escala * v[sesgo]
+}
+
+// Una época del clasificador `k` minimizando λ/2·‖w‖² + media(max(0, 1 - y·f(x))) con
+// `Optimizador`: el subgradiente de cada mini-lote es λw menos la media de y·x de los
+// ejemplos que violan el margen. A diferencia de Pegasos, el sesgo no se regulariza.
+func (svm *SVM) descenso(k int, x []ML.Disperso, rng *rand.Rand) {
+	clasificador, y := svm.Clasificadores[k], svm.etiquetas[k]
+	sesgo := []float64{clasificador.Sesgo}
+	estado := svm.EstadosOptimizador[k]
+	estado.Asociar(svm.Optimizador, [][]float64{clasificador.Pesos, sesgo})
+	g := [][]float64{make([]float64, len(clasificador.Pesos)), make([]float64, 1)}
+	for _, lote := range ML.Lotes(rng.Perm(len(x)), svm.TamañoLote) {
+		clasificador.Sesgo = sesgo[0]
+		escala := 1 / float64(len(lote))
+		for j, w := range clasificador.Pesos {
+			g[0][j] = svm.Lambda * w
+		}
+		g[1][0] = 0
+		for _, i := range lote {
+			if y[i]*clasificador.DecisionDisperso(x[i]) < 1 {
+				for n, j := range x[i].Indices {
+					g[0][j] -= escala * y[i] * x[i].Valores[n]
+				}
+				g[1][0] -= escala * y[i]
+			}
+		}
+		estado.Paso(g)
+	}
+	clasificador.Sesgo = sesgo[0]
+}
+
+// `Loss` devuelve el objetivo de la SVM sobre `d`, λ/2·‖w‖² + media(max(0, 1 - y·f(x))),
+// promediado entre los clasificadores binarios. Con Pegasos el sesgo es un peso más y entra
+// en ‖w‖²; con `Optimizador` no se regulariza y queda afuera.
+func (svm *SVM) Loss(d *ML.Dataset) float64 {
+	return svm.LossDisperso(d.Disperso())
+}
+
+// `LossDisperso` es `Loss` para un dataset disperso.
+func (svm *SVM) LossDisperso(d *ML.DatasetDisperso) float64 {
+	if d.Len() == 0 || len(svm.Clasificadores) == 0 {
+		return 0
+	}
+	positivas := svm.Clases
+	if len(svm.Clases) == 2 {
+		positivas = svm.Clases[1:]
+	}
+	total := 0.0
+	for k, clasificador := range svm.Clasificadores {
+		norma := 0.0
+		for _, w := range clasificador.Pesos {
+			norma += w * w
+		}
+		if svm.Optimizador == nil {
+			norma += clasificador.Sesgo * clasificador.Sesgo
+		}
+		hinge := 0.0
+		for i, x := range d.X {
+			y := -1.0
+			if d.Y[i] == positivas[k] {
+				y = 1
+			}
+			hinge += math.Max(0, 1-y*clasificador.DecisionDisperso(x))
+		}
+		total += svm.Lambda/2*norma + hinge/float64(d.Len())
+	}
+	return total / float64(len(svm.Clasificadores))
+}
+
+// `DecisionFunction` devuelve las puntuaciones de `x`: una con dos clases (positiva para
+// `Clases[1]`) y una por clase con más.
+func (svm *SVM) DecisionFunction(x []float64) []float64 {
+	puntuaciones := make([]float64, len(svm.Clasificadores))
+	for k, clasificador := range svm.Clasificadores {
+		puntuaciones[k] = clasificador.Decision(x)
+	}
+	return puntuaciones
+}
+
+// `Predict` devuelve la clase de `x`: con dos clases según el signo de la decisión y con más,
+// la del clasificador uno contra el resto con mayor puntuación.
+func (svm *SVM) Predict(x []float64) float64 {
+	return svm.clase(svm.DecisionFunction(x))
+}
+
+// `PredictDisperso` es `Predict` para un ejemplo disperso.
+func (svm *SVM) PredictDisperso(x ML.Disperso) float64 {
+	puntuaciones := make([]float64, len(svm.Clasificadores))
+	for k, clasificador := range svm.Clasificadores {
+		puntuaciones[k] = clasificador.DecisionDisperso(x)
+	}
+	return svm.clase(puntuaciones)
+}
+
+// Clase correspondiente a las puntuaciones de `DecisionFunction`.
+func (svm *SVM) clase(puntuaciones []float64) float64 {
+	if len(svm.Clases) == 2 {
+		if puntuaciones[0] >= 0 {
+			return svm.Clases[1]
+		}
+		return svm.Clases[0]
+	}
+	mejor := 0
+	for k, puntuacion := range puntuaciones {
+		if puntuacion > puntuaciones[mejor] {
+			mejor = k
+		}
+	}
+	return svm.Clases[mejor]
+}
@@ -0,0 +1,166 @@
+package SVM
+
+import (
+	"math"
+	"testing"
+
+	"pc2/ML"
+	"pc2/Optimizadores"
+)
+
+// Entrena y evalúa sobre datos estandarizados, como el comando `pc2 svm`.
+func evaluar(t *testing.T, svm *SVM, dataset *ML.Dataset) float64 {
+	train, test := dataset.Split(0.8, 1)
+	scaler := ML.FitScaler(train)
+	if err := svm.Fit(scaler.Transform(train)); err != nil {
+		t.Fatal(err)
+	}
+	return ML.Accuracy(svm, scaler.Transform(test))
+}
+
+func TestBinary(t *testing.T) {
+	precision := evaluar(t, &SVM{Semilla: 1}, ML.LinearClassification(5000, 5, 0.05, 1))
+	t.Logf("accuracy %.4f (noise 0.05)", precision)
+	if precision < 0.92 {
+		t.Fatalf("accuracy %.4f, expected at least 0.92", precision)
+	}
+}
+
+// Las etiquetas -1/+1 se aceptan tal cual y `Predict` devuelve esos mismos valores.
+func TestSignedLabels(t *testing.T) {
+	dataset := ML.LinearClassification(2000, 3, 0, 2)
+	for i, y := range dataset.Y {
+		dataset.Y[i] = 2*y - 1
+	}
+	svm := &SVM{Semilla: 2}
+	precision := evaluar(t, svm, dataset)
+	if svm.Clases[0] != -1 || svm.Clases[1] != 1 || len(svm.Clasificadores) != 1 {
+		t.Fatalf("classes %v with %d classifiers", svm.Clases, len(svm.Clasificadores))
+	}
+	if precision < 0.97 {
+		t.Fatalf("accuracy %.4f, expected at least 0.97", precision)
+	}
+	x := dataset.X[0]
+	if (svm.DecisionFunction(x)[0] >= 0) != (svm.Predict(x) == 1) {
+		t.Fatal("Predict disagrees with the sign of DecisionFunction")
+	}
+}
+
+// Uno contra el resto: un clasificador por clase y el mismo resultado con varios workers.
+func TestOneVsRest(t *testing.T) {
+	dataset := ML.Blobs(3000, 2, 4, 0.1, 3)
+	var precisiones []float64
+	for _, workers := range []int{1, 4} {
+		svm := &SVM{Workers: workers, Semilla: 3}
+		precisiones = append(precisiones, evaluar(t, svm, dataset))
+		if len(svm.Clasificadores) != 4 {
+			t.Fatalf("%d classifiers for 4 classes", len(svm.Clasificadores))
+		}
+	}
+	t.Logf("accuracy %.4f", precisiones[0])
+	if precisiones[0] != precisiones[1] {
+		t.Fatalf("workers change the result: %.4f vs %.4f", precisiones[0], precisiones[1])
+	}
+	if precisiones[0] < 0.9 {
+		t.Fatalf("accuracy %.4f, expected at least 0.9", precisiones[0])
+	}
+}
+
+// Con un optimizador en lugar de Pegasos la precisión es similar, también en uno contra el resto.
+func TestOptimizador(t *testing.T) {
+	adam := &Optimizadores.Adam{Tasa: Optimizadores.Coseno{Inicial: 0.05, Minima: 1e-3, Pasos: 1000}}
+	precision := evaluar(t, &SVM{Optimizador: adam, TamañoLote: 16, Semilla: 1}, ML.LinearClassification(5000, 5, 0.05, 1))
+	t.Logf("accuracy %.4f with Adam", precision)
+	if precision < 0.92 {
+		t.Fatalf("accuracy %.4f with Adam, expected at least 0.92", precision)
+	}
+
+	momento := &Optimizadores.SGD{Tasa: Optimizadores.Constante(0.01), Momento: 0.9}
+	precision = evaluar(t, &SVM{Optimizador: momento, TamañoLote: 8, Workers: 4, Semilla: 3}, ML.Blobs(3000, 2, 4, 0.1, 3))
+	if precision < 0.95 {
+		t.Fatalf("one-vs-rest accuracy %.4f with momentum, expected at least 0.95", precision)
+	}
+}
+
+// `FitDisperso` entrena con ejemplos dispersos y da el mismo modelo que `Fit` con las filas
+// densas.
+func TestDisperso(t *testing.T) {
+	train, test := ML.SparseClassification(20000, 1000, 20, 0, 3).Split(0.8, 3)
+	svm := &SVM{Lambda: 1e-4, Semilla: 3}
+	if err := svm.FitDisperso(train); err != nil {
+		t.Fatal(err)
+	}
+	precision := ML.AccuracyDisperso(svm, test)
+	t.Logf("sparse accuracy %.4f", precision)
+	if precision < 0.9 {
+		t.Errorf("sparse accuracy %.4f, expected at least 0.9", precision)
+	}
+
+	pequeño := ML.SparseClassification(500, 50, 5, 0, 4)
+	disperso := &SVM{Semilla: 4, Optimizador: &Optimizadores.Adam{}}
+	denso := &SVM{Semilla: 4, Optimizador: &Optimizadores.Adam{}}
+	if err := disperso.FitDisperso(pequeño); err != nil {
+		t.Fatal(err)
+	}
+	if err := denso.Fit(pequeño.Denso()); err != nil {
+		t.Fatal(err)
+	}
+	for j, w := range denso.Clasificadores[0].Pesos {
+		if disperso.Clasificadores[0].Pesos[j] != w {
+			t.Fatalf("weight %d: sparse %v, dense %v", j, disperso.Clasificadores[0].Pesos[j], w)
+		}
+	}
+}
+
+// `Epoca` entrena con el dataset que recibe aunque no sea el de `Preparar`, y rechaza uno con
+// otra cantidad de características o con clases que no vio.
+func TestEpoca(t *testing.T) {
+	a, b := ML.LinearClassification(2000, 4, 0.05, 3).Split(0.5, 3)
+	entrenar := func(preparar *ML.Dataset) (*SVM, float64) {
+		svm := &SVM{Semilla: 3}
+		if err := svm.Preparar(preparar, false); err != nil {
+			t.Fatal(err)
+		}
+		perdida, err := svm.Epoca(b, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return svm, perdida
+	}
+	esperado, perdidaEsperada := entrenar(b)
+	svm, perdida := entrenar(a)
+	if perdida != perdidaEsperada || perdida != svm.Loss(b) {
+		t.Fatalf("loss %v, expected %v", perdida, perdidaEsperada)
+	}
+	for j, w := range svm.Clasificadores[0].Pesos {
+		if w != esperado.Clasificadores[0].Pesos[j] {
+			t.Fatalf("weights %v, expected %v", svm.Clasificadores[0].Pesos, esperado.Clasificadores[0].Pesos)
+		}
+	}
+
+	if _, err := svm.Epoca(ML.LinearClassification(10, 3, 0, 4), 1); err == nil {
+		t.Error("Epoca accepted a dataset with another number of features")
+	}
+	otra := b.Subset([]int{0, 1, 2})
+	otra.Y = []float64{0, 1, 2}
+	if _, err := svm.Epoca(otra, 1); err == nil {
+		t.Error("Epoca accepted a label that was not in Preparar")
+	}
+}
+
+// El objetivo de Pegasos regulariza el sesgo; el de `Optimizador`, no.
+func TestLossSesgo(t *testing.T) {
+	d := &ML.Dataset{X: [][]float64{{2}, {-2}}, Y: []float64{1, 0}}
+	for _, svm := range []*SVM{{Lambda: 0.5}, {Lambda: 0.5, Optimizador: &Optimizadores.SGD{}}} {
+		svm.Clases = []float64{0, 1}
+		svm.Clasificadores = []*Lineal{{Pesos: []float64{1}, Sesgo: 2}}
+		// Márgenes 4 y 0: la pérdida hinge media es 0.5.
+		esperado := 0.5/2*(1+4) + 0.5
+		if svm.Optimizador != nil {
+			esperado = 0.5/2*1 + 0.5
+		}
+		if perdida := svm.Loss(d); math.Abs(perdida-esperado) > 1e-12 {
+			t.Errorf("optimizer %v: loss %v, expected %v", svm.Optimizador != nil, perdida, esperado)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"pc2/FiltradoColaborativo"
+)
+
+// Subcomando `cf`: calcula el índice de vecinos del filtrado colaborativo basado en usuarios
+// (o en productos con `-items`, o entrena una factorización de matrices con `-mf`), mide el RMSE sobre el 20% de prueba (el recall de las
+// recomendaciones con `-implicit`), predice la calificación de un producto para todos los
+// usuarios y recomienda productos a un usuario.
+func runCF(args []string) int {
+	flags := flag.NewFlagSet("cf", flag.ContinueOnError)
+	users := flags.Int("users", 1000, "cantidad de usuarios")
+	products := flags.Int("products", 100, "cantidad de productos")
+	density := flags.Float64("density", 0.2, "fracción de pares usuario-producto calificados")
+	product := flags.Int("product", 5, "producto cuya calificación se predice")
+	user := flags.Int("user", 0, "usuario al que se recomiendan productos")
+	top := flags.Int("top", 10, "cantidad de productos recomendados")
+	k := flags.Int("k", 50, "vecinos por usuario (o producto) del índice")
+	similarity := flags.String("similarity", "", "similitud: coseno, pearson o coseno_ajustado (por defecto, pearson por usuarios y coseno_ajustado por productos; coseno con -implicit)")
+	shrink := flags.Float64("shrink", 0, "encogimiento de las similitudes con pocas calificaciones en común")
+	items := flags.Bool("items", false, "filtrado basado en productos en lugar de usuarios")
+	implicit := flags.Bool("implicit", false, "usar vistas, clics y compras sintéticas en lugar de calificaciones")
+	events := flags.Int("events", 20, "eventos por usuario con -implicit")
+	alpha := flags.Float64("alpha", 1, "escala de la confianza 1 + alpha·log(1 + pesos) con -implicit")
+	mf := flags.String("mf", "", "factorización de matrices en lugar de vecinos: als o sgd")
+	factors := flags.Int("factors", 10, "factores latentes con -mf")
+	lambda := flags.Float64("lambda", 0.05, "regularización con -mf")
+	rate := flags.Float64("rate", 0.01, "tasa de aprendizaje con -mf sgd")
+	epochs := flags.Int("epochs", 10, "épocas con -mf")
+	workers := flags.Int("workers", 4, "goroutines del índice o del entrenamiento (1: secuencial)")
+	seed := flags.Int64("seed", 0, "semilla aleatoria (0: hora actual)")
+	quiet := flags.Bool("quiet", false, "no mostrar la predicción de cada usuario")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	fmt.Printf("Creando dataset con %d usuarios y %d productos...\n", *users, *products)
+	var datos *FiltradoColaborativo.Calificaciones
+	if *implicit {
+		eventos := FiltradoColaborativo.EventosAleatorios(*users, *products, *events, 10, *seed)
+		var err error
+		if datos, err = FiltradoColaborativo.CalificacionesImplicitas(eventos, FiltradoColaborativo.PESOS_IMPLICITOS, *alpha); err != nil {
+			return fail("cf", err)
+		}
+	} else {
+		datos = FiltradoColaborativo.CalificacionesAleatorias(*users, *products, *density, *seed)
+	}
+	train, test := datos.Split(0.8, *seed)
+	fmt.Printf("Dataset creado con %d calificaciones de entrenamiento y %d de prueba.\n", train.Len(), test.Len())
+
+	start := time.Now()
+	var modelo FiltradoColaborativo.Recomendador = &FiltradoColaborativo.BasadoEnUsuarios{Similitud: *similarity, K: *k, Encogimiento: *shrink, Workers: *workers}
+	factorizacion := &FiltradoColaborativo.Factorizacion{
+		Factores:        *factors,
+		Regularizacion:  *lambda,
+		Solver:          *mf,
+		TasaAprendizaje: *rate,
+		Epocas:          *epochs,
+		Workers:         *workers,
+		Semilla:         *seed,
+	}
+	switch {
+	case *mf != "":
+		modelo = factorizacion
+	case *items:
+		modelo = &FiltradoColaborativo.BasadoEnProductos{Similitud: *similarity, K: *k, Encogimiento: *shrink, Workers: *workers}
+	}
+	if err := modelo.Fit(train); err != nil {
+		return fail("cf", err)
+	}
+	if *mf != "" {
+		fmt.Printf("Factorización con %s entrenada en %s (RMSE de entrenamiento %.4f).\n", *mf, time.Since(start), factorizacion.Historial[len(factorizacion.Historial)-1])
+	} else {
+		fmt.Printf("Índice de %d vecinos calculado en %s.\n", *k, time.Since(start))
+	}
+	if *implicit {
+		fmt.Printf("Recall de las %d primeras recomendaciones: %.2f%%\n", *top, FiltradoColaborativo.Recall(modelo, test, *top)*100)
+	} else {
+		fmt.Printf("RMSE de prueba: %.4f\n", FiltradoColaborativo.RMSE(modelo, test))
+	}
+
+	if !*quiet {
+		for u := 0; u < train.NumUsuarios; u++ {
+			fmt.Printf("Usuario %d predice una calificación de %.2f para el producto %d\n", u, modelo.PredictRating(u, *product), *product)
+		}
+	}
+	fmt.Printf("Recomendaciones para el usuario %d:\n", *user)
+	for _, r := range modelo.RecommendTopN(*user, *top) {
+		fmt.Printf("  producto %d: %.2f\n", r.Producto, r.Puntuacion)
+	}
+	if *mf != "" {
+		// Fold-in: el mismo usuario como usuario nuevo, sin reentrenar.
+		calificaciones := make(map[int]float64)
+		fila := train.Usuario(*user)
+		for n, p := range fila.Indices {
+			calificaciones[p] = fila.Valores[n]
+		}
+		nuevo, err := factorizacion.AgregarUsuario(calificaciones)
+		if err != nil {
+			return fail("cf", err)
+		}
+		fmt.Printf("Recomendaciones para un usuario nuevo con las %d calificaciones del usuario %d:\n", len(calificaciones), *user)
+		for _, r := range factorizacion.RecommendTopN(nuevo, *top) {
+			fmt.Printf("  producto %d: %.2f\n", r.Producto, r.Puntuacion)
+		}
+	}
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+	return 0
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"pc2/ML"
+	"pc2/SVM"
+)
+
+// Subcomando `ksvm`: entrena una SVM con kernel (SMO) sobre el archivo del SIS o, si no se
+// indica `-data`, sobre puntos dentro y fuera de un círculo, que ninguna recta separa.
+func runKernelSVM(args []string) int {
+	flags := flag.NewFlagSet("ksvm", flag.ContinueOnError)
+	opts := linearFlags(flags, 5000)
+	kernel := flags.String("kernel", SVM.RBF, "kernel: lineal, rbf o polinomico")
+	gamma := flags.Float64("gamma", 0, "parámetro γ de los kernels rbf y polinomico (0: 1/características)")
+	degree := flags.Int("degree", 3, "grado del kernel polinomico")
+	coef0 := flags.Float64("coef0", 1, "término independiente del kernel polinomico")
+	c := flags.Float64("C", 1, "penalización de las violaciones del margen")
+	cacheRows := flags.Int("cache", 1000, "filas de la matriz de kernel en la caché")
+	proba := flags.Bool("proba", false, "ajustar probabilidades de Platt")
+	modelPath := flags.String("model", "", "archivo JSON donde se guarda el modelo (opcional)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	circles := func(n, features int, noise float64, seed int64) *ML.Dataset { return ML.Circles(n, noise, seed) }
+	train, test, clases, err := loadLinear(opts, circles)
+	if err != nil {
+		return fail("ksvm", err)
+	}
+
+	start := time.Now()
+	fmt.Printf("Entrenando la SVM con kernel %s %s...\n", *kernel, modo(*opts.workers))
+	svm := &SVM.KernelSVM{
+		Kernel:         SVM.Kernel{Tipo: *kernel, Gamma: *gamma, Grado: *degree, Coef0: *coef0},
+		C:              *c,
+		TamañoCache:    *cacheRows,
+		Workers:        *opts.workers,
+		Probabilidades: *proba,
+		Semilla:        *opts.seed,
+	}
+	if err := svm.Fit(train); err != nil {
+		return fail("ksvm", err)
+	}
+	fmt.Println("Entrenamiento completado.")
+	fmt.Printf("%d vectores de soporte, %d iteraciones de SMO, caché: %d aciertos y %d fallos\n",
+		len(svm.Vectores), svm.Iteraciones, svm.Aciertos, svm.Fallos)
+	fmt.Printf("Precisión del modelo: %.2f%%\n", ML.Accuracy(svm, test)*100)
+	if *proba {
+		for i := 0; i < 5 && i < test.Len(); i++ {
+			p, _ := svm.Probability(test.X[i])
+			fmt.Printf("Registro %d: P(%s) = %.3f, etiqueta real: %s\n", i, className(svm.Clases[1], clases), p, className(test.Y[i], clases))
+		}
+	}
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+
+	if *modelPath != "" {
+		if err := SVM.DumpKernelSVM(svm, *modelPath); err != nil {
+			return fail("ksvm", err)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pc2/ML"
+	"pc2/SGD"
+	"pc2/SVM"
+)
+
+// Opciones de datos de los modelos lineales: el archivo del SIS o un dataset sintético.
+type dataOptions struct {
+	options
+	path    *string
+	columns *string
+	label   *int
+	noise   *float64
+}
+
+// Registra las opciones comunes (con `n` ejemplos sintéticos por defecto) y las de datos.
+func linearFlags(flags *flag.FlagSet, n int) dataOptions {
+	return dataOptions{
+		options: commonFlags(flags, n, 4, 4),
+		path:    flags.String("data", "", "archivo CSV del SIS, p. ej. Afiliados_activos_DM_SIS.csv (por defecto, datos sintéticos)"),
+		columns: flags.String("columns", "", "columnas de características del CSV, p. ej. \"1,2,3,4\" (por defecto, las de tp.go)"),
+		label:   flags.Int("label", ML.SIS_LABEL, "columna de la etiqueta del CSV"),
+		noise:   flags.Float64("noise", 0.05, "fracción de etiquetas invertidas en los datos sintéticos"),
+	}
+}
+
+// Carga el archivo del SIS (con las columnas de tp.go) o genera un dataset sintético con
+// `synthetic`, lo divide y estandariza las columnas con las estadísticas de entrenamiento.
+// Devuelve además el nombre de cada clase si los datos vienen de un CSV.
+func loadLinear(opts dataOptions, synthetic func(n, features int, noise float64, seed int64) *ML.Dataset) (*ML.Dataset, *ML.Dataset, []string, error) {
+	var dataset *ML.Dataset
+	var clases []string
+	if *opts.path != "" {
+		features, err := parseColumns(*opts.columns)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		fmt.Printf("Creando dataset desde %s...\n", *opts.path)
+		if dataset, clases, err = ML.LoadCSV(*opts.path, features, *opts.label); err != nil {
+			return nil, nil, nil, err
+		}
+	} else {
+		fmt.Printf("Creando dataset de %d registros...\n", *opts.n)
+		dataset = synthetic(*opts.n, *opts.features, *opts.noise, *opts.seed)
+	}
+	fmt.Println("Dataset creado con éxito.")
+
+	train, test := dataset.Split(0.8, *opts.seed)
+	scaler := ML.FitScaler(train)
+	fmt.Printf("Dataset dividido en %d ejemplos de entrenamiento y %d ejemplos de prueba.\n", train.Len(), test.Len())
+	return scaler.Transform(train), scaler.Transform(test), clases, nil
+}
+
+// Interpreta la opción `-columns`; si está vacía devuelve las columnas del SIS que usa tp.go.
+func parseColumns(spec string) ([]int, error) {
+	if spec == "" {
+		return ML.SIS_FEATURES, nil
+	}
+	var columns []int
+	for _, field := range strings.Split(spec, ",") {
+		column, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || column < 0 {
+			return nil, fmt.Errorf("invalid column %q", field)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// Nombre de la clase `clase`: el del CSV si lo hay o el valor numérico.
+func className(clase float64, clases []string) string {
+	if clases != nil {
+		return clases[int(clase)]
+	}
+	return fmt.Sprint(clase)
+}
+
+// Subcomando `svm`: entrena la SVM lineal (con Pegasos o con `-optimizer`) sobre el archivo
+// de afiliados del SIS o, si no se indica `-data`, sobre un dataset sintético separable.
+func runSVM(args []string) int {
+	flags := flag.NewFlagSet("svm", flag.ContinueOnError)
+	opts := linearFlags(flags, 1000000)
+	lambda := flags.Float64("lambda", 1e-3, "regularización L2")
+	training := trainingFlags(flags, 10)
+	optimizer := optimizerFlags(flags, "", 0.01)
+	batch := flags.Int("batch", 1, "ejemplos por paso del optimizador")
+	sparse := sparseFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *sparse.path != "" {
+		return trainSparse("svm", sparse, *opts.seed, true, func(train *ML.DatasetDisperso) (ML.ModeloDisperso, error) {
+			optimizador, err := optimizer.build(trainingSteps(*training.epochs, train.Len(), *batch))
+			return &SVM.SVM{Lambda: *lambda, Epocas: *training.epochs, Workers: *opts.workers, Semilla: *opts.seed, Optimizador: optimizador, TamañoLote: *batch}, err
+		})
+	}
+	train, test, clases, err := loadLinear(opts, ML.LinearClassification)
+	if err != nil {
+		return fail("svm", err)
+	}
+	optimizador, err := optimizer.build(trainingSteps(*training.epochs, train.Len(), *batch))
+	if err != nil {
+		return fail("svm", err)
+	}
+
+	start := time.Now()
+	fmt.Printf("Entrenando el modelo SVM %s...\n", modo(*opts.workers))
+	svm := &SVM.SVM{
+		Lambda:      *lambda,
+		Epocas:      *training.epochs,
+		Workers:     *opts.workers,
+		Semilla:     *opts.seed,
+		Optimizador: optimizador,
+		TamañoLote:  *batch,
+	}
+	if err := training.fit(svm, train, *opts.seed, "precisión", ML.Accuracy); err != nil {
+		return fail("svm", err)
+	}
+	fmt.Println("Entrenamiento completado.")
+	desde := len(svm.Clases) - len(svm.Clasificadores) // Con dos clases solo está el de `Clases[1]`
+	for k, clasificador := range svm.Clasificadores {
+		fmt.Printf("Clase %s: pesos %v, sesgo %.4f\n", className(svm.Clases[desde+k], clases), clasificador.Pesos, clasificador.Sesgo)
+	}
+	fmt.Printf("Precisión del modelo: %.2f%%\n", ML.Accuracy(svm, test)*100)
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+	return 0
+}
+
+// Subcomando `sgd`: entrena un modelo lineal de dos clases con la estrategia de SGD en
+// paralelo elegida, para comparar sus tiempos y precisión.
+func runSGD(args []string) int {
+	flags := flag.NewFlagSet("sgd", flag.ContinueOnError)
+	opts := linearFlags(flags, 1000000)
+	strategy := flags.String("strategy", SGD.HOGWILD, "estrategia: mutex, hogwild, promedio o minibatch")
+	loss := flags.String("loss", SGD.HINGE, "pérdida: hinge o logistica")
+	rate := flags.Float64("rate", 0.01, "tasa de aprendizaje inicial")
+	lambda := flags.Float64("lambda", 1e-4, "regularización L2")
+	epochs := flags.Int("epochs", 10, "épocas de entrenamiento")
+	batch := flags.Int("batch", 64, "ejemplos por lote (minibatch)")
+	syncSteps := flags.Int("sync", 1000, "pasos locales entre promedios (promedio)")
+	sparse := sparseFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	config := SGD.Config{
+		Perdida:         *loss,
+		Estrategia:      *strategy,
+		Workers:         *opts.workers,
+		Epocas:          *epochs,
+		TasaAprendizaje: *rate,
+		Lambda:          *lambda,
+		TamañoLote:      *batch,
+		Sincronizar:     *syncSteps,
+		Semilla:         *opts.seed,
+	}
+	if *sparse.path != "" {
+		return trainSparse("sgd", sparse, *opts.seed, true, func(*ML.DatasetDisperso) (ML.ModeloDisperso, error) {
+			return &SGD.Lineal{Config: config}, nil
+		})
+	}
+	train, test, clases, err := loadLinear(opts, ML.LinearClassification)
+	if err != nil {
+		return fail("sgd", err)
+	}
+
+	start := time.Now()
+	fmt.Printf("Entrenando con la estrategia %s %s...\n", *strategy, modo(*opts.workers))
+	modelo := &SGD.Lineal{Config: config}
+	if err := modelo.Fit(train); err != nil {
+		return fail("sgd", err)
+	}
+	fmt.Println("Entrenamiento completado.")
+	fmt.Printf("Clase %s: pesos %v, sesgo %.4f\n", className(modelo.Clases[1], clases), modelo.Pesos, modelo.Sesgo)
+	fmt.Printf("Precisión del modelo: %.2f%%\n", ML.Accuracy(modelo, test)*100)
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+	return 0
+}
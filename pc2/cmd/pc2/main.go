@@ -0,0 +1,115 @@
+// Comando `pc2`: ejecuta los algoritmos de la PC2 (antes un programa secuencial y otro
+// concurrente por algoritmo) sobre datos sintéticos. La opción `-workers` elige la
+// versión: 1 entrena secuencialmente y más de 1 reparte el trabajo entre goroutines.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"pc2/ML"
+)
+
+// Subcomandos disponibles, con su descripción para la ayuda.
+var commands = []struct {
+	name        string
+	description string
+	run         func(args []string) int
+}{
+	{"svm", "entrena una SVM lineal con Pegasos", runSVM},
+	{"ksvm", "entrena una SVM con kernel (SMO) y, opcionalmente, probabilidades de Platt", runKernelSVM},
+	{"sgd", "entrena un modelo lineal con SGD en paralelo (mutex, hogwild, promedio, minibatch)", runSGD},
+	{"dl", "entrena la red neuronal profunda (DL)", runDL},
+	{"mlp", "entrena un perceptrón multicapa (ReLU/tanh/sigmoide, softmax, entropía cruzada o MSE)", runMLP},
+	{"redes", "entrena la red neuronal de una capa oculta", runRedes},
+	{"mbfl", "entrena el modelo basado en factores latentes (MBFL)", runMBFL},
+	{"rf", "entrena un bosque aleatorio", runRF},
+	{"arbol", "entrena un árbol de decisión", runArbol},
+	{"cf", "predice calificaciones con filtrado colaborativo", runCF},
+	{"libsvm", "guarda un dataset sintético disperso en formato libsvm/libFM", runLibSVM},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, command := range commands {
+		if command.name == os.Args[1] {
+			os.Exit(command.run(os.Args[2:]))
+		}
+	}
+	if os.Args[1] != "help" && os.Args[1] != "-h" && os.Args[1] != "--help" {
+		fmt.Fprintf(os.Stderr, "pc2: unknown command %q\n\n", os.Args[1])
+	}
+	usage()
+	os.Exit(2)
+}
+
+// Imprime la ayuda general del comando.
+func usage() {
+	fmt.Fprintln(os.Stderr, "uso: pc2 <comando> [opciones]")
+	fmt.Fprintln(os.Stderr, "\ncomandos:")
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %-6s %s\n", command.name, command.description)
+	}
+	fmt.Fprintln(os.Stderr, "\nUse \"pc2 <comando> -h\" para ver las opciones de cada comando.")
+}
+
+// Opciones comunes a todos los subcomandos.
+type options struct {
+	n        *int
+	features *int
+	workers  *int
+	seed     *int64
+}
+
+// Registra las opciones comunes con los valores por defecto del programa original.
+func commonFlags(flags *flag.FlagSet, n, features, workers int) options {
+	return options{
+		n:        flags.Int("n", n, "cantidad de ejemplos del dataset sintético"),
+		features: flags.Int("features", features, "cantidad de características"),
+		workers:  flags.Int("workers", workers, "goroutines de entrenamiento (1: secuencial)"),
+		seed:     flags.Int64("seed", 0, "semilla aleatoria (0: hora actual)"),
+	}
+}
+
+// Describe el modo de entrenamiento elegido con `-workers`.
+func modo(workers int) string {
+	if workers <= 1 {
+		return "secuencialmente"
+	}
+	return fmt.Sprintf("concurrentemente (%d goroutines)", workers)
+}
+
+// Divide el dataset, entrena `model` y muestra la precisión sobre la parte de prueba
+// calculada con `metric`, como hacían los programas originales. Si `training` no es nil el
+// modelo se entrena con esas opciones del bucle de entrenamiento.
+func trainAndEvaluate(name string, model ML.Model, training *trainingOptions, dataset *ML.Dataset, seed int64, metric func(ML.Model, *ML.Dataset) float64) int {
+	ratioEntrenamiento := 0.8
+	train, test := dataset.Split(ratioEntrenamiento, seed)
+	fmt.Printf("Dataset dividido en %d ejemplos de entrenamiento y %d ejemplos de prueba.\n", train.Len(), test.Len())
+
+	start := time.Now()
+	fit := model.Fit
+	if iterativo, ok := model.(ML.Iterativo); ok && training != nil {
+		fit = func(train *ML.Dataset) error { return training.fit(iterativo, train, seed, "precisión", metric) }
+	}
+	if err := fit(train); err != nil {
+		return fail(name, err)
+	}
+	fmt.Println("Entrenamiento completado.")
+
+	precision := metric(model, test)
+	fmt.Printf("Precisión del modelo: %.2f%%\n", precision*100)
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+	return 0
+}
+
+// Imprime un error del subcomando `command` y devuelve el código de salida.
+func fail(command string, err error) int {
+	fmt.Fprintf(os.Stderr, "pc2 %s: %v\n", command, err)
+	return 1
+}
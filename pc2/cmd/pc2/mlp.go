@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pc2/DL"
+	"pc2/ML"
+)
+
+// Subcomando `mlp`: entrena un perceptrón multicapa sobre el archivo del SIS o, si no se
+// indica `-data`, sobre nubes gaussianas de `-classes` clases (o círculos con `-classes 0`).
+func runMLP(args []string) int {
+	flags := flag.NewFlagSet("mlp", flag.ContinueOnError)
+	opts := linearFlags(flags, 5000)
+	layers := flags.String("layers", "16,16", "neuronas de cada capa oculta, separadas por comas")
+	activation := flags.String("activation", DL.RELU, "activación de las capas ocultas: relu, tanh, sigmoid o lineal")
+	output := flags.String("output", "", "activación de salida: softmax, sigmoid, tanh o lineal (por defecto según la pérdida)")
+	loss := flags.String("loss", DL.ENTROPIA_CRUZADA, "pérdida: entropia o mse")
+	init := flags.String("init", "", "inicialización: xavier o he (por defecto según la activación)")
+	training := trainingFlags(flags, 30)
+	batch := flags.Int("batch", 32, "ejemplos por mini-lote, repartidos entre los workers")
+	optimizer := optimizerFlags(flags, "sgd", 0.05)
+	classes := flags.Int("classes", 3, "clases de los datos sintéticos (0: círculos)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	ocultas, err := parseLayers(*layers)
+	if err != nil {
+		return fail("mlp", err)
+	}
+	synthetic := func(n, features int, noise float64, seed int64) *ML.Dataset {
+		if *classes <= 0 {
+			return ML.Circles(n, noise, seed)
+		}
+		return ML.Blobs(n, features, *classes, 0.3, seed)
+	}
+	train, test, _, err := loadLinear(opts, synthetic)
+	if err != nil {
+		return fail("mlp", err)
+	}
+
+	optimizador, err := optimizer.build(trainingSteps(*training.epochs, train.Len(), *batch))
+	if err != nil {
+		return fail("mlp", err)
+	}
+
+	start := time.Now()
+	fmt.Printf("Entrenando el perceptrón multicapa %v %s...\n", ocultas, modo(*opts.workers))
+	red := &DL.MLP{
+		Ocultas:        ocultas,
+		Activacion:     *activation,
+		Salida:         *output,
+		Perdida:        *loss,
+		Inicializacion: *init,
+		Epocas:         *training.epochs,
+		TamañoLote:     *batch,
+		Optimizador:    optimizador,
+		Workers:        *opts.workers,
+		Semilla:        *opts.seed,
+	}
+	metric, metricName := ML.Accuracy, "precisión"
+	if *loss == DL.MSE {
+		metric, metricName = ML.RMSE, "RMSE"
+	}
+	if err := training.fit(red, train, *opts.seed, metricName, metric); err != nil {
+		return fail("mlp", err)
+	}
+	fmt.Println("Entrenamiento completado.")
+	fmt.Printf("Pérdida de entrenamiento: %.4f, de prueba: %.4f\n", red.Loss(train), red.Loss(test))
+	if *loss == DL.MSE {
+		fmt.Printf("RMSE de prueba: %.4f\n", ML.RMSE(red, test))
+	} else {
+		fmt.Printf("Precisión del modelo: %.2f%%\n", ML.Accuracy(red, test)*100)
+	}
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+	return 0
+}
+
+// Interpreta la opción `-layers`; una cadena vacía es una red sin capas ocultas.
+func parseLayers(spec string) ([]int, error) {
+	var layers []int
+	for _, field := range strings.Split(spec, ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid layer size %q", field)
+		}
+		layers = append(layers, size)
+	}
+	return layers, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"pc2/DL"
+	"pc2/MBFL"
+	"pc2/ML"
+	"pc2/RandomForests"
+	"pc2/RedesNeuronales"
+)
+
+// Subcomando `dl`: entrena la red neuronal y la evalúa sobre el 20% de prueba.
+func runDL(args []string) int {
+	flags := flag.NewFlagSet("dl", flag.ContinueOnError)
+	opts := commonFlags(flags, 1000, 10, 4)
+	hidden := flags.Int("hidden", 5, "neuronas de la capa oculta")
+	training := trainingFlags(flags, 10)
+	rate := flags.Float64("rate", 0.01, "tasa de aprendizaje")
+	batch := flags.Int("batch", 32, "ejemplos por mini-lote, repartidos entre los workers")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	fmt.Printf("Creando dataset con %d ejemplos y %d características...\n", *opts.n, *opts.features)
+	dataset := ML.RandomClassification(*opts.n, *opts.features, 10, *opts.seed)
+	fmt.Println("Dataset creado con éxito.")
+
+	red := &DL.RedNeuronal{Ocultas: *hidden, Epocas: *training.epochs, TasaAprendizaje: *rate, TamañoLote: *batch, Workers: *opts.workers, Semilla: *opts.seed}
+	return trainAndEvaluate("dl", red, &training, dataset, *opts.seed, ML.Accuracy)
+}
+
+// Subcomando `redes`: entrena la red neuronal de una capa oculta sobre un millón de registros.
+func runRedes(args []string) int {
+	flags := flag.NewFlagSet("redes", flag.ContinueOnError)
+	opts := commonFlags(flags, 1000000, 4, 10)
+	hidden := flags.Int("hidden", 5, "neuronas de la capa oculta")
+	training := trainingFlags(flags, 10)
+	rate := flags.Float64("rate", 0.01, "tasa de aprendizaje")
+	batch := flags.Int("batch", 32, "ejemplos por mini-lote, repartidos entre los workers")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	fmt.Printf("Creando dataset de %d registros...\n", *opts.n)
+	dataset := ML.RandomClassification(*opts.n, *opts.features, 1, *opts.seed)
+	fmt.Println("Dataset creado con éxito.")
+
+	start := time.Now()
+	fmt.Printf("Entrenando la red neuronal %s...\n", modo(*opts.workers))
+	red := &RedesNeuronales.Red{Ocultas: *hidden, Epocas: *training.epochs, TasaAprendizaje: *rate, TamañoLote: *batch, Workers: *opts.workers, Semilla: *opts.seed}
+	if err := training.fit(red, dataset, *opts.seed, "precisión", ML.Accuracy); err != nil {
+		return fail("redes", err)
+	}
+	fmt.Println("Entrenamiento completado.")
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+	return 0
+}
+
+// Subcomando `mbfl`: entrena la máquina de factorización. En regresión mide la fracción de
+// predicciones a menos de 1 del valor real; con `-objective logistica` la precisión de la
+// clasificación binaria.
+func runMBFL(args []string) int {
+	flags := flag.NewFlagSet("mbfl", flag.ContinueOnError)
+	opts := commonFlags(flags, 1000, 10, 4)
+	factors := flags.Int("factors", 5, "cantidad de factores latentes")
+	objective := flags.String("objective", MBFL.CUADRATICA, "pérdida: cuadratica (regresión) o logistica (clasificación binaria)")
+	solver := flags.String("solver", MBFL.SGD, "método de entrenamiento: sgd o als (solo cuadratica)")
+	training := trainingFlags(flags, 10)
+	optimizer := optimizerFlags(flags, "", 0.01)
+	batch := flags.Int("batch", 0, "ejemplos por paso de SGD (0: 1 con un worker, 64 con varios)")
+	reg := flags.Float64("reg", 0.01, "regularización")
+	sparse := sparseFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	model := func(n int) (*MBFL.FactorizationMachine, error) {
+		lote := *batch
+		if lote <= 0 && *opts.workers > 1 {
+			lote = 64
+		}
+		optimizador, err := optimizer.build(trainingSteps(*training.epochs, n, lote))
+		return &MBFL.FactorizationMachine{
+			NumFactors:     *factors,
+			LearningRate:   *optimizer.rate,
+			Optimizer:      optimizador,
+			Regularization: *reg,
+			Objective:      *objective,
+			Solver:         *solver,
+			BatchSize:      *batch,
+			Epochs:         *training.epochs,
+			Workers:        *opts.workers,
+			Seed:           *opts.seed,
+		}, err
+	}
+	if *sparse.path != "" {
+		return trainSparse("mbfl", sparse, *opts.seed, *objective == MBFL.LOGISTICA, func(train *ML.DatasetDisperso) (ML.ModeloDisperso, error) {
+			return model(train.Len())
+		})
+	}
+
+	fmt.Printf("Creando dataset con %d ejemplos y %d características...\n", *opts.n, *opts.features)
+	dataset := ML.RandomRegression(*opts.n, *opts.features, 1, 10, *opts.seed) // Características en [0, 1]: las interacciones crecen con su producto
+	metric := func(model ML.Model, test *ML.Dataset) float64 { return ML.WithinTolerance(model, test, 1.0) }
+	if *objective == MBFL.LOGISTICA {
+		dataset = ML.LinearClassification(*opts.n, *opts.features, 0.05, *opts.seed)
+		metric = ML.Accuracy
+	}
+	fmt.Println("Dataset creado con éxito.")
+	fm, err := model(*opts.n * 4 / 5) // Pasos sobre el 80% de entrenamiento
+	if err != nil {
+		return fail("mbfl", err)
+	}
+	return trainAndEvaluate("mbfl", fm, &training, dataset, *opts.seed, metric)
+}
+
+// Subcomando `rf`: entrena un bosque aleatorio, con un árbol por goroutine.
+func runRF(args []string) int {
+	flags := flag.NewFlagSet("rf", flag.ContinueOnError)
+	opts := commonFlags(flags, 1000, 10, 4)
+	trees := flags.Int("trees", 10, "cantidad de árboles")
+	depth := flags.Int("depth", 8, "profundidad máxima de cada árbol")
+	linear := flags.Bool("linear", false, "etiquetas separables por un hiperplano en lugar de aleatorias")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	fmt.Printf("Creando dataset con %d ejemplos y %d características...\n", *opts.n, *opts.features)
+	dataset := syntheticClassification(*opts.n, *opts.features, 10, *linear, *opts.seed)
+	fmt.Println("Dataset creado con éxito.")
+
+	bosque := &RandomForests.Bosque{Arboles: *trees, Profundidad: *depth, Workers: *opts.workers, Semilla: *opts.seed}
+	return trainAndEvaluate("rf", bosque, nil, dataset, *opts.seed, ML.Accuracy)
+}
+
+// Subcomando `arbol`: entrena un árbol de decisión sobre un millón de registros con dos
+// características, evaluando las características de cada nodo en paralelo.
+func runArbol(args []string) int {
+	flags := flag.NewFlagSet("arbol", flag.ContinueOnError)
+	opts := commonFlags(flags, 1000000, 2, 4)
+	depth := flags.Int("depth", 3, "profundidad máxima")
+	linear := flags.Bool("linear", false, "etiquetas separables por un hiperplano en lugar de aleatorias")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	fmt.Println("Creando dataset...")
+	dataset := syntheticClassification(*opts.n, *opts.features, 1, *linear, *opts.seed)
+	fmt.Println("Dataset creado con éxito!")
+
+	arbol := &RandomForests.Arbol{Profundidad: *depth, Workers: *opts.workers, Semilla: *opts.seed}
+	return trainAndEvaluate("arbol", arbol, nil, dataset, *opts.seed, ML.Accuracy)
+}
+
+// Genera un dataset de clasificación con etiquetas aleatorias (como los programas
+// originales) o, con `linear`, separables por un hiperplano.
+func syntheticClassification(n, features int, scale float64, linear bool, seed int64) *ML.Dataset {
+	if linear {
+		return ML.LinearClassification(n, features, 0, seed)
+	}
+	return ML.RandomClassification(n, features, scale, seed)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	"pc2/Optimizadores"
+)
+
+// Opciones del optimizador y del programa de la tasa de aprendizaje.
+type optimizerOptions struct {
+	name     *string
+	rate     *float64
+	momentum *float64
+	decay    *float64
+	schedule *string
+	warmup   *int
+}
+
+// Registra las opciones del optimizador; `name` es el optimizador por defecto ("" deja el
+// entrenamiento propio del modelo) y `rate` la tasa inicial por defecto.
+func optimizerFlags(flags *flag.FlagSet, name string, rate float64) optimizerOptions {
+	return optimizerOptions{
+		name:     flags.String("optimizer", name, "optimizador: sgd, momentum, nesterov, adagrad, rmsprop o adam"),
+		rate:     flags.Float64("rate", rate, "tasa de aprendizaje inicial"),
+		momentum: flags.Float64("momentum", 0.9, "momento de momentum y nesterov"),
+		decay:    flags.Float64("decay", 0, "decaimiento de pesos"),
+		schedule: flags.String("schedule", "constante", "programa de la tasa: constante, escalonada (mitad cada tercio), exponencial o coseno (hasta 1/100 de la tasa)"),
+		warmup:   flags.Int("warmup", 0, "pasos de calentamiento lineal de la tasa"),
+	}
+}
+
+// Crea el optimizador elegido para un entrenamiento de `steps` pasos, o nil si no se eligió
+// ninguno.
+func (o optimizerOptions) build(steps int) (Optimizadores.Optimizador, error) {
+	if *o.name == "" {
+		return nil, nil
+	}
+	var programa Optimizadores.Programa
+	switch *o.schedule {
+	case "constante":
+		programa = Optimizadores.Constante(*o.rate)
+	case "escalonada":
+		programa = Optimizadores.Escalonada{Inicial: *o.rate, Factor: 0.5, Cada: (steps + 2) / 3}
+	case "exponencial":
+		programa = Optimizadores.Exponencial{Inicial: *o.rate, Decaimiento: math.Pow(0.01, 1/float64(steps))}
+	case "coseno":
+		programa = Optimizadores.Coseno{Inicial: *o.rate, Minima: *o.rate / 100, Pasos: steps}
+	default:
+		return nil, fmt.Errorf("unknown schedule %q", *o.schedule)
+	}
+	if *o.warmup > 0 {
+		programa = Optimizadores.Calentamiento{Pasos: *o.warmup, Programa: programa}
+	}
+
+	switch *o.name {
+	case "sgd":
+		return &Optimizadores.SGD{Tasa: programa, DecaimientoPesos: *o.decay}, nil
+	case "momentum", "nesterov":
+		return &Optimizadores.SGD{Tasa: programa, Momento: *o.momentum, Nesterov: *o.name == "nesterov", DecaimientoPesos: *o.decay}, nil
+	case "adagrad":
+		return &Optimizadores.AdaGrad{Tasa: programa, DecaimientoPesos: *o.decay}, nil
+	case "rmsprop":
+		return &Optimizadores.RMSProp{Tasa: programa, DecaimientoPesos: *o.decay}, nil
+	case "adam":
+		return &Optimizadores.Adam{Tasa: programa, DecaimientoPesos: *o.decay}, nil
+	}
+	return nil, fmt.Errorf("unknown optimizer %q", *o.name)
+}
+
+// Pasos de un entrenamiento de `epochs` épocas sobre `n` ejemplos en lotes de `batch`.
+func trainingSteps(epochs, n, batch int) int {
+	if batch <= 0 {
+		batch = 1
+	}
+	return epochs * ((n + batch - 1) / batch)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"pc2/ML"
+)
+
+// Opciones de los datos dispersos en archivos de texto libsvm/svmlight o libFM.
+type sparseOptions struct {
+	path  *string
+	libfm *bool
+}
+
+// Registra las opciones de los archivos dispersos.
+func sparseFlags(flags *flag.FlagSet) sparseOptions {
+	return sparseOptions{
+		path:  flags.String("libsvm", "", "archivo libsvm/svmlight con los datos, que se dividen en 80% y 20% (sin validación ni puntos de control)"),
+		libfm: flags.Bool("libfm", false, "los índices del archivo empiezan en 0, como en libFM"),
+	}
+}
+
+// Primer índice de las características según `-libfm`.
+func (o sparseOptions) base() int {
+	if *o.libfm {
+		return ML.LIBFM
+	}
+	return ML.LIBSVM
+}
+
+// Lee el archivo de `-libsvm`, entrena el modelo que crea `build` para el 80% de
+// entrenamiento y muestra la precisión (o el RMSE si `classification` es false) sobre el
+// 20% de prueba.
+func trainSparse(name string, opts sparseOptions, seed int64, classification bool, build func(train *ML.DatasetDisperso) (ML.ModeloDisperso, error)) int {
+	fmt.Printf("Leyendo %s...\n", *opts.path)
+	dataset, err := ML.LoadLibSVM(*opts.path, opts.base())
+	if err != nil {
+		return fail(name, err)
+	}
+	train, test := dataset.Split(0.8, seed)
+	fmt.Printf("Dataset dividido en %d ejemplos de entrenamiento y %d ejemplos de prueba (%d características).\n", train.Len(), test.Len(), dataset.Dimension)
+
+	model, err := build(train)
+	if err != nil {
+		return fail(name, err)
+	}
+	start := time.Now()
+	if err := model.FitDisperso(train); err != nil {
+		return fail(name, err)
+	}
+	fmt.Println("Entrenamiento completado.")
+	if classification {
+		fmt.Printf("Precisión del modelo: %.2f%%\n", ML.AccuracyDisperso(model, test)*100)
+	} else {
+		fmt.Printf("RMSE del modelo: %.4f\n", ML.RMSEDisperso(model, test))
+	}
+	fmt.Printf("Tiempo de ejecución: %s\n", time.Since(start))
+	return 0
+}
+
+// Subcomando `libsvm`: guarda un dataset sintético disperso de clasificación en formato
+// libsvm (o libFM), para entrenar con `-libsvm` en `svm`, `sgd` y `mbfl`.
+func runLibSVM(args []string) int {
+	flags := flag.NewFlagSet("libsvm", flag.ContinueOnError)
+	n := flags.Int("n", 10000, "cantidad de ejemplos")
+	features := flags.Int("features", 1000, "cantidad de características")
+	seed := flags.Int64("seed", 0, "semilla aleatoria (0: hora actual)")
+	nnz := flags.Int("nnz", 20, "características distintas de cero por ejemplo")
+	noise := flags.Float64("noise", 0.05, "fracción de etiquetas invertidas")
+	output := flags.String("o", "datos.svm", "archivo de salida")
+	libfm := flags.Bool("libfm", false, "numerar las características desde 0, como en libFM")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	dataset := ML.SparseClassification(*n, *features, *nnz, *noise, *seed)
+	base := ML.LIBSVM
+	if *libfm {
+		base = ML.LIBFM
+	}
+	if err := ML.SaveLibSVM(*output, dataset, base); err != nil {
+		return fail("libsvm", err)
+	}
+	fmt.Printf("Dataset de %d ejemplos y %d características guardado en %s.\n", dataset.Len(), dataset.Dimension, *output)
+	return 0
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"pc2/ML"
+)
+
+// Opciones del bucle de entrenamiento de los modelos iterativos (ver `ML.Entrenar`).
+type trainingOptions struct {
+	epochs     *int
+	validation *float64
+	patience   *int
+	checkpoint *string
+	every      *int
+	resume     *bool
+	verbose    *bool
+}
+
+// Registra las opciones del bucle de entrenamiento, con `epochs` épocas por defecto.
+func trainingFlags(flags *flag.FlagSet, epochs int) trainingOptions {
+	return trainingOptions{
+		epochs:     flags.Int("epochs", epochs, "épocas de entrenamiento"),
+		validation: flags.Float64("validation", 0, "fracción del entrenamiento reservada para validación"),
+		patience:   flags.Int("patience", 0, "épocas sin mejorar la pérdida de validación antes de detenerse (0: sin parada temprana)"),
+		checkpoint: flags.String("checkpoint", "", "archivo JSON del punto de control"),
+		every:      flags.Int("every", 1, "épocas entre puntos de control"),
+		resume:     flags.Bool("resume", false, "retomar el entrenamiento desde -checkpoint si existe"),
+		verbose:    flags.Bool("v", false, "mostrar la pérdida de cada época"),
+	}
+}
+
+// Entrena `model` con las opciones elegidas. Si se reserva validación, `metric` (con nombre
+// `metricName`) se calcula sobre ella en cada época.
+func (o trainingOptions) fit(model ML.Iterativo, train *ML.Dataset, seed int64, metricName string, metric func(ML.Model, *ML.Dataset) float64) error {
+	config := ML.Entrenamiento{Epocas: *o.epochs}
+	if *o.validation > 0 {
+		train, config.Validacion = train.Split(1-*o.validation, seed)
+		config.Metrica = metric
+	}
+	if *o.verbose || config.Validacion != nil {
+		config.Callbacks = append(config.Callbacks, &ML.Registro{Metrica: metricName})
+	}
+	if *o.patience > 0 {
+		if config.Validacion == nil {
+			return errors.New("-patience needs -validation")
+		}
+		config.Callbacks = append(config.Callbacks, &ML.ParadaTemprana{Paciencia: *o.patience, Restaurar: true})
+	}
+	if *o.checkpoint != "" {
+		config.Callbacks = append(config.Callbacks, &ML.PuntoDeControl{Archivo: *o.checkpoint, Cada: *o.every})
+		if *o.resume {
+			config.Reanudar = *o.checkpoint
+		}
+	} else if *o.resume {
+		return errors.New("-resume needs -checkpoint")
+	}
+	_, err := ML.Entrenar(model, train, config)
+	return err
+}
@@ -0,0 +1,3 @@
+module pc2
+
+go 1.23.0